@@ -4,23 +4,38 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+
 	"github.com/spf13/cobra"
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers/animation"
 )
 
+var animationParams []string
+
 // animationCmd represents the animation command
 var animationCmd = &cobra.Command{
-	Use:       "animation [name]",
-	Short:     "Set the displayed animation",
+	Use:   "animation [name]",
+	Short: "Set the displayed animation",
+	Long: `Set the displayed animation. Parameters such as speed, palette and seed
+depend on the animation; the server lists them at /animations/{name}.`,
+	Example: `  led set animation plasma
+  led set animation plasma --param speed=0.5 --param palette=ocean`,
 	ValidArgs: animation.AnimationStrings(),
 	RunE: func(_ *cobra.Command, args []string) error {
-		return SetAnimation(args[0])
+		params, err := parseAnimationParams(animationParams)
+		if err != nil {
+			return err
+		}
+		return SetAnimation(args[0], params)
 	},
 	Args: cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
 }
 
 func init() {
 	setCmd.AddCommand(animationCmd)
+	animationCmd.Flags().StringArrayVarP(&animationParams, "param", "p", nil, "Animation parameter as key=value, may be repeated")
 
 	// Here you will define your flags and configuration settings.
 
@@ -32,3 +47,16 @@ func init() {
 	// is called directly, e.g.:
 	// animationCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
+
+// parseAnimationParams parses parameters in the form key=value.
+func parseAnimationParams(args []string) (url.Values, error) {
+	params := url.Values{}
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" || key == "name" || key == "transition" {
+			return nil, fmt.Errorf("invalid parameter %q: expected key=value", arg)
+		}
+		params.Set(key, value)
+	}
+	return params, nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,6 +25,9 @@ func do(endpoint string, name string) error {
 	}
 	query := u.Query()
 	query.Set("name", name)
+	if transition != "" {
+		query.Set("transition", transition)
+	}
 	u.RawQuery = query.Encode()
 
 	req, err := http.NewRequest(http.MethodPut, u.String(), nil)
@@ -42,6 +46,43 @@ func do(endpoint string, name string) error {
 	return nil
 }
 
+// send performs a JSON request against an API endpoint. A nil body sends no
+// request body and a nil response discards the response body.
+func send(method, endpoint string, body, response any) error {
+	var payload io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, endpoint, payload)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return decodeAPIError(res)
+	}
+	if response == nil {
+		_, _ = io.Copy(io.Discard, res.Body)
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(response); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
 func hosts() []string {
 	var h []string
 	if selected := viper.GetInt("selectedHost"); selected > 0 {
@@ -62,8 +103,11 @@ func hosts() []string {
 	return h
 }
 
-func SetDashboard(name string) error {
-	return setOnHosts("dashboard", name)
+func SetDashboard(name, palette string) error {
+	if palette == "" {
+		return setOnHosts("dashboard", name)
+	}
+	return setOnHosts("dashboard?"+url.Values{"palette": {palette}}.Encode(), name)
 }
 
 func SetImage(name string) error {
@@ -78,14 +122,22 @@ func SetGIF(name string, once bool) error {
 	return setOnHosts(endpoint, name)
 }
 
-func SetAnimation(name string) error {
-	return setOnHosts("animation", name)
+func SetAnimation(name string, params url.Values) error {
+	return setOnHosts("animation?"+params.Encode(), name)
 }
 
 func setOnHosts(endpoint, name string) error {
+	return runOnHosts(func(h string) error {
+		return do(h+"/"+endpoint, name)
+	})
+}
+
+// runOnHosts runs action for every selected host and prints "<host> OK" for
+// each host on which it succeeded.
+func runOnHosts(action func(host string) error) error {
 	var errs []error
 	for _, h := range hosts() {
-		if err := do(h+"/"+endpoint, name); err != nil {
+		if err := action(h); err != nil {
 			errs = append(errs, fmt.Errorf("%s: %w", h, err))
 			continue
 		}
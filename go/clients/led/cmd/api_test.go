@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -44,6 +46,22 @@ func TestDoEscapesName(t *testing.T) {
 	}
 }
 
+func TestDoSendsTransition(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("transition"); got != "crossfade" {
+			t.Fatalf("unexpected transition: %q", got)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transition = "crossfade"
+	defer func() { transition = "" }()
+	if err := do(server.URL+"/animation", "plasma"); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestDoReturnsStructuredAPIError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -72,3 +90,48 @@ func TestHostsDefaultsToLocalServer(t *testing.T) {
 		t.Fatalf("unexpected hosts: %v", got)
 	}
 }
+
+func TestUploadDetectsAssetType(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	previousHosts := viper.GetStringSlice("hosts")
+	previousSelected := viper.GetInt("selectedHost")
+	t.Cleanup(func() {
+		viper.Set("hosts", previousHosts)
+		viper.Set("selectedHost", previousSelected)
+	})
+	viper.Set("hosts", []string{server.URL})
+	viper.Set("selectedHost", 0)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"logo.png":  "\x89PNG\r\n\x1a\n",
+		"party.gif": "GIF89a",
+		"notes.txt": "hello",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Upload(filepath.Join(dir, "logo.png"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := Upload(filepath.Join(dir, "party.gif"), "parrot"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Upload(filepath.Join(dir, "notes.txt"), ""); err == nil {
+		t.Fatal("text file was uploaded")
+	}
+
+	want := []string{"PUT /images/logo image/png", "PUT /gifs/parrot image/gif"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Fatalf("unexpected requests: got %v, want %v", requests, want)
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+type brightnessResponse struct {
+	Brightness int `json:"brightness"`
+}
+
+var brightnessCmd = &cobra.Command{
+	Use:   "brightness [0-100]",
+	Short: "Get or set the brightness of the server's display",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return printBrightness()
+		}
+		brightness, err := strconv.Atoi(args[0])
+		if err != nil || brightness < 0 || brightness > 100 {
+			return fmt.Errorf("invalid brightness %q: expected a number between 0 and 100", args[0])
+		}
+		return SetBrightness(brightness)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(brightnessCmd)
+}
+
+func SetBrightness(brightness int) error {
+	return runOnHosts(func(h string) error {
+		return send(http.MethodPut, h+"/brightness?value="+strconv.Itoa(brightness), nil, nil)
+	})
+}
+
+func printBrightness() error {
+	var errs []error
+	for _, h := range hosts() {
+		var body brightnessResponse
+		if err := send(http.MethodGet, h+"/brightness", nil, &body); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", h, err))
+			continue
+		}
+		fmt.Printf("%s %d%%\n", h, body.Brightness)
+	}
+	return errors.Join(errs...)
+}
@@ -1,6 +1,11 @@
 package cmd
 
-import "testing"
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
 
 func TestRootCommands(t *testing.T) {
 	if command, _, err := rootCmd.Find([]string{"get"}); err != nil || command != getCmd {
@@ -12,6 +17,18 @@ func TestRootCommands(t *testing.T) {
 	if command, _, err := rootCmd.Find([]string{"display"}); err != nil || command != displayCmd {
 		t.Fatalf("display command not registered: command=%v err=%v", command, err)
 	}
+	if command, _, err := rootCmd.Find([]string{"brightness"}); err != nil || command != brightnessCmd {
+		t.Fatalf("brightness command not registered: command=%v err=%v", command, err)
+	}
+	if command, _, err := rootCmd.Find([]string{"status"}); err != nil || command != statusCmd {
+		t.Fatalf("status command not registered: command=%v err=%v", command, err)
+	}
+	if command, _, err := rootCmd.Find([]string{"notify"}); err != nil || command != notifyCmd {
+		t.Fatalf("notify command not registered: command=%v err=%v", command, err)
+	}
+	if command, _, err := rootCmd.Find([]string{"overlay"}); err != nil || command != overlayCmd {
+		t.Fatalf("overlay command not registered: command=%v err=%v", command, err)
+	}
 
 	for _, oldName := range []string{"list", "show"} {
 		command, _, err := rootCmd.Find([]string{oldName})
@@ -34,3 +51,138 @@ func TestGetAndSetResources(t *testing.T) {
 		}
 	}
 }
+
+func TestPaletteCommands(t *testing.T) {
+	if command, _, err := getCmd.Find([]string{"palette"}); err != nil || command == getCmd {
+		t.Fatalf("get palette command not registered: command=%v err=%v", command, err)
+	}
+	if flag := dashboardCmd.Flags().Lookup("palette"); flag == nil {
+		t.Fatal("set dashboard has no palette flag")
+	}
+}
+
+func TestTextCommand(t *testing.T) {
+	if command, _, err := setCmd.Find([]string{"text"}); err != nil || command != textCmd {
+		t.Fatalf("set text command not registered: command=%v err=%v", command, err)
+	}
+	if command, _, err := getCmd.Find([]string{"font"}); err != nil || command == getCmd {
+		t.Fatalf("get font command not registered: command=%v err=%v", command, err)
+	}
+
+	textOptions.color, textOptions.mode = "ff8800", "marquee"
+	defer func() { textOptions.color, textOptions.mode = "", "" }()
+	if query := textQuery("Meeting in 5 min"); query != "color=ff8800&message=Meeting+in+5+min&mode=marquee" {
+		t.Fatalf("unexpected query: %s", query)
+	}
+}
+
+func TestLayoutCommand(t *testing.T) {
+	if command, _, err := setCmd.Find([]string{"layout"}); err != nil || command != layoutCmd {
+		t.Fatalf("set layout command not registered: command=%v err=%v", command, err)
+	}
+	if command, _, err := getCmd.Find([]string{"layout"}); err != nil || command == getCmd {
+		t.Fatalf("get layout command not registered: command=%v err=%v", command, err)
+	}
+
+	query, err := layoutQuery("split", []string{"left=dashboard:clock", "right=animation:plasma"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "left=dashboard%3Aclock&name=split&right=animation%3Aplasma" {
+		t.Fatalf("unexpected query: %s", query)
+	}
+	for _, invalid := range []string{"left", "=dashboard:clock", "left=clock"} {
+		if _, err := layoutQuery("split", []string{invalid}); err == nil {
+			t.Errorf("%q was accepted", invalid)
+		}
+	}
+}
+
+func TestOverlayCommands(t *testing.T) {
+	for _, name := range []string{"set", "delete"} {
+		command, _, err := overlayCmd.Find([]string{name})
+		if err != nil || command == overlayCmd {
+			t.Errorf("overlay %s command not registered: command=%v err=%v", name, command, err)
+		}
+	}
+
+	overlayOptions.x, overlayOptions.z, overlayOptions.color = 2, 1, "ff0000"
+	defer func() { overlayOptions.x, overlayOptions.z, overlayOptions.color = 0, 0, "" }()
+	body := overlayBody("text", []string{"LIVE"})
+	want := overlay{Type: "text", Content: "LIVE", X: 2, Z: 1, Params: map[string]string{"color": "ff0000"}}
+	if !reflect.DeepEqual(body, want) {
+		t.Fatalf("unexpected overlay: %#v", body)
+	}
+	if body := overlayBody("clock", nil); body.Content != "" || body.Params["color"] != "ff0000" {
+		t.Fatalf("unexpected clock overlay: %#v", body)
+	}
+}
+
+func TestParseAnimationParams(t *testing.T) {
+	params, err := parseAnimationParams([]string{"speed=0.5", "palette=ocean"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Encode() != "palette=ocean&speed=0.5" {
+		t.Fatalf("unexpected params: %s", params.Encode())
+	}
+	for _, invalid := range []string{"speed", "=1", "name=plasma"} {
+		if _, err := parseAnimationParams([]string{invalid}); err == nil {
+			t.Errorf("%q was accepted", invalid)
+		}
+	}
+}
+
+func TestPlaylistCommands(t *testing.T) {
+	for _, name := range []string{"create", "start", "stop"} {
+		command, _, err := playlistCmd.Find([]string{name})
+		if err != nil || command == playlistCmd {
+			t.Errorf("playlist %s command not registered: command=%v err=%v", name, command, err)
+		}
+	}
+}
+
+func TestParsePlaylistEntries(t *testing.T) {
+	entries, err := parsePlaylistEntries([]string{"dashboard:clock:60s", "animation:plasma:2m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0] != (playlistEntry{Type: "dashboard", Name: "clock", Duration: "60s"}) {
+		t.Fatalf("unexpected entries: %#v", entries)
+	}
+
+	for _, invalid := range []string{"dashboard:clock", "dashboard::60s", "animation:plasma:soon"} {
+		if _, err := parsePlaylistEntries([]string{invalid}); err == nil {
+			t.Errorf("%q was accepted", invalid)
+		}
+	}
+}
+
+func TestStatusLines(t *testing.T) {
+	var body displayStateResponse
+	err := json.Unmarshal([]byte(`{"display":{
+		"type":"gif-once","name":"success","temporary":true,"started_at":"2025-06-01T12:00:00Z",
+		"revert_to":{"type":"dashboard","name":"clock"},
+		"last_error":{"type":"image","name":"broken","message":"invalid asset","at":"2025-06-01T11:59:00Z"}
+	}}`), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2025, 6, 1, 12, 0, 5, 0, time.UTC)
+	want := []string{
+		`showing gif-once "success" for 5s (temporary)`,
+		`reverts to dashboard "clock"`,
+		`last error 1m5s ago: image "broken": invalid asset`,
+	}
+	if lines := statusLines(body.Display, now); !reflect.DeepEqual(lines, want) {
+		t.Fatalf("unexpected lines:\n got %q\nwant %q", lines, want)
+	}
+	body.Display.Failed = true
+	if lines := statusLines(body.Display, now); lines[0] != `showing gif-once "success" for 5s (temporary) (failed)` {
+		t.Fatalf("unexpected line for a failed renderer: %q", lines[0])
+	}
+	if lines := statusLines(displayState{}, now); !reflect.DeepEqual(lines, []string{"showing nothing yet"}) {
+		t.Fatalf("unexpected lines for an idle server: %q", lines)
+	}
+}
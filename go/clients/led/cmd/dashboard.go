@@ -5,17 +5,21 @@ import (
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers/dashboard"
 )
 
+var dashboardPalette string
+
 // dashboardCmd represents the dashboard command
 var dashboardCmd = &cobra.Command{
 	Use:       "dashboard [name]",
 	Short:     "Set the displayed dashboard",
+	Example:   `  led set dashboard clock --palette sunset`,
 	ValidArgs: dashboard.DashboardStrings(),
 	RunE: func(_ *cobra.Command, args []string) error {
-		return SetDashboard(args[0])
+		return SetDashboard(args[0], dashboardPalette)
 	},
 	Args: cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
 }
 
 func init() {
 	setCmd.AddCommand(dashboardCmd)
+	dashboardCmd.Flags().StringVar(&dashboardPalette, "palette", "", "Palette the dashboard samples its accent colors from")
 }
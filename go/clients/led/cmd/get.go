@@ -18,6 +18,9 @@ var catalogResources = []catalogResource{
 	{name: "gif", title: "GIFs", endpoint: "/gifs"},
 	{name: "dashboard", title: "Dashboards", endpoint: "/dashboards"},
 	{name: "animation", title: "Animations", endpoint: "/animations"},
+	{name: "font", title: "Fonts", endpoint: "/fonts"},
+	{name: "palette", title: "Palettes", endpoint: "/palettes"},
+	{name: "layout", title: "Layouts", endpoint: "/layouts"},
 }
 
 var getCmd = &cobra.Command{
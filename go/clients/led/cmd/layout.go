@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var layoutCmd = &cobra.Command{
+	Use:   "layout [name] [region=type:name]...",
+	Short: "Show a layout with content in its regions",
+	Example: `  led set layout split left=dashboard:clock right=animation:plasma
+  led set layout ticker top=image:logo`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		query, err := layoutQuery(args[0], args[1:])
+		if err != nil {
+			return err
+		}
+		return runOnHosts(func(h string) error {
+			return send(http.MethodPut, h+"/layout?"+query, nil, nil)
+		})
+	},
+}
+
+func init() {
+	setCmd.AddCommand(layoutCmd)
+}
+
+// layoutQuery encodes the layout name and region assignments in the form
+// region=type:name.
+func layoutQuery(name string, assignments []string) (string, error) {
+	query := url.Values{"name": {name}}
+	for _, assignment := range assignments {
+		region, content, ok := strings.Cut(assignment, "=")
+		if !ok || region == "" || !strings.Contains(content, ":") {
+			return "", fmt.Errorf("invalid region %q: expected region=type:name", assignment)
+		}
+		query.Set(region, content)
+	}
+	if transition != "" {
+		query.Set("transition", transition)
+	}
+	return query.Encode(), nil
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var notifyOptions struct {
+	priority int
+	duration time.Duration
+	ttl      time.Duration
+}
+
+type notificationRequest struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+	Duration string `json:"duration,omitempty"`
+	TTL      string `json:"ttl,omitempty"`
+}
+
+type notification struct {
+	ID       uint64 `json:"id"`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+	Duration string `json:"duration"`
+	Active   bool   `json:"active"`
+}
+
+type notificationsResponse struct {
+	Notifications []notification `json:"notifications"`
+}
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify [image|gif|text] [name]",
+	Short: "Show temporary content on top of the current content",
+	Long: `Show temporary content on top of the current content. Notifications with a
+higher priority interrupt lower ones; the others wait in a queue. The current
+content returns once the queue is empty.
+
+Without arguments, notify lists the active and queued notifications.`,
+	Example: `  led notify gif success --priority 10
+  led notify text "Build failed" --duration 30s
+  led notify image doorbell --duration 5s --ttl 1m`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 && len(args) != 2 {
+			return fmt.Errorf("accepts 0 or 2 arg(s), received %d", len(args))
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return printNotifications()
+		}
+		return Notify(args[0], args[1])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+	notifyCmd.Flags().IntVarP(&notifyOptions.priority, "priority", "p", 0, "Priority of the notification")
+	notifyCmd.Flags().DurationVarP(&notifyOptions.duration, "duration", "d", 0, "How long to show the notification (GIFs play once by default)")
+	notifyCmd.Flags().DurationVar(&notifyOptions.ttl, "ttl", 0, "Drop the notification if it waited longer than this")
+}
+
+func Notify(contentType, name string) error {
+	body := notificationRequest{Type: contentType, Name: name, Priority: notifyOptions.priority}
+	if notifyOptions.duration > 0 {
+		body.Duration = notifyOptions.duration.String()
+	}
+	if notifyOptions.ttl > 0 {
+		body.TTL = notifyOptions.ttl.String()
+	}
+	return runOnHosts(func(h string) error {
+		return send(http.MethodPost, h+"/notifications", body, nil)
+	})
+}
+
+func printNotifications() error {
+	var errs []error
+	for hostIndex, host := range hosts() {
+		if hostIndex > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s\n", host)
+
+		var body notificationsResponse
+		if err := send(http.MethodGet, host+"/notifications", nil, &body); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", host, err))
+			continue
+		}
+		if len(body.Notifications) == 0 {
+			fmt.Println("  (none)")
+			continue
+		}
+		for _, n := range body.Notifications {
+			marker := " "
+			if n.Active {
+				marker = "*"
+			}
+			duration := n.Duration
+			if duration == "" {
+				duration = "once"
+			}
+			fmt.Printf("%s #%d %s %q priority=%d duration=%s\n", marker, n.ID, n.Type, n.Name, n.Priority, duration)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+)
+
+var overlayOptions struct {
+	x, y, z    int
+	font       string
+	color      string
+	background string
+}
+
+type overlay struct {
+	ID      string            `json:"id,omitempty"`
+	Type    string            `json:"type"`
+	Content string            `json:"content"`
+	X       int               `json:"x"`
+	Y       int               `json:"y"`
+	Z       int               `json:"z"`
+	Params  map[string]string `json:"params,omitempty"`
+}
+
+type overlaysResponse struct {
+	Overlays []overlay `json:"overlays"`
+}
+
+var overlayCmd = &cobra.Command{
+	Use:   "overlay",
+	Short: "List the overlays drawn on top of the content",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return printOverlays()
+	},
+}
+
+var overlaySetCmd = &cobra.Command{
+	Use:   "set [id] [text|image|clock] [content]",
+	Short: "Add or replace an overlay",
+	Long: `Add or replace an overlay. The content is the message of text overlays, the
+image name of image overlays and an optional Go time layout of clock
+overlays, such as 15:04:05. Overlays with a higher z are drawn above lower
+ones.`,
+	Example: `  led overlay set live text LIVE --x 2 --y 2 --color ff0000
+  led overlay set time clock --x 40 --z 1
+  led overlay set logo image autodarts --x 48 --y 16`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(_ *cobra.Command, args []string) error {
+		body := overlayBody(args[1], args[2:])
+		return runOnHosts(func(h string) error {
+			return send(http.MethodPut, h+"/overlays/"+url.PathEscape(args[0]), body, nil)
+		})
+	},
+}
+
+var overlayDeleteCmd = &cobra.Command{
+	Use:   "delete [id]",
+	Short: "Remove an overlay",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runOnHosts(func(h string) error {
+			return send(http.MethodDelete, h+"/overlays/"+url.PathEscape(args[0]), nil, nil)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(overlayCmd)
+	overlayCmd.AddCommand(overlaySetCmd)
+	overlayCmd.AddCommand(overlayDeleteCmd)
+	overlaySetCmd.Flags().IntVarP(&overlayOptions.x, "x", "x", 0, "Left edge of the overlay")
+	overlaySetCmd.Flags().IntVarP(&overlayOptions.y, "y", "y", 0, "Top edge of the overlay")
+	overlaySetCmd.Flags().IntVarP(&overlayOptions.z, "z", "z", 0, "Stacking order; higher overlays are drawn on top")
+	overlaySetCmd.Flags().StringVar(&overlayOptions.font, "font", "", "Font of text and clock overlays, see 'led get font'")
+	overlaySetCmd.Flags().StringVar(&overlayOptions.color, "color", "", "Text color as hex, e.g. ff8800")
+	overlaySetCmd.Flags().StringVar(&overlayOptions.background, "background", "", "Background color as hex; transparent by default")
+}
+
+// overlayBody builds the overlay request from the arguments and the flags
+// that were set.
+func overlayBody(overlayType string, content []string) overlay {
+	body := overlay{Type: overlayType, X: overlayOptions.x, Y: overlayOptions.y, Z: overlayOptions.z}
+	if len(content) > 0 {
+		body.Content = content[0]
+	}
+	for key, value := range map[string]string{
+		"font":       overlayOptions.font,
+		"color":      overlayOptions.color,
+		"background": overlayOptions.background,
+	} {
+		if value == "" {
+			continue
+		}
+		if body.Params == nil {
+			body.Params = map[string]string{}
+		}
+		body.Params[key] = value
+	}
+	return body
+}
+
+func printOverlays() error {
+	var errs []error
+	for hostIndex, host := range hosts() {
+		if hostIndex > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s\n", host)
+
+		var body overlaysResponse
+		if err := send(http.MethodGet, host+"/overlays", nil, &body); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", host, err))
+			continue
+		}
+		if len(body.Overlays) == 0 {
+			fmt.Println("  (none)")
+			continue
+		}
+		for _, o := range body.Overlays {
+			fmt.Printf("  %s %s %q at %d,%d z=%d\n", o.ID, o.Type, o.Content, o.X, o.Y, o.Z)
+		}
+	}
+	return errors.Join(errs...)
+}
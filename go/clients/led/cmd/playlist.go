@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	playlistShuffle bool
+	playlistRepeat  bool
+)
+
+type playlistEntry struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Duration string `json:"duration"`
+}
+
+type playlist struct {
+	Name    string          `json:"name"`
+	Entries []playlistEntry `json:"entries"`
+	Shuffle bool            `json:"shuffle"`
+	Repeat  bool            `json:"repeat"`
+}
+
+type playlistsResponse struct {
+	Active    string     `json:"active"`
+	Playlists []playlist `json:"playlists"`
+}
+
+var playlistCmd = &cobra.Command{
+	Use:   "playlist",
+	Short: "List the playlists of the server",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return printPlaylists()
+	},
+}
+
+var playlistCreateCmd = &cobra.Command{
+	Use:   "create [name] [type:name:duration]...",
+	Short: "Create or replace a playlist",
+	Example: `  led playlist create office dashboard:clock:60s dashboard:shopify:30s animation:plasma:2m --repeat
+  led playlist create party gif:celebration:20s image:autodarts:20s --shuffle --repeat`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		entries, err := parsePlaylistEntries(args[1:])
+		if err != nil {
+			return err
+		}
+		body := playlist{Entries: entries, Shuffle: playlistShuffle, Repeat: playlistRepeat}
+		return runOnHosts(func(h string) error {
+			return send(http.MethodPut, h+"/playlists/"+url.PathEscape(args[0]), body, nil)
+		})
+	},
+}
+
+var playlistStartCmd = &cobra.Command{
+	Use:   "start [name]",
+	Short: "Start a playlist",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runOnHosts(func(h string) error {
+			return send(http.MethodPost, h+"/playlists/"+url.PathEscape(args[0])+"/start", nil, nil)
+		})
+	},
+}
+
+var playlistStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running playlist",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runOnHosts(func(h string) error {
+			return send(http.MethodPost, h+"/playlists/stop", nil, nil)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(playlistCmd)
+	playlistCmd.AddCommand(playlistCreateCmd)
+	playlistCmd.AddCommand(playlistStartCmd)
+	playlistCmd.AddCommand(playlistStopCmd)
+	playlistCreateCmd.Flags().BoolVarP(&playlistShuffle, "shuffle", "s", false, "Shuffle the entries on every pass")
+	playlistCreateCmd.Flags().BoolVarP(&playlistRepeat, "repeat", "r", false, "Repeat the playlist until it is stopped")
+}
+
+// parsePlaylistEntries parses entries in the form type:name:duration, for
+// example dashboard:clock:60s.
+func parsePlaylistEntries(args []string) ([]playlistEntry, error) {
+	entries := make([]playlistEntry, 0, len(args))
+	for _, arg := range args {
+		parts := strings.Split(arg, ":")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid entry %q: expected type:name:duration", arg)
+		}
+		if _, err := time.ParseDuration(parts[2]); err != nil {
+			return nil, fmt.Errorf("invalid entry %q: %w", arg, err)
+		}
+		entries = append(entries, playlistEntry{Type: parts[0], Name: parts[1], Duration: parts[2]})
+	}
+	return entries, nil
+}
+
+func printPlaylists() error {
+	configuredHosts := hosts()
+	multipleHosts := len(configuredHosts) > 1
+	var errs []error
+
+	for hostIndex, host := range configuredHosts {
+		if multipleHosts {
+			if hostIndex > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s\n", host)
+		}
+
+		var body playlistsResponse
+		if err := send(http.MethodGet, host+"/playlists", nil, &body); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", host, err))
+			continue
+		}
+		if len(body.Playlists) == 0 {
+			fmt.Println("  (none)")
+			continue
+		}
+		for _, playlist := range body.Playlists {
+			marker := ""
+			if playlist.Name == body.Active {
+				marker = " *"
+			}
+			fmt.Printf("%s%s\n", playlist.Name, marker)
+			for _, entry := range playlist.Entries {
+				fmt.Printf("  %s %s for %s\n", entry.Type, entry.Name, entry.Duration)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -12,6 +12,9 @@ var setCmd = &cobra.Command{
 	Short: "Set the content displayed by the server",
 }
 
+var transition string
+
 func init() {
 	rootCmd.AddCommand(setCmd)
+	setCmd.PersistentFlags().StringVarP(&transition, "transition", "t", "", "Transition from the current content: none, crossfade, wipe, slide or dissolve (defaults to the server setting)")
 }
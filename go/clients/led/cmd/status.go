@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type displayState struct {
+	Type      string     `json:"type"`
+	Name      string     `json:"name"`
+	Temporary bool       `json:"temporary"`
+	Failed    bool       `json:"failed"`
+	StartedAt *time.Time `json:"started_at"`
+	RevertTo  *struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	} `json:"revert_to"`
+	LastError *struct {
+		Type    string    `json:"type"`
+		Name    string    `json:"name"`
+		Message string    `json:"message"`
+		At      time.Time `json:"at"`
+	} `json:"last_error"`
+}
+
+type displayStateResponse struct {
+	Display displayState `json:"display"`
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show what every server is displaying",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return printStatus(time.Now())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
+
+func printStatus(now time.Time) error {
+	var errs []error
+	for hostIndex, host := range hosts() {
+		if hostIndex > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s\n", host)
+
+		var body displayStateResponse
+		if err := send(http.MethodGet, host+"/display", nil, &body); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", host, err))
+			continue
+		}
+		for _, line := range statusLines(body.Display, now) {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func statusLines(state displayState, now time.Time) []string {
+	if state.StartedAt == nil {
+		return []string{"showing nothing yet"}
+	}
+
+	showing := fmt.Sprintf("showing %s %q for %s", state.Type, state.Name, since(*state.StartedAt, now))
+	if state.Temporary {
+		showing += " (temporary)"
+	}
+	if state.Failed {
+		showing += " (failed)"
+	}
+	lines := []string{showing}
+	if state.RevertTo != nil {
+		lines = append(lines, fmt.Sprintf("reverts to %s %q", state.RevertTo.Type, state.RevertTo.Name))
+	}
+	if state.LastError != nil {
+		lines = append(lines, fmt.Sprintf("last error %s ago: %s %q: %s",
+			since(state.LastError.At, now), state.LastError.Type, state.LastError.Name, state.LastError.Message))
+	}
+	return lines
+}
+
+func since(t, now time.Time) time.Duration {
+	return max(now.Sub(t), 0).Round(time.Second)
+}
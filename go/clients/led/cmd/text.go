@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+)
+
+var textOptions struct {
+	font       string
+	color      string
+	background string
+	align      string
+	mode       string
+	speed      string
+}
+
+var textCmd = &cobra.Command{
+	Use:   "text [message]",
+	Short: "Set the displayed text",
+	Example: `  led set text "Meeting in 5 min" --color ff8800
+  led set text "GOAL!" --mode marquee --speed 60 --font 5x7`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return SetText(args[0])
+	},
+}
+
+func init() {
+	setCmd.AddCommand(textCmd)
+	textCmd.Flags().StringVar(&textOptions.font, "font", "", "Font of the text, see 'led get font'")
+	textCmd.Flags().StringVar(&textOptions.color, "color", "", "Text color as hex, e.g. ff8800")
+	textCmd.Flags().StringVar(&textOptions.background, "background", "", "Background color as hex, e.g. 000000")
+	textCmd.Flags().StringVar(&textOptions.align, "align", "", "Alignment of static text: left, center or right")
+	textCmd.Flags().StringVar(&textOptions.mode, "mode", "", "Display mode: static or marquee")
+	textCmd.Flags().StringVar(&textOptions.speed, "speed", "", "Marquee speed in pixels per second")
+}
+
+func SetText(message string) error {
+	query := textQuery(message)
+	return runOnHosts(func(h string) error {
+		return send(http.MethodPut, h+"/text?"+query, nil, nil)
+	})
+}
+
+// textQuery encodes the message and the flags that were set; the server
+// applies its defaults to the rest.
+func textQuery(message string) string {
+	query := url.Values{"message": {message}}
+	for key, value := range map[string]string{
+		"font":       textOptions.font,
+		"color":      textOptions.color,
+		"background": textOptions.background,
+		"align":      textOptions.align,
+		"mode":       textOptions.mode,
+		"speed":      textOptions.speed,
+		"transition": transition,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	return query.Encode()
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// uploadClient allows more time than httpClient because GIFs can be large.
+var uploadClient = &http.Client{Timeout: time.Minute}
+
+var uploadName string
+
+var uploadCmd = &cobra.Command{
+	Use:   "upload [file]",
+	Short: "Upload a PNG image or GIF to the servers",
+	Example: `  led upload logo.png
+  led upload ~/Downloads/party-parrot.gif --name parrot`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return Upload(args[0], uploadName)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uploadCmd)
+	uploadCmd.Flags().StringVarP(&uploadName, "name", "n", "", "Name of the asset (defaults to the file name without extension)")
+}
+
+// Upload sends a PNG or GIF file to every selected host. The asset type is
+// detected from the file content.
+func Upload(path, name string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	endpoint, contentType, err := assetEndpoint(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return runOnHosts(func(h string) error {
+		return upload(h+endpoint+"/"+url.PathEscape(name), contentType, data)
+	})
+}
+
+func assetEndpoint(data []byte) (string, string, error) {
+	switch contentType := http.DetectContentType(data); contentType {
+	case "image/png":
+		return "/images", contentType, nil
+	case "image/gif":
+		return "/gifs", contentType, nil
+	default:
+		return "", "", fmt.Errorf("unsupported file type %s: expected a PNG or GIF", contentType)
+	}
+}
+
+func upload(endpoint, contentType string, data []byte) error {
+	req, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	res, err := uploadClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return decodeAPIError(res)
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+)
+
+type animationResponse struct {
+	Name   string               `json:"name"`
+	Params []animationParamBody `json:"params"`
+}
+
+type animationParamBody struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Default     string   `json:"default"`
+	Min         *float64 `json:"min,omitempty"`
+	Max         *float64 `json:"max,omitempty"`
+	Values      []string `json:"values,omitempty"`
+	Description string   `json:"description"`
+}
+
+// animationSchemaHandler describes the parameters of an animation.
+func animationSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	schema, err := renderers.AnimationSchema(name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "animation_not_found", "animation \""+name+"\" does not exist")
+		return
+	}
+	response := animationResponse{Name: name, Params: []animationParamBody{}}
+	for _, spec := range schema {
+		body := animationParamBody{
+			Name:        spec.Name,
+			Type:        spec.Type.String(),
+			Default:     spec.Default,
+			Values:      spec.Values,
+			Description: spec.Description,
+		}
+		if spec.Type != renderers.ParamEnum {
+			body.Min, body.Max = &spec.Min, &spec.Max
+		}
+		response.Params = append(response.Params, body)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
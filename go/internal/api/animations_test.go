@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+)
+
+func TestAnimationSchema(t *testing.T) {
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{})
+	response := performRequest(handler, http.MethodGet, "/animations/glitch")
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", response.Code)
+	}
+	var body animationResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Name != "glitch" || len(body.Params) != 3 {
+		t.Fatalf("unexpected schema: %#v", body)
+	}
+	speed, palette := body.Params[0], body.Params[1]
+	if speed.Name != "speed" || speed.Type != "float" || speed.Default != "1" || speed.Min == nil || *speed.Max != 10 {
+		t.Fatalf("unexpected speed parameter: %#v", speed)
+	}
+	if palette.Type != "enum" || palette.Min != nil || len(palette.Values) == 0 || palette.Values[0] != renderers.DefaultPalette {
+		t.Fatalf("unexpected palette parameter: %#v", palette)
+	}
+
+	assertAPIError(t, performRequest(handler, http.MethodGet, "/animations/unknown"), http.StatusNotFound, "animation_not_found")
+}
+
+func TestAnimationPassesParams(t *testing.T) {
+	commands := make(chan renderers.Command)
+	handler := newHandler(testServices(commands), catalog{})
+	go func() {
+		command := <-commands
+		if command.Name != "plasma" || len(command.Params) != 2 || command.Params["speed"] != "0.5" || command.Params["palette"] != "ocean" {
+			t.Errorf("unexpected command: %#v", command)
+		}
+		command.Result <- nil
+	}()
+
+	response := performRequest(handler, http.MethodPut, "/animation?name=plasma&speed=0.5&palette=ocean&transition=wipe")
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/fs"
+)
+
+const (
+	maxImageUploadSize = 4 << 20
+	maxGIFUploadSize   = 16 << 20
+)
+
+var assetNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// assetSpec describes a catalog directory that accepts uploads.
+type assetSpec struct {
+	kind      string
+	dir       string
+	extension string
+	maxSize   int64
+	decode    func(io.Reader) error
+}
+
+func imageAssets(c catalog) assetSpec {
+	return assetSpec{
+		kind: "image", dir: c.imagesDir, extension: ".png", maxSize: maxImageUploadSize,
+		decode: func(r io.Reader) error {
+			_, err := fs.DecodePNG(r)
+			return err
+		},
+	}
+}
+
+func gifAssets(c catalog) assetSpec {
+	return assetSpec{
+		kind: "gif", dir: c.gifsDir, extension: ".gif", maxSize: maxGIFUploadSize,
+		decode: func(r io.Reader) error {
+			_, err := fs.DecodeGIF(r)
+			return err
+		},
+	}
+}
+
+type assetResponse struct {
+	Name     string `json:"name"`
+	Replaced bool   `json:"replaced"`
+}
+
+// uploadAssetHandler stores the request body as an asset after it decoded
+// successfully. Replacing an asset does not affect content that is already
+// displayed.
+func uploadAssetHandler(spec assetSpec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if !assetNamePattern.MatchString(name) {
+			writeInvalidAssetName(w)
+			return
+		}
+
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, spec.maxSize))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "asset_too_large", spec.kind+" uploads are limited to "+formatSize(spec.maxSize))
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_upload", "the upload could not be read")
+			return
+		}
+		if err := spec.decode(bytes.NewReader(data)); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "invalid_"+spec.kind, "the upload is not a valid "+strings.TrimPrefix(spec.extension, ".")+": "+err.Error())
+			return
+		}
+
+		path, exists, err := assetPath(spec, name)
+		if err == nil {
+			err = fs.WriteFileAtomic(path, data, 0o644)
+		}
+		if err != nil {
+			log.Printf("upload %s %q: %v", spec.kind, name, err)
+			writeError(w, http.StatusInternalServerError, "asset_failed", "the "+spec.kind+" could not be stored")
+			return
+		}
+
+		log.Printf("asset uploaded: type=%s name=%q size=%d replaced=%t", spec.kind, name, len(data), exists)
+		status := http.StatusCreated
+		if exists {
+			status = http.StatusOK
+		}
+		writeJSON(w, status, assetResponse{Name: name, Replaced: exists})
+	}
+}
+
+// deleteAssetHandler removes an asset of the catalog. Names are looked up in
+// the catalog listing rather than checked against the upload pattern, so
+// that assets copied into the directory by hand can be deleted as well.
+func deleteAssetHandler(spec assetSpec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		names, err := assetNames(spec.dir, spec.extension)
+		if err == nil && !contains(names, name) {
+			writeError(w, http.StatusNotFound, spec.kind+"_not_found", spec.kind+" \""+name+"\" does not exist")
+			return
+		}
+
+		var path string
+		if err == nil {
+			path, _, err = assetPath(spec, name)
+		}
+		if err == nil {
+			err = os.Remove(path)
+		}
+		if err != nil {
+			log.Printf("delete %s %q: %v", spec.kind, name, err)
+			writeError(w, http.StatusInternalServerError, "asset_failed", "the "+spec.kind+" could not be deleted")
+			return
+		}
+		log.Printf("asset deleted: type=%s name=%q", spec.kind, name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// assetPath returns the file of the named asset. Like the catalog, it
+// matches the extension case-insensitively; new assets use the lower-case
+// extension.
+func assetPath(spec assetSpec, name string) (string, bool, error) {
+	entries, err := os.ReadDir(spec.dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", false, err
+	}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if !entry.IsDir() && strings.EqualFold(ext, spec.extension) && strings.TrimSuffix(entry.Name(), ext) == name {
+			return filepath.Join(spec.dir, entry.Name()), true, nil
+		}
+	}
+	return filepath.Join(spec.dir, name+spec.extension), false, nil
+}
+
+func formatSize(size int64) string {
+	return strconv.FormatInt(size>>20, 10) + " MiB"
+}
+
+func writeInvalidAssetName(w http.ResponseWriter) {
+	writeError(w, http.StatusBadRequest, "invalid_name", "asset names may only contain letters, digits, '-' and '_' and must be at most 64 characters long")
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+)
+
+func TestUploadAndDeleteImage(t *testing.T) {
+	images := filepath.Join(t.TempDir(), "pngs")
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{imagesDir: images})
+
+	response := performUpload(handler, http.MethodPut, "/images/logo", encodeTestPNG(t))
+	if response.Code != http.StatusCreated {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	assertCatalog(t, handler, "/images", []string{"logo"})
+
+	response = performUpload(handler, http.MethodPut, "/images/logo", encodeTestPNG(t))
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status for a replacement: %d", response.Code)
+	}
+	if entries, _ := os.ReadDir(images); len(entries) != 1 {
+		t.Fatalf("temporary files were left behind: %v", entries)
+	}
+
+	response = performRequest(handler, http.MethodDelete, "/images/logo")
+	if response.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	assertCatalog(t, handler, "/images", []string{})
+
+	response = performRequest(handler, http.MethodDelete, "/images/logo")
+	assertAPIError(t, response, http.StatusNotFound, "image_not_found")
+}
+
+func TestDeleteAssetOutsideUploadPattern(t *testing.T) {
+	images := t.TempDir()
+	if err := os.WriteFile(filepath.Join(images, "old logo.PNG"), encodeTestPNG(t), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{imagesDir: images})
+
+	response := performRequest(handler, http.MethodDelete, "/images/old%20logo")
+	if response.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	assertCatalog(t, handler, "/images", []string{})
+
+	response = performRequest(handler, http.MethodDelete, "/images/..%2Flogo")
+	assertAPIError(t, response, http.StatusNotFound, "image_not_found")
+}
+
+func TestUploadGIF(t *testing.T) {
+	gifs := t.TempDir()
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{gifsDir: gifs})
+
+	var body bytes.Buffer
+	frame := image.NewPaletted(image.Rect(0, 0, 4, 4), color.Palette{color.Black, color.White})
+	if err := gif.EncodeAll(&body, &gif.GIF{Image: []*image.Paletted{frame}, Delay: []int{10}}); err != nil {
+		t.Fatal(err)
+	}
+
+	response := performUpload(handler, http.MethodPut, "/gifs/party", body.Bytes())
+	if response.Code != http.StatusCreated {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	assertCatalog(t, handler, "/gifs", []string{"party"})
+
+	response = performUpload(handler, http.MethodPut, "/gifs/broken", encodeTestPNG(t))
+	assertAPIError(t, response, http.StatusUnprocessableEntity, "invalid_gif")
+}
+
+func TestUploadValidation(t *testing.T) {
+	images := t.TempDir()
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{imagesDir: images})
+
+	tests := []struct {
+		name      string
+		path      string
+		body      []byte
+		status    int
+		errorCode string
+	}{
+		{name: "not a png", path: "/images/logo", body: []byte("not a png"), status: http.StatusUnprocessableEntity, errorCode: "invalid_image"},
+		{name: "too large", path: "/images/logo", body: make([]byte, maxImageUploadSize+1), status: http.StatusRequestEntityTooLarge, errorCode: "asset_too_large"},
+		{name: "hidden name", path: "/images/.logo", body: encodeTestPNG(t), status: http.StatusBadRequest, errorCode: "invalid_name"},
+		{name: "escaped path", path: "/images/..%2Flogo", body: encodeTestPNG(t), status: http.StatusBadRequest, errorCode: "invalid_name"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := performUpload(handler, http.MethodPut, test.path, test.body)
+			assertAPIError(t, response, test.status, test.errorCode)
+		})
+	}
+	if entries, _ := os.ReadDir(images); len(entries) != 0 {
+		t.Fatalf("rejected uploads were stored: %v", entries)
+	}
+}
+
+func performUpload(handler http.Handler, method, path string, body []byte) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, bytes.NewReader(body))
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	return response
+}
+
+func encodeTestPNG(t *testing.T) []byte {
+	t.Helper()
+	var body bytes.Buffer
+	if err := png.Encode(&body, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	return body.Bytes()
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+type brightnessResponse struct {
+	Brightness int `json:"brightness"`
+}
+
+func getBrightnessHandler(matrix rgbmatrix.Dimmable) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if matrix == nil {
+			writeBrightnessUnavailable(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, brightnessResponse{Brightness: matrix.Brightness()})
+	}
+}
+
+func setBrightnessHandler(matrix rgbmatrix.Dimmable) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if matrix == nil {
+			writeBrightnessUnavailable(w)
+			return
+		}
+
+		value := strings.TrimSpace(r.URL.Query().Get("value"))
+		brightness, err := strconv.Atoi(value)
+		if err != nil || brightness < 0 || brightness > 100 {
+			writeError(w, http.StatusBadRequest, "invalid_brightness", "query parameter \"value\" must be a number between 0 and 100")
+			return
+		}
+		if err := matrix.SetBrightness(brightness); err != nil {
+			log.Printf("set brightness %d: %v", brightness, err)
+			writeError(w, http.StatusInternalServerError, "brightness_failed", "the brightness could not be changed")
+			return
+		}
+		log.Printf("brightness changed: brightness=%d", brightness)
+		writeJSON(w, http.StatusOK, brightnessResponse{Brightness: matrix.Brightness()})
+	}
+}
+
+func writeBrightnessUnavailable(w http.ResponseWriter) {
+	writeError(w, http.StatusServiceUnavailable, "brightness_unavailable", "the brightness cannot be changed on this server")
+}
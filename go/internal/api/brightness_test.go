@@ -0,0 +1,45 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestBrightnessEndpoints(t *testing.T) {
+	matrix := rgbmatrix.NewMemory(1, 1)
+	services := testServices(make(chan renderers.Command))
+	services.Brightness = matrix
+	handler := newHandler(services, catalog{})
+
+	response := performRequest(handler, http.MethodPut, "/brightness?value=40")
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	if matrix.Brightness() != 40 {
+		t.Fatalf("brightness was not applied: %d", matrix.Brightness())
+	}
+
+	response = performRequest(handler, http.MethodGet, "/brightness")
+	var body brightnessResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Brightness != 40 {
+		t.Fatalf("unexpected brightness: %d", body.Brightness)
+	}
+
+	for _, path := range []string{"/brightness", "/brightness?value=101", "/brightness?value=dim"} {
+		response := performRequest(handler, http.MethodPut, path)
+		assertAPIError(t, response, http.StatusBadRequest, "invalid_brightness")
+	}
+}
+
+func TestBrightnessUnavailable(t *testing.T) {
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{})
+	response := performRequest(handler, http.MethodGet, "/brightness")
+	assertAPIError(t, response, http.StatusServiceUnavailable, "brightness_unavailable")
+}
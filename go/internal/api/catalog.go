@@ -2,24 +2,31 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/palette"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers/animation"
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers/dashboard"
 )
 
 type catalog struct {
-	imagesDir string
-	gifsDir   string
+	imagesDir   string
+	gifsDir     string
+	fontsDir    string
+	palettesDir string
 }
 
 func defaultCatalog() catalog {
 	return catalog{
-		imagesDir: "images/pngs",
-		gifsDir:   "images/gifs",
+		imagesDir:   renderers.ImagesDir,
+		gifsDir:     renderers.GIFsDir,
+		fontsDir:    renderers.FontsDir,
+		palettesDir: renderers.PalettesDir,
 	}
 }
 
@@ -31,6 +38,15 @@ func (c catalog) gifs() ([]string, error) {
 	return assetNames(c.gifsDir, ".gif")
 }
 
+func (c catalog) fonts() ([]string, error) {
+	return assetNames(c.fontsDir, ".bdf")
+}
+
+// palettes returns the built-in palettes and the imported palette files.
+func (c catalog) palettes() ([]string, error) {
+	return palette.Names(c.palettesDir)
+}
+
 func (c catalog) dashboards() []string {
 	items := dashboard.DashboardStrings()
 	sort.Strings(items)
@@ -43,6 +59,22 @@ func (c catalog) animations() []string {
 	return items
 }
 
+// content returns the names that can be shown with the given renderer type.
+func (c catalog) content(t renderers.ScreenType) ([]string, error) {
+	switch t {
+	case renderers.TypeImage:
+		return c.images()
+	case renderers.TypeGIF, renderers.TypeGIFOnce:
+		return c.gifs()
+	case renderers.TypeDashboard:
+		return c.dashboards(), nil
+	case renderers.TypeAnimation:
+		return c.animations(), nil
+	default:
+		return nil, fmt.Errorf("no catalog for renderer type %s", t)
+	}
+}
+
 func contains(items []string, name string) bool {
 	index := sort.SearchStrings(items, name)
 	return index < len(items) && items[index] == name
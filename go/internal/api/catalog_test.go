@@ -7,9 +7,10 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"testing"
 
-	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/display"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/palette"
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
 )
 
@@ -34,22 +35,36 @@ func TestAssetCatalogEndpoints(t *testing.T) {
 		}
 	}
 
-	handler := newHandler(make(chan renderers.Command), catalog{imagesDir: images, gifsDir: gifs}, display.NewHub())
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{imagesDir: images, gifsDir: gifs})
 	assertCatalog(t, handler, "/images", []string{"alpha", "zebra"})
 	assertCatalog(t, handler, "/gifs", []string{"party"})
 }
 
 func TestMissingAssetDirectoryReturnsEmptyCatalog(t *testing.T) {
-	handler := newHandler(make(chan renderers.Command), catalog{
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{
 		imagesDir: filepath.Join(t.TempDir(), "missing"),
 		gifsDir:   filepath.Join(t.TempDir(), "missing"),
-	}, display.NewHub())
+	})
 	assertCatalog(t, handler, "/images", []string{})
 	assertCatalog(t, handler, "/gifs", []string{})
 }
 
+func TestPaletteCatalog(t *testing.T) {
+	palettes := t.TempDir()
+	for _, name := range []string{"brand.gpl", "swatches.ase", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(palettes, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{palettesDir: palettes})
+	want := append(palette.BuiltinNames(), "brand", "swatches")
+	sort.Strings(want)
+	assertCatalog(t, handler, "/palettes", want)
+}
+
 func TestRendererCatalogEndpoints(t *testing.T) {
-	handler := newHandler(make(chan renderers.Command), catalog{}, display.NewHub())
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{})
 
 	request := httptest.NewRequest(http.MethodGet, "/dashboards", nil)
 	response := httptest.NewRecorder()
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+const maxColorBodySize = 4 << 10
+
+type colorResponse struct {
+	Color rgbmatrix.ColorCorrection `json:"color"`
+}
+
+func getColorHandler(matrix rgbmatrix.Correctable) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if matrix == nil {
+			writeColorUnavailable(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, colorResponse{Color: matrix.ColorCorrection()})
+	}
+}
+
+// setColorHandler changes the fields of the color correction that are in
+// the request body and keeps the others.
+func setColorHandler(matrix rgbmatrix.Correctable) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if matrix == nil {
+			writeColorUnavailable(w)
+			return
+		}
+
+		correction := matrix.ColorCorrection()
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxColorBodySize))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&correction); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_json", "the request body is not a valid color correction")
+			return
+		}
+		if err := matrix.SetColorCorrection(correction); err != nil {
+			if errors.Is(err, rgbmatrix.ErrInvalidColorCorrection) {
+				writeError(w, http.StatusBadRequest, "invalid_color_correction", err.Error())
+				return
+			}
+			log.Printf("set color correction: %v", err)
+			writeError(w, http.StatusInternalServerError, "color_correction_failed", "the color correction could not be changed")
+			return
+		}
+		log.Printf("color correction changed: gamma=%g temperature=%d lut=%q", correction.Gamma, correction.Temperature, correction.LUT)
+		writeJSON(w, http.StatusOK, colorResponse{Color: matrix.ColorCorrection()})
+	}
+}
+
+func writeColorUnavailable(w http.ResponseWriter) {
+	writeError(w, http.StatusServiceUnavailable, "color_unavailable", "the colors cannot be corrected on this server")
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestColorEndpoints(t *testing.T) {
+	corrector, err := rgbmatrix.NewCorrector(rgbmatrix.NewMemory(1, 1), rgbmatrix.DefaultColorCorrection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	services := testServices(make(chan renderers.Command))
+	services.Color = corrector
+	handler := newHandler(services, catalog{})
+
+	response := performJSONRequest(handler, http.MethodPut, "/color", `{"gamma":2.2,"gain":{"red":1,"green":1,"blue":0.8}}`)
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	if correction := corrector.ColorCorrection(); correction.Gamma != 2.2 || correction.Gain.Blue != 0.8 || correction.Temperature != 6500 {
+		t.Fatalf("color correction was not applied: %+v", correction)
+	}
+
+	response = performJSONRequest(handler, http.MethodPut, "/color", `{"temperature":4000}`)
+	var body colorResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Color.Temperature != 4000 || body.Color.Gamma != 2.2 {
+		t.Fatalf("unexpected color correction: %+v", body.Color)
+	}
+
+	response = performRequest(handler, http.MethodGet, "/color")
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Color.Temperature != 4000 {
+		t.Fatalf("unexpected color correction: %+v", body.Color)
+	}
+
+	for _, request := range []string{`{"gamma":0}`, `{"lut":"../config.toml"}`, `{"lut":"missing.cube"}`} {
+		response := performJSONRequest(handler, http.MethodPut, "/color", request)
+		assertAPIError(t, response, http.StatusBadRequest, "invalid_color_correction")
+	}
+	response = performJSONRequest(handler, http.MethodPut, "/color", `{"saturation":2}`)
+	assertAPIError(t, response, http.StatusBadRequest, "invalid_json")
+	if corrector.ColorCorrection().Temperature != 4000 {
+		t.Fatal("invalid requests changed the color correction")
+	}
+}
+
+func TestColorUnavailable(t *testing.T) {
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{})
+	response := performRequest(handler, http.MethodGet, "/color")
+	assertAPIError(t, response, http.StatusServiceUnavailable, "color_unavailable")
+}
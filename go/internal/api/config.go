@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+type reloadResponse struct {
+	// Applied are the changed config keys that took effect.
+	Applied []string `json:"applied"`
+	// RestartRequired are the changed config keys that take effect when the
+	// server restarts.
+	RestartRequired []string `json:"restart_required"`
+	// Restarted is true if the shown renderer was restarted.
+	Restarted bool `json:"restarted"`
+}
+
+func reloadConfigHandler(reloader *renderers.Reloader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if reloader == nil {
+			writeError(w, http.StatusServiceUnavailable, "reload_unavailable", "the config cannot be reloaded on this server")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+		result, err := reloader.Reload(ctx)
+		var invalid *rgbmatrix.ValidationError
+		switch {
+		case err == nil:
+		case errors.As(err, &invalid):
+			writeError(w, http.StatusBadRequest, "invalid_config", err.Error())
+			return
+		case errors.Is(err, context.DeadlineExceeded):
+			writeError(w, http.StatusGatewayTimeout, "reload_timeout", "the renderer did not take the config in time")
+			return
+		case errors.Is(err, context.Canceled):
+			writeError(w, http.StatusRequestTimeout, "request_cancelled", "the request was cancelled")
+			return
+		default:
+			log.Printf("reload config: %v", err)
+			writeError(w, http.StatusInternalServerError, "config_reload_failed", "the config could not be reloaded")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, reloadResponse{
+			Applied:         append([]string{}, result.Applied...),
+			RestartRequired: append([]string{}, result.Restart...),
+			Restarted:       result.Restarted,
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestReloadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("[options]\nrows = 32\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := rgbmatrix.LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrector, err := rgbmatrix.NewCorrector(rgbmatrix.NewMemory(1, 1), config.Color)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloader := renderers.NewReloader(path, config, nil, corrector)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for {
+			select {
+			case reload := <-reloader.Reloads():
+				reload.Result <- false
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	services := testServices(make(chan renderers.Command))
+	services.Reloader = reloader
+	handler := newHandler(services, catalog{})
+
+	if err := os.WriteFile(path, []byte("[options]\nrows = 64\n[color]\ngamma = 2.2\n[dashboards]\nfont = \"assets/fonts/6x10.bdf\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	response := performRequest(handler, http.MethodPost, "/config/reload")
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	var body reloadResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	want := reloadResponse{
+		Applied:         []string{"dashboards.font", "color.gamma"},
+		RestartRequired: []string{"options.rows"},
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Fatalf("unexpected response: got %+v, want %+v", body, want)
+	}
+	if gamma := corrector.ColorCorrection().Gamma; gamma != 2.2 {
+		t.Fatalf("color correction was not applied: gamma %g", gamma)
+	}
+
+	if err := os.WriteFile(path, []byte("[color]\ngamma = 0\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	response = performRequest(handler, http.MethodPost, "/config/reload")
+	assertAPIError(t, response, http.StatusBadRequest, "invalid_config")
+	if gamma := corrector.ColorCorrection().Gamma; gamma != 2.2 {
+		t.Fatalf("an invalid config changed the color correction: gamma %g", gamma)
+	}
+}
+
+func TestReloadConfigUnavailable(t *testing.T) {
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{})
+	response := performRequest(handler, http.MethodPost, "/config/reload")
+	assertAPIError(t, response, http.StatusServiceUnavailable, "reload_unavailable")
+}
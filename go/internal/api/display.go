@@ -0,0 +1,208 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+)
+
+const maxDisplayBodySize = 16 << 10
+
+// displayRequest is content to show. Temporary content is shown for its
+// duration on top of the persistent content, which it then reverts to.
+type displayRequest struct {
+	Type       string            `json:"type"`
+	Name       string            `json:"name"`
+	Params     map[string]string `json:"params"`
+	Duration   string            `json:"duration"`
+	Transition string            `json:"transition"`
+	Temporary  bool              `json:"temporary"`
+}
+
+// displayHandler shows any content described by a JSON body.
+func displayHandler(services Services, catalog catalog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body displayRequest
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxDisplayBodySize))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_json", "the request body is not a valid display command")
+			return
+		}
+		showContent(w, r, services, catalog, body)
+	}
+}
+
+// commandHandler shows the content of one type named by the query. Further
+// query parameters besides transition are passed to dashboards and
+// animations, for example speed=0.5.
+func commandHandler(services Services, catalog catalog, screenType renderers.ScreenType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		name := strings.TrimSpace(query.Get("name"))
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "missing_name", "query parameter \"name\" is required")
+			return
+		}
+		request := displayRequest{Type: screenType.String(), Name: name, Transition: query.Get("transition")}
+		if screenType == renderers.TypeDashboard || screenType == renderers.TypeAnimation {
+			request.Params = commandParams(query)
+		}
+		command, ok := parseDisplayRequest(w, services, catalog, request)
+		if !ok || !sendCommand(w, r, services, contentKind(command.Type), command) {
+			return
+		}
+		// The routes respond with the kind of content like before PUT
+		// /display existed, so that gif-once content is a temporary gif.
+		body := newDisplayCommand(command)
+		body.Type = contentKind(command.Type)
+		writeJSON(w, http.StatusOK, displayResponse{Display: body})
+	}
+}
+
+// showContent validates a display request and responds once its renderer
+// started or failed. PUT /text and PUT /layout are thin wrappers around it.
+func showContent(w http.ResponseWriter, r *http.Request, services Services, catalog catalog, request displayRequest) {
+	command, ok := parseDisplayRequest(w, services, catalog, request)
+	if !ok {
+		return
+	}
+	if !sendCommand(w, r, services, contentKind(command.Type), command) {
+		return
+	}
+	writeJSON(w, http.StatusOK, displayResponse{Display: newDisplayCommand(command)})
+}
+
+// parseDisplayRequest validates a display request and returns its command.
+// It writes an error response and returns false if the request is invalid.
+func parseDisplayRequest(w http.ResponseWriter, services Services, catalog catalog, request displayRequest) (renderers.Command, bool) {
+	screenType, err := renderers.ParseScreenType(request.Type)
+	if err != nil || screenType == renderers.TypePlayground {
+		writeError(w, http.StatusBadRequest, "invalid_type", "field \"type\" must be one of image, gif, gif-once, dashboard, animation, text, layout or blank")
+		return renderers.Command{}, false
+	}
+	command := renderers.Command{
+		Type:        screenType,
+		Name:        strings.TrimSpace(request.Name),
+		Params:      request.Params,
+		IsTemporary: request.Temporary || screenType == renderers.TypeGIFOnce,
+	}
+	if command.Name == "" && screenType != renderers.TypeBlank {
+		writeError(w, http.StatusBadRequest, "missing_name", "field \"name\" is required")
+		return renderers.Command{}, false
+	}
+	var ok bool
+	if command.Transition, ok = parseTransition(w, request.Transition); !ok {
+		return renderers.Command{}, false
+	}
+	if request.Duration != "" {
+		duration, err := time.ParseDuration(request.Duration)
+		switch {
+		case err != nil || duration <= 0:
+			writeError(w, http.StatusBadRequest, "invalid_duration", "field \"duration\" must be a positive duration such as \"30s\"")
+			return renderers.Command{}, false
+		case !command.IsTemporary:
+			writeError(w, http.StatusBadRequest, "invalid_duration", "only temporary content is shown for a duration")
+			return renderers.Command{}, false
+		case screenType == renderers.TypeGIFOnce:
+			writeError(w, http.StatusBadRequest, "invalid_duration", "gif-once plays the GIF once and takes no duration")
+			return renderers.Command{}, false
+		}
+		command.Duration = duration
+	}
+
+	if err := validateContent(services, catalog, contentKind(screenType), command); err != nil {
+		writeContentError(w, err)
+		return renderers.Command{}, false
+	}
+	return command, true
+}
+
+func newDisplayCommand(command renderers.Command) displayCommand {
+	body := displayCommand{Type: command.Type.String(), Name: command.Name, Temporary: command.IsTemporary}
+	if command.IsTemporary {
+		body.Duration = formatDuration(shownFor(command))
+	}
+	return body
+}
+
+// contentKind names content in error codes such as gif_not_found.
+func contentKind(screenType renderers.ScreenType) string {
+	if screenType == renderers.TypeGIFOnce {
+		return renderers.TypeGIF.String()
+	}
+	return screenType.String()
+}
+
+// contentError is content that cannot be shown, with the status and the
+// code of its error response.
+type contentError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *contentError) Error() string {
+	return e.message
+}
+
+func writeContentError(w http.ResponseWriter, err *contentError) {
+	writeError(w, err.status, err.code, err.message)
+}
+
+// validateContent checks that the content of a command exists and that its
+// parameters are valid.
+func validateContent(services Services, catalog catalog, kind string, command renderers.Command) *contentError {
+	switch command.Type {
+	case renderers.TypeText:
+		return validateTextParams(command.Params, catalog)
+	case renderers.TypeLayout:
+		return validateLayout(services, catalog, command.Name, command.Params)
+	case renderers.TypeAnimation:
+		_, err := renderers.ParseAnimationParams(command.Name, command.Params)
+		switch {
+		case errors.Is(err, renderers.ErrUnknownContent):
+			return &contentError{http.StatusNotFound, "animation_not_found", "animation \"" + command.Name + "\" does not exist"}
+		case err != nil:
+			return &contentError{http.StatusBadRequest, "invalid_parameter", err.Error()}
+		}
+		return nil
+	case renderers.TypeBlank:
+		if command.Name != "" || len(command.Params) > 0 {
+			return &contentError{http.StatusBadRequest, "invalid_parameter", "blank content has no name and no parameters"}
+		}
+		return nil
+	case renderers.TypeImage, renderers.TypeGIF, renderers.TypeGIFOnce:
+		if len(command.Params) > 0 {
+			return &contentError{http.StatusBadRequest, "invalid_parameter", kind + " content has no parameters"}
+		}
+	}
+
+	if err := validateCatalogContent(catalog, command.Type, kind, command.Name); err != nil {
+		return err
+	}
+	if command.Type == renderers.TypeDashboard {
+		if err := renderers.ValidateDashboardParams(command.Params); err != nil {
+			return &contentError{http.StatusBadRequest, "invalid_parameter", err.Error()}
+		}
+	}
+	return nil
+}
+
+// validateCatalogContent checks that the catalog of type t lists name.
+func validateCatalogContent(catalog catalog, t renderers.ScreenType, kind, name string) *contentError {
+	items, err := catalog.content(t)
+	if err != nil {
+		log.Printf("validate %s %q: %v", kind, name, err)
+		return &contentError{http.StatusInternalServerError, "catalog_failed", "the catalog could not be loaded"}
+	}
+	if !contains(items, name) {
+		return &contentError{http.StatusNotFound, kind + "_not_found", kind + " \"" + name + "\" does not exist"}
+	}
+	return nil
+}
@@ -89,6 +89,9 @@ func displayStreamHandler(hub *display.Hub) http.HandlerFunc {
 			case <-disconnected:
 				return
 			case <-r.Context().Done():
+				// The server shuts down.
+				message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+				_ = connection.WriteControl(websocket.CloseMessage, message, time.Now().Add(streamWriteTimeout))
 				return
 			}
 		}
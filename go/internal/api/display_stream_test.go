@@ -16,7 +16,7 @@ func TestDisplayStreamSendsMetadataThenLatestFrame(t *testing.T) {
 	hub := display.NewHub()
 	pixels := []byte{1, 2, 3, 4, 5, 6}
 	hub.Publish(display.Frame{Width: 2, Height: 1, Pixels: pixels})
-	server := httptest.NewServer(newHandler(make(chan renderers.Command), catalog{}, hub))
+	server := httptest.NewServer(newHandler(Services{Commands: make(chan renderers.Command), Frames: hub}, catalog{}))
 	defer server.Close()
 
 	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/display/stream"
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestDisplayShowsTemporaryContent(t *testing.T) {
+	commands := make(chan renderers.Command)
+	handler := newHandler(testServices(commands), catalog{})
+	go func() {
+		command := <-commands
+		if command.Type != renderers.TypeDashboard || command.Name != "clock" || command.Params["palette"] != "sunset" ||
+			!command.IsTemporary || command.Duration != 30*time.Second || command.Transition != rgbmatrix.TransitionWipe {
+			t.Errorf("unexpected command: %#v", command)
+		}
+		command.Result <- nil
+	}()
+
+	response := performJSONRequest(handler, http.MethodPut, "/display",
+		`{"type":"dashboard","name":"clock","params":{"palette":"sunset"},"duration":"30s","transition":"wipe","temporary":true}`)
+
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	var body displayResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	want := displayCommand{Type: "dashboard", Name: "clock", Temporary: true, Duration: "30s"}
+	if body.Display != want {
+		t.Fatalf("unexpected response: %#v", body.Display)
+	}
+}
+
+func TestDisplayValidation(t *testing.T) {
+	services := testServices(make(chan renderers.Command))
+	services.Layouts = testLayouts()
+	handler := newHandler(services, catalog{})
+	tests := []struct {
+		name      string
+		body      string
+		status    int
+		errorCode string
+	}{
+		{name: "invalid json", body: `{"type":"animation","name":"plasma","speed":2}`, status: http.StatusBadRequest, errorCode: "invalid_json"},
+		{name: "unknown type", body: `{"type":"video","name":"intro"}`, status: http.StatusBadRequest, errorCode: "invalid_type"},
+		{name: "missing name", body: `{"type":"animation"}`, status: http.StatusBadRequest, errorCode: "missing_name"},
+		{name: "unknown transition", body: `{"type":"animation","name":"plasma","transition":"spin"}`, status: http.StatusBadRequest, errorCode: "invalid_transition"},
+		{name: "invalid duration", body: `{"type":"animation","name":"plasma","duration":"soon","temporary":true}`, status: http.StatusBadRequest, errorCode: "invalid_duration"},
+		{name: "persistent duration", body: `{"type":"animation","name":"plasma","duration":"30s"}`, status: http.StatusBadRequest, errorCode: "invalid_duration"},
+		{name: "gif-once duration", body: `{"type":"gif-once","name":"party","duration":"30s"}`, status: http.StatusBadRequest, errorCode: "invalid_duration"},
+		{name: "image parameters", body: `{"type":"image","name":"logo","params":{"speed":"2"}}`, status: http.StatusBadRequest, errorCode: "invalid_parameter"},
+		{name: "unknown animation", body: `{"type":"animation","name":"unknown"}`, status: http.StatusNotFound, errorCode: "animation_not_found"},
+		{name: "invalid animation parameter", body: `{"type":"animation","name":"plasma","params":{"speed":"fast"}}`, status: http.StatusBadRequest, errorCode: "invalid_parameter"},
+		{name: "unknown gif", body: `{"type":"gif-once","name":"party"}`, status: http.StatusNotFound, errorCode: "gif_not_found"},
+		{name: "unknown font", body: `{"type":"text","name":"Hello","params":{"font":"comic"}}`, status: http.StatusNotFound, errorCode: "font_not_found"},
+		{name: "blank parameters", body: `{"type":"blank","params":{"speed":"2"}}`, status: http.StatusBadRequest, errorCode: "invalid_parameter"},
+		{name: "unknown region", body: `{"type":"layout","name":"split","params":{"top":"dashboard:clock"}}`, status: http.StatusBadRequest, errorCode: "invalid_parameter"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := performJSONRequest(handler, http.MethodPut, "/display", test.body)
+			assertAPIError(t, response, test.status, test.errorCode)
+		})
+	}
+}
+
+func TestGIFOnceRouteIsTemporary(t *testing.T) {
+	commands := make(chan renderers.Command)
+	root := t.TempDir()
+	writeTestFile(t, root, "party.gif")
+	handler := newHandler(testServices(commands), catalog{gifsDir: root})
+	go func() {
+		command := <-commands
+		if command.Type != renderers.TypeGIFOnce || !command.IsTemporary || command.Duration != 0 {
+			t.Errorf("unexpected command: %#v", command)
+		}
+		command.Result <- nil
+	}()
+
+	response := performRequest(handler, http.MethodPut, "/gif-once?name=party")
+
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	var body displayResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Display != (displayCommand{Type: "gif", Name: "party", Temporary: true}) {
+		t.Fatalf("unexpected response: %#v", body.Display)
+	}
+}
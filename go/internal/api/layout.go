@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+// layoutHandler shows a layout. Query parameters other than name and
+// transition assign content to the regions, for example left=dashboard:clock.
+func layoutHandler(services Services, catalog catalog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		name := strings.TrimSpace(query.Get("name"))
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "missing_name", "query parameter \"name\" is required")
+			return
+		}
+		showContent(w, r, services, catalog, displayRequest{
+			Type:       renderers.TypeLayout.String(),
+			Name:       name,
+			Params:     commandParams(query),
+			Transition: query.Get("transition"),
+		})
+	}
+}
+
+// validateLayout checks that a layout exists and that its parameters assign
+// existing content to its regions.
+func validateLayout(services Services, catalog catalog, name string, params map[string]string) *contentError {
+	layout, ok := services.Layouts[name]
+	if !ok {
+		return &contentError{http.StatusNotFound, "layout_not_found", "layout \"" + name + "\" does not exist"}
+	}
+	for region, value := range params {
+		if _, ok := layout.Region(region); !ok {
+			return &contentError{http.StatusBadRequest, "invalid_parameter", "layout \"" + name + "\" has no region \"" + region + "\""}
+		}
+		content, err := renderers.ParseRegionContent(value)
+		if err != nil {
+			return &contentError{http.StatusBadRequest, "invalid_parameter", err.Error()}
+		}
+		if err := validateCatalogContent(catalog, content.Type, content.Type.String(), content.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func layoutsHandler(layouts map[string]rgbmatrix.Layout) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, catalogResponse{Items: rgbmatrix.LayoutNames(layouts)})
+	}
+}
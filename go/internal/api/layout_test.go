@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func testLayouts() map[string]rgbmatrix.Layout {
+	return map[string]rgbmatrix.Layout{
+		"split": {Regions: []rgbmatrix.Region{
+			{Name: "left", Width: 64, Height: 96},
+			{Name: "right", X: 64, Width: 128, Height: 96},
+		}},
+	}
+}
+
+func TestLayoutCommand(t *testing.T) {
+	commands := make(chan renderers.Command)
+	services := testServices(commands)
+	services.Layouts = testLayouts()
+	handler := newHandler(services, catalog{})
+	go func() {
+		command := <-commands
+		if command.Type != renderers.TypeLayout || command.Name != "split" ||
+			command.Params["left"] != "dashboard:clock" || command.Params["right"] != "animation:plasma" {
+			t.Errorf("unexpected command: %#v", command)
+		}
+		command.Result <- nil
+	}()
+
+	response := performRequest(handler, http.MethodPut, "/layout?name=split&left=dashboard:clock&right=animation:plasma")
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	assertCatalog(t, handler, "/layouts", []string{"split"})
+}
+
+func TestLayoutValidation(t *testing.T) {
+	services := testServices(make(chan renderers.Command))
+	services.Layouts = testLayouts()
+	handler := newHandler(services, catalog{})
+	tests := []struct {
+		name      string
+		path      string
+		status    int
+		errorCode string
+	}{
+		{name: "missing name", path: "/layout", status: http.StatusBadRequest, errorCode: "missing_name"},
+		{name: "unknown layout", path: "/layout?name=quad", status: http.StatusNotFound, errorCode: "layout_not_found"},
+		{name: "unknown region", path: "/layout?name=split&top=dashboard:clock", status: http.StatusBadRequest, errorCode: "invalid_parameter"},
+		{name: "invalid content", path: "/layout?name=split&left=clock", status: http.StatusBadRequest, errorCode: "invalid_parameter"},
+		{name: "temporary content", path: "/layout?name=split&left=gif-once:party", status: http.StatusBadRequest, errorCode: "invalid_parameter"},
+		{name: "unknown content", path: "/layout?name=split&left=animation:unknown", status: http.StatusNotFound, errorCode: "animation_not_found"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := performRequest(handler, http.MethodPut, test.path)
+			assertAPIError(t, response, test.status, test.errorCode)
+		})
+	}
+}
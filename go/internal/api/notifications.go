@@ -0,0 +1,181 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+)
+
+const maxNotificationBodySize = 16 << 10
+
+type notificationRequest struct {
+	Type       string            `json:"type"`
+	Name       string            `json:"name"`
+	Params     map[string]string `json:"params"`
+	Priority   int               `json:"priority"`
+	Duration   string            `json:"duration"`
+	TTL        string            `json:"ttl"`
+	Transition string            `json:"transition"`
+}
+
+type notificationBody struct {
+	ID         uint64     `json:"id,omitempty"`
+	Type       string     `json:"type"`
+	Name       string     `json:"name"`
+	Priority   int        `json:"priority"`
+	Duration   string     `json:"duration,omitempty"`
+	TTL        string     `json:"ttl,omitempty"`
+	EnqueuedAt *time.Time `json:"enqueued_at,omitempty"`
+	Active     bool       `json:"active"`
+}
+
+type notificationResponse struct {
+	Notification notificationBody `json:"notification"`
+}
+
+type notificationsResponse struct {
+	Notifications []notificationBody `json:"notifications"`
+}
+
+func listNotificationsHandler(state *renderers.State) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if state == nil {
+			writeError(w, http.StatusServiceUnavailable, "display_unavailable", "the display state is unavailable")
+			return
+		}
+		response := notificationsResponse{Notifications: []notificationBody{}}
+		for _, n := range state.Notifications() {
+			enqueuedAt := n.EnqueuedAt
+			response.Notifications = append(response.Notifications, notificationBody{
+				ID:         n.ID,
+				Type:       n.Type.String(),
+				Name:       n.Name,
+				Priority:   n.Priority,
+				Duration:   formatDuration(n.Duration),
+				TTL:        formatDuration(n.TTL),
+				EnqueuedAt: &enqueuedAt,
+				Active:     n.Active,
+			})
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+// notifyHandler queues temporary content. GIFs without a duration play once;
+// with a duration they loop until it elapsed.
+func notifyHandler(services Services, catalog catalog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body notificationRequest
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxNotificationBodySize))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_json", "the request body is not a valid notification")
+			return
+		}
+		body.Name = strings.TrimSpace(body.Name)
+		if body.Name == "" {
+			writeError(w, http.StatusBadRequest, "missing_name", "field \"name\" is required")
+			return
+		}
+
+		command := renderers.Command{Name: body.Name, IsTemporary: true, Priority: body.Priority}
+		var ok bool
+		if command.Transition, ok = parseTransition(w, body.Transition); !ok {
+			return
+		}
+		if command.Duration, ok = parseNotificationDuration(w, "duration", body.Duration); !ok {
+			return
+		}
+		if command.TTL, ok = parseNotificationDuration(w, "ttl", body.TTL); !ok {
+			return
+		}
+
+		kind := body.Type
+		switch body.Type {
+		case "image":
+			command.Type = renderers.TypeImage
+		case "gif":
+			command.Type = renderers.TypeGIF
+			if command.Duration == 0 {
+				command.Type = renderers.TypeGIFOnce
+			}
+		case "text":
+			command.Type = renderers.TypeText
+			command.Params = body.Params
+			if err := validateTextParams(command.Params, catalog); err != nil {
+				writeContentError(w, err)
+				return
+			}
+		default:
+			writeError(w, http.StatusBadRequest, "invalid_notification", "field \"type\" must be one of image, gif or text")
+			return
+		}
+		if command.Type != renderers.TypeText {
+			if len(body.Params) > 0 {
+				writeError(w, http.StatusBadRequest, "invalid_parameter", kind+" notifications have no parameters")
+				return
+			}
+			items, err := catalog.content(command.Type)
+			if err != nil {
+				log.Printf("validate %s %q: %v", kind, body.Name, err)
+				writeError(w, http.StatusInternalServerError, "catalog_failed", "the catalog could not be loaded")
+				return
+			}
+			if !contains(items, body.Name) {
+				writeError(w, http.StatusNotFound, kind+"_not_found", kind+" \""+body.Name+"\" does not exist")
+				return
+			}
+		}
+
+		queued := make(chan uint64, 1)
+		command.Queued = queued
+		if !sendCommand(w, r, services, kind, command) {
+			return
+		}
+		var id uint64
+		select {
+		case id = <-queued:
+		default:
+		}
+		writeJSON(w, http.StatusOK, notificationResponse{Notification: notificationBody{
+			ID:       id,
+			Type:     command.Type.String(),
+			Name:     command.Name,
+			Priority: command.Priority,
+			Duration: formatDuration(shownFor(command)),
+			TTL:      formatDuration(command.TTL),
+		}})
+	}
+}
+
+func parseNotificationDuration(w http.ResponseWriter, field, value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, true
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid_notification", "field \""+field+"\" must be a positive duration such as \"30s\"")
+		return 0, false
+	}
+	return duration, true
+}
+
+// shownFor returns how long temporary content is shown. GIF-once plays
+// until the GIF ended.
+func shownFor(command renderers.Command) time.Duration {
+	if command.Duration == 0 && command.Type != renderers.TypeGIFOnce {
+		return renderers.DefaultNotificationDuration
+	}
+	return command.Duration
+}
+
+func formatDuration(duration time.Duration) string {
+	if duration == 0 {
+		return ""
+	}
+	return duration.String()
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+)
+
+func TestNotifyQueuesTemporaryCommand(t *testing.T) {
+	gifs := t.TempDir()
+	writeTestFile(t, gifs, "success.gif")
+	commands := make(chan renderers.Command)
+	handler := newHandler(testServices(commands), catalog{gifsDir: gifs})
+
+	tests := []struct {
+		body        string
+		commandType renderers.ScreenType
+		duration    time.Duration
+	}{
+		{body: `{"type":"gif","name":"success","priority":5}`, commandType: renderers.TypeGIFOnce},
+		{body: `{"type":"gif","name":"success","priority":5,"duration":"15s","ttl":"1m"}`, commandType: renderers.TypeGIF, duration: 15 * time.Second},
+	}
+	for _, test := range tests {
+		go func() {
+			command := <-commands
+			if command.Type != test.commandType || !command.IsTemporary || command.Priority != 5 || command.Duration != test.duration {
+				t.Errorf("unexpected command: %#v", command)
+			}
+			command.Queued <- 7
+			command.Result <- nil
+		}()
+
+		response := performJSONRequest(handler, http.MethodPost, "/notifications", test.body)
+		if response.Code != http.StatusOK {
+			t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+		}
+		var body notificationResponse
+		if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Notification.ID != 7 || body.Notification.Type != test.commandType.String() || body.Notification.Name != "success" {
+			t.Fatalf("unexpected response: %#v", body)
+		}
+	}
+}
+
+func TestNotifyValidation(t *testing.T) {
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{})
+	tests := []struct {
+		name      string
+		body      string
+		status    int
+		errorCode string
+	}{
+		{name: "missing name", body: `{"type":"image"}`, status: http.StatusBadRequest, errorCode: "missing_name"},
+		{name: "persistent type", body: `{"type":"animation","name":"plasma"}`, status: http.StatusBadRequest, errorCode: "invalid_notification"},
+		{name: "invalid duration", body: `{"type":"image","name":"logo","duration":"-1s"}`, status: http.StatusBadRequest, errorCode: "invalid_notification"},
+		{name: "unknown image", body: `{"type":"image","name":"logo"}`, status: http.StatusNotFound, errorCode: "image_not_found"},
+		{name: "invalid text", body: `{"type":"text","name":"hi","params":{"color":"red"}}`, status: http.StatusBadRequest, errorCode: "invalid_parameter"},
+		{name: "unknown field", body: `{"type":"image","name":"logo","sticky":true}`, status: http.StatusBadRequest, errorCode: "invalid_json"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := performJSONRequest(handler, http.MethodPost, "/notifications", test.body)
+			assertAPIError(t, response, test.status, test.errorCode)
+		})
+	}
+}
+
+func TestListNotifications(t *testing.T) {
+	services := testServices(make(chan renderers.Command))
+	handler := newHandler(services, catalog{})
+	assertAPIError(t, performRequest(handler, http.MethodGet, "/notifications"), http.StatusServiceUnavailable, "display_unavailable")
+
+	services.State = renderers.NewState()
+	handler = newHandler(services, catalog{})
+	response := performRequest(handler, http.MethodGet, "/notifications")
+	if response.Code != http.StatusOK || response.Body.String() != "{\"notifications\":[]}\n" {
+		t.Fatalf("unexpected response: %d %s", response.Code, response.Body.String())
+	}
+}
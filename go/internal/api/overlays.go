@@ -0,0 +1,170 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+)
+
+const maxOverlayBodySize = 16 << 10
+
+type overlayRequest struct {
+	Type    string            `json:"type"`
+	Content string            `json:"content"`
+	X       int               `json:"x"`
+	Y       int               `json:"y"`
+	Z       int               `json:"z"`
+	Params  map[string]string `json:"params"`
+}
+
+type overlayBody struct {
+	ID      string            `json:"id"`
+	Type    string            `json:"type"`
+	Content string            `json:"content"`
+	X       int               `json:"x"`
+	Y       int               `json:"y"`
+	Z       int               `json:"z"`
+	Params  map[string]string `json:"params,omitempty"`
+}
+
+type overlayResponse struct {
+	Overlay  overlayBody `json:"overlay"`
+	Replaced bool        `json:"replaced"`
+}
+
+type overlaysResponse struct {
+	Overlays []overlayBody `json:"overlays"`
+}
+
+func newOverlayBody(overlay renderers.Overlay) overlayBody {
+	return overlayBody{
+		ID:      overlay.ID,
+		Type:    overlay.Type.String(),
+		Content: overlay.Content,
+		X:       overlay.X,
+		Y:       overlay.Y,
+		Z:       overlay.Z,
+		Params:  overlay.Params,
+	}
+}
+
+func listOverlaysHandler(overlays *renderers.Overlays) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if overlays == nil {
+			writeOverlaysUnavailable(w)
+			return
+		}
+		response := overlaysResponse{Overlays: []overlayBody{}}
+		for _, overlay := range overlays.List() {
+			response.Overlays = append(response.Overlays, newOverlayBody(overlay))
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+// setOverlayHandler adds or replaces the overlay with the ID in the path.
+func setOverlayHandler(overlays *renderers.Overlays, catalog catalog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if overlays == nil {
+			writeOverlaysUnavailable(w)
+			return
+		}
+		var body overlayRequest
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxOverlayBodySize))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_json", "the request body is not a valid overlay")
+			return
+		}
+		overlayType, err := renderers.ParseOverlayType(body.Type)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_overlay", err.Error())
+			return
+		}
+
+		overlay := renderers.Overlay{
+			ID:      r.PathValue("id"),
+			Type:    overlayType,
+			Content: body.Content,
+			X:       body.X,
+			Y:       body.Y,
+			Z:       body.Z,
+			Params:  body.Params,
+		}
+		if overlayType == renderers.OverlayClock && overlay.Content == "" {
+			overlay.Content = renderers.DefaultClockFormat
+		}
+		kind, name := "font", renderers.DefaultOverlayFont
+		if font, ok := overlay.Params["font"]; ok {
+			name = font
+		}
+		load := catalog.fonts
+		if overlayType == renderers.OverlayImage {
+			kind, name, load = "image", overlay.Content, catalog.images
+		}
+		items, err := load()
+		if err != nil {
+			log.Printf("validate %s %q: %v", kind, name, err)
+			writeError(w, http.StatusInternalServerError, "catalog_failed", "the catalog could not be loaded")
+			return
+		}
+		if name != "" && !contains(items, name) {
+			writeError(w, http.StatusNotFound, kind+"_not_found", kind+" \""+name+"\" does not exist")
+			return
+		}
+
+		replaced, err := overlays.Set(overlay)
+		switch {
+		case errors.Is(err, renderers.ErrInvalidOverlay):
+			writeError(w, http.StatusBadRequest, "invalid_overlay", err.Error())
+			return
+		case errors.Is(err, renderers.ErrInvalidParameter):
+			writeError(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+			return
+		case errors.Is(err, renderers.ErrInvalidAsset):
+			log.Printf("set overlay %q: %v", overlay.ID, err)
+			writeError(w, http.StatusUnprocessableEntity, "invalid_"+kind, kind+" \""+name+"\" could not be decoded")
+			return
+		case err != nil:
+			log.Printf("set overlay %q: %v", overlay.ID, err)
+			writeError(w, http.StatusInternalServerError, "overlay_failed", "the overlay could not be shown")
+			return
+		}
+
+		log.Printf("overlay set: id=%q type=%s replaced=%t", overlay.ID, overlay.Type, replaced)
+		status := http.StatusCreated
+		if replaced {
+			status = http.StatusOK
+		}
+		writeJSON(w, status, overlayResponse{Overlay: newOverlayBody(overlay), Replaced: replaced})
+	}
+}
+
+func deleteOverlayHandler(overlays *renderers.Overlays) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if overlays == nil {
+			writeOverlaysUnavailable(w)
+			return
+		}
+		id := r.PathValue("id")
+		deleted, err := overlays.Delete(id)
+		if err != nil {
+			log.Printf("delete overlay %q: %v", id, err)
+			writeError(w, http.StatusInternalServerError, "overlay_failed", "the overlay could not be removed")
+			return
+		}
+		if !deleted {
+			writeError(w, http.StatusNotFound, "overlay_not_found", "overlay \""+id+"\" does not exist")
+			return
+		}
+		log.Printf("overlay deleted: id=%q", id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func writeOverlaysUnavailable(w http.ResponseWriter) {
+	writeError(w, http.StatusServiceUnavailable, "overlays_unavailable", "overlays are not available on this server")
+}
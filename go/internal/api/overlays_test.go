@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestOverlays(t *testing.T) {
+	// The fonts of the repository, seen from this package.
+	fonts := filepath.Join("..", "..", "..", renderers.FontsDir)
+	images := t.TempDir()
+	services := testServices(make(chan renderers.Command))
+	services.Overlays = renderers.NewOverlays(rgbmatrix.NewLayers(rgbmatrix.NewMemory(64, 32)), images, fonts)
+	handler := newHandler(services, catalog{imagesDir: images, fontsDir: fonts})
+
+	response := performJSONRequest(handler, http.MethodPut, "/overlays/clock", `{"type":"clock","x":2,"y":1,"z":5}`)
+	if response.Code != http.StatusCreated {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	var created overlayResponse
+	if err := json.NewDecoder(response.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Replaced || !reflect.DeepEqual(created.Overlay, overlayBody{ID: "clock", Type: "clock", Content: "15:04", X: 2, Y: 1, Z: 5}) {
+		t.Fatalf("unexpected overlay: %#v", created)
+	}
+
+	response = performJSONRequest(handler, http.MethodPut, "/overlays/clock", `{"type":"text","content":"LIVE","params":{"color":"ff0000"}}`)
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status for a replaced overlay: %d, body: %s", response.Code, response.Body.String())
+	}
+
+	response = performRequest(handler, http.MethodGet, "/overlays")
+	var listed overlaysResponse
+	if err := json.NewDecoder(response.Body).Decode(&listed); err != nil {
+		t.Fatal(err)
+	}
+	if len(listed.Overlays) != 1 || listed.Overlays[0].Content != "LIVE" || listed.Overlays[0].Params["color"] != "ff0000" {
+		t.Fatalf("unexpected overlays: %#v", listed)
+	}
+
+	if response := performRequest(handler, http.MethodDelete, "/overlays/clock"); response.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status for delete: %d", response.Code)
+	}
+	assertAPIError(t, performRequest(handler, http.MethodDelete, "/overlays/clock"), http.StatusNotFound, "overlay_not_found")
+}
+
+func TestOverlayValidation(t *testing.T) {
+	fonts := t.TempDir()
+	writeTestFile(t, fonts, "5x7.bdf")
+	images := t.TempDir()
+	services := testServices(make(chan renderers.Command))
+	services.Overlays = renderers.NewOverlays(rgbmatrix.NewLayers(rgbmatrix.NewMemory(64, 32)), images, fonts)
+	handler := newHandler(services, catalog{imagesDir: images, fontsDir: fonts})
+	tests := []struct {
+		name      string
+		path      string
+		body      string
+		status    int
+		errorCode string
+	}{
+		{name: "invalid json", path: "/overlays/live", body: `{"type":"text","speed":3}`, status: http.StatusBadRequest, errorCode: "invalid_json"},
+		{name: "unknown type", path: "/overlays/live", body: `{"type":"bar"}`, status: http.StatusBadRequest, errorCode: "invalid_overlay"},
+		{name: "invalid id", path: "/overlays/-live", body: `{"type":"text","content":"LIVE"}`, status: http.StatusBadRequest, errorCode: "invalid_overlay"},
+		{name: "outside", path: "/overlays/live", body: `{"type":"text","content":"LIVE","x":64}`, status: http.StatusBadRequest, errorCode: "invalid_overlay"},
+		{name: "unknown font", path: "/overlays/live", body: `{"type":"text","content":"LIVE","params":{"font":"huge"}}`, status: http.StatusNotFound, errorCode: "font_not_found"},
+		{name: "unknown image", path: "/overlays/logo", body: `{"type":"image","content":"logo"}`, status: http.StatusNotFound, errorCode: "image_not_found"},
+		{name: "invalid parameter", path: "/overlays/live", body: `{"type":"text","content":"LIVE","params":{"color":"red"}}`, status: http.StatusBadRequest, errorCode: "invalid_parameter"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := performJSONRequest(handler, http.MethodPut, test.path, test.body)
+			assertAPIError(t, response, test.status, test.errorCode)
+		})
+	}
+
+	unavailable := newHandler(testServices(make(chan renderers.Command)), catalog{})
+	assertAPIError(t, performRequest(unavailable, http.MethodGet, "/overlays"), http.StatusServiceUnavailable, "overlays_unavailable")
+}
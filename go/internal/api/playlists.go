@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+)
+
+const maxPlaylistBodySize = 64 << 10
+
+var errCatalogUnavailable = errors.New("catalog unavailable")
+
+// playlistTypes are the renderer types that playlist entries can use.
+var playlistTypes = map[renderers.ScreenType]bool{
+	renderers.TypeImage:     true,
+	renderers.TypeGIF:       true,
+	renderers.TypeDashboard: true,
+	renderers.TypeAnimation: true,
+}
+
+type playlistBody struct {
+	Name    string              `json:"name"`
+	Entries []playlistEntryBody `json:"entries"`
+	Shuffle bool                `json:"shuffle"`
+	Repeat  bool                `json:"repeat"`
+}
+
+type playlistEntryBody struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Duration string `json:"duration"`
+}
+
+type playlistsResponse struct {
+	Active    string         `json:"active"`
+	Playlists []playlistBody `json:"playlists"`
+}
+
+type playlistResponse struct {
+	Playlist playlistBody `json:"playlist"`
+}
+
+type activePlaylistResponse struct {
+	Active string `json:"active"`
+}
+
+func listPlaylistsHandler(playlists *renderers.Playlists) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if playlists == nil {
+			writePlaylistsUnavailable(w)
+			return
+		}
+		active, _ := playlists.Active()
+		response := playlistsResponse{Active: active, Playlists: []playlistBody{}}
+		for _, playlist := range playlists.List() {
+			response.Playlists = append(response.Playlists, newPlaylistBody(playlist))
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+func savePlaylistHandler(playlists *renderers.Playlists, catalog catalog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if playlists == nil {
+			writePlaylistsUnavailable(w)
+			return
+		}
+
+		var body playlistBody
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPlaylistBodySize))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_json", "the request body is not a valid playlist")
+			return
+		}
+		body.Name = strings.TrimSpace(r.PathValue("name"))
+
+		playlist, err := parsePlaylist(body, catalog)
+		if err == nil {
+			err = playlists.Save(playlist)
+		}
+		if errors.Is(err, errCatalogUnavailable) {
+			log.Printf("validate playlist %q: %v", body.Name, err)
+			writeError(w, http.StatusInternalServerError, "catalog_failed", "the catalog could not be loaded")
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_playlist", err.Error())
+			return
+		}
+		log.Printf("playlist saved: playlist=%q entries=%d", playlist.Name, len(playlist.Entries))
+		writeJSON(w, http.StatusOK, playlistResponse{Playlist: newPlaylistBody(playlist)})
+	}
+}
+
+func startPlaylistHandler(playlists *renderers.Playlists) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if playlists == nil {
+			writePlaylistsUnavailable(w)
+			return
+		}
+		name := r.PathValue("name")
+		if err := playlists.Start(name); err != nil {
+			if errors.Is(err, renderers.ErrUnknownContent) {
+				writeError(w, http.StatusNotFound, "playlist_not_found", "playlist \""+name+"\" does not exist")
+				return
+			}
+			log.Printf("start playlist %q: %v", name, err)
+			writeError(w, http.StatusInternalServerError, "playlist_failed", "the playlist could not be started")
+			return
+		}
+		writeJSON(w, http.StatusOK, activePlaylistResponse{Active: name})
+	}
+}
+
+func stopPlaylistHandler(playlists *renderers.Playlists) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if playlists == nil {
+			writePlaylistsUnavailable(w)
+			return
+		}
+		playlists.Stop()
+		writeJSON(w, http.StatusOK, activePlaylistResponse{})
+	}
+}
+
+func parsePlaylist(body playlistBody, catalog catalog) (renderers.Playlist, error) {
+	playlist := renderers.Playlist{Name: body.Name, Shuffle: body.Shuffle, Repeat: body.Repeat}
+	for index, entry := range body.Entries {
+		screenType, err := renderers.ParseScreenType(entry.Type)
+		if err != nil || !playlistTypes[screenType] {
+			return renderers.Playlist{}, fmt.Errorf("entry %d: type %q cannot be used in a playlist", index, entry.Type)
+		}
+		items, err := catalog.content(screenType)
+		if err != nil {
+			return renderers.Playlist{}, fmt.Errorf("%w: %v", errCatalogUnavailable, err)
+		}
+		if !contains(items, entry.Name) {
+			return renderers.Playlist{}, fmt.Errorf("entry %d: %s %q does not exist", index, entry.Type, entry.Name)
+		}
+		duration, err := time.ParseDuration(entry.Duration)
+		if err != nil || duration <= 0 {
+			return renderers.Playlist{}, fmt.Errorf("entry %d: duration %q is not a positive duration such as \"30s\"", index, entry.Duration)
+		}
+		playlist.Entries = append(playlist.Entries, renderers.PlaylistEntry{
+			Type: screenType, Name: entry.Name, Duration: duration,
+		})
+	}
+	return playlist, nil
+}
+
+func newPlaylistBody(playlist renderers.Playlist) playlistBody {
+	body := playlistBody{
+		Name:    playlist.Name,
+		Entries: make([]playlistEntryBody, 0, len(playlist.Entries)),
+		Shuffle: playlist.Shuffle,
+		Repeat:  playlist.Repeat,
+	}
+	for _, entry := range playlist.Entries {
+		body.Entries = append(body.Entries, playlistEntryBody{
+			Type: entry.Type.String(), Name: entry.Name, Duration: entry.Duration.String(),
+		})
+	}
+	return body
+}
+
+func writePlaylistsUnavailable(w http.ResponseWriter) {
+	writeError(w, http.StatusServiceUnavailable, "playlists_unavailable", "playlists are unavailable")
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+)
+
+func TestSaveAndListPlaylists(t *testing.T) {
+	services := testServices(make(chan renderers.Command))
+	services.Playlists = renderers.NewPlaylists(context.Background(), services.Commands)
+	handler := newHandler(services, catalog{})
+
+	response := performJSONRequest(handler, http.MethodPut, "/playlists/evening", `{
+		"entries": [
+			{"type": "dashboard", "name": "clock", "duration": "60s"},
+			{"type": "animation", "name": "plasma", "duration": "2m"}
+		],
+		"repeat": true
+	}`)
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+
+	response = performRequest(handler, http.MethodGet, "/playlists")
+	var body playlistsResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Playlists) != 1 || body.Active != "" {
+		t.Fatalf("unexpected playlists: %#v", body)
+	}
+	playlist := body.Playlists[0]
+	if playlist.Name != "evening" || !playlist.Repeat || len(playlist.Entries) != 2 || playlist.Entries[1].Duration != "2m0s" {
+		t.Fatalf("unexpected playlist: %#v", playlist)
+	}
+}
+
+func TestPlaylistValidation(t *testing.T) {
+	services := testServices(make(chan renderers.Command))
+	services.Playlists = renderers.NewPlaylists(context.Background(), services.Commands)
+	handler := newHandler(services, catalog{})
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "unknown content", body: `{"entries":[{"type":"animation","name":"unknown","duration":"1s"}]}`},
+		{name: "temporary content", body: `{"entries":[{"type":"gif-once","name":"party","duration":"1s"}]}`},
+		{name: "missing duration", body: `{"entries":[{"type":"dashboard","name":"clock"}]}`},
+		{name: "no entries", body: `{"entries":[]}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := performJSONRequest(handler, http.MethodPut, "/playlists/broken", test.body)
+			assertAPIError(t, response, http.StatusBadRequest, "invalid_playlist")
+		})
+	}
+
+	response := performRequest(handler, http.MethodPost, "/playlists/missing/start")
+	assertAPIError(t, response, http.StatusNotFound, "playlist_not_found")
+}
+
+func performJSONRequest(handler http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	return response
+}
@@ -4,33 +4,77 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
-	"strings"
+	"net/url"
 	"time"
 
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/display"
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
 )
 
-func ListenAndServe(commands chan renderers.Command, frames *display.Hub) {
+// Services are the server components controlled through the HTTP API.
+type Services struct {
+	Commands   chan renderers.Command
+	Frames     *display.Hub
+	Playlists  *renderers.Playlists
+	Brightness rgbmatrix.Dimmable
+	Color      rgbmatrix.Correctable
+	State      *renderers.State
+	Layouts    map[string]rgbmatrix.Layout
+	Overlays   *renderers.Overlays
+	Reloader   *renderers.Reloader
+	Sequences  *renderers.Sequences
+	Schedules  *renderers.Schedules
+}
+
+// ListenAndServe serves the API until ctx is done. It then stops accepting
+// connections and gives the requests in flight up to drain to finish, while
+// the update loop still takes their commands. Display streams and requests
+// that are still running after drain are cancelled.
+func ListenAndServe(ctx context.Context, services Services, drain time.Duration) error {
+	requests, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
 	server := &http.Server{
 		Addr:              ":8085",
-		Handler:           NewHandler(commands, frames),
+		Handler:           NewHandler(services),
 		ReadHeaderTimeout: 5 * time.Second,
 		ReadTimeout:       10 * time.Second,
 		WriteTimeout:      10 * time.Second,
 		IdleTimeout:       60 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return requests },
 	}
 	log.Printf("HTTP API listening on %s", server.Addr)
-	log.Fatal(server.ListenAndServe())
+	served := make(chan error, 1)
+	go func() { served <- server.ListenAndServe() }()
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Shutdown does not wait for the hijacked connections of display
+	// streams, which are closed once the other requests drained.
+	log.Printf("HTTP API shutting down: drain=%s", drain)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drain)
+	defer cancel()
+	err := server.Shutdown(shutdownCtx)
+	cancelRequests()
+	if err != nil {
+		_ = server.Close()
+		return fmt.Errorf("drain HTTP API: %w", err)
+	}
+	return nil
 }
 
-func NewHandler(commands chan renderers.Command, frames *display.Hub) http.Handler {
-	return newHandler(commands, defaultCatalog(), frames)
+func NewHandler(services Services) http.Handler {
+	return newHandler(services, defaultCatalog())
 }
 
-func newHandler(commands chan renderers.Command, catalog catalog, frames *display.Hub) http.Handler {
+func newHandler(services Services, catalog catalog) http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
@@ -45,25 +89,54 @@ func newHandler(commands chan renderers.Command, catalog catalog, frames *displa
 	mux.HandleFunc("GET /animations", func(w http.ResponseWriter, _ *http.Request) {
 		writeJSON(w, http.StatusOK, catalogResponse{Items: catalog.animations()})
 	})
-	mux.HandleFunc("GET /display/stream", displayStreamHandler(frames))
-
-	mux.HandleFunc("PUT /image", commandHandler(commands, commandSpec{
-		kind: "image", commandType: renderers.TypeImage, available: catalog.images,
-	}))
-	mux.HandleFunc("PUT /gif", commandHandler(commands, commandSpec{
-		kind: "gif", commandType: renderers.TypeGIF, available: catalog.gifs,
-	}))
-	mux.HandleFunc("PUT /gif-once", commandHandler(commands, commandSpec{
-		kind: "gif", commandType: renderers.TypeGIFOnce, temporary: true, available: catalog.gifs,
-	}))
-	mux.HandleFunc("PUT /dashboard", commandHandler(commands, commandSpec{
-		kind: "dashboard", commandType: renderers.TypeDashboard,
-		available: func() ([]string, error) { return catalog.dashboards(), nil },
-	}))
-	mux.HandleFunc("PUT /animation", commandHandler(commands, commandSpec{
-		kind: "animation", commandType: renderers.TypeAnimation,
-		available: func() ([]string, error) { return catalog.animations(), nil },
-	}))
+	mux.HandleFunc("GET /animations/{name}", animationSchemaHandler)
+	mux.HandleFunc("GET /fonts", catalogHandler(catalog.fonts))
+	mux.HandleFunc("GET /palettes", catalogHandler(catalog.palettes))
+	mux.HandleFunc("GET /layouts", layoutsHandler(services.Layouts))
+	mux.HandleFunc("GET /display", displayStateHandler(services.State))
+	mux.HandleFunc("GET /display/errors", rendererErrorsHandler(services.State))
+	mux.HandleFunc("GET /display/stream", displayStreamHandler(services.Frames))
+
+	mux.HandleFunc("PUT /display", displayHandler(services, catalog))
+	mux.HandleFunc("PUT /image", commandHandler(services, catalog, renderers.TypeImage))
+	mux.HandleFunc("PUT /gif", commandHandler(services, catalog, renderers.TypeGIF))
+	mux.HandleFunc("PUT /gif-once", commandHandler(services, catalog, renderers.TypeGIFOnce))
+	mux.HandleFunc("PUT /dashboard", commandHandler(services, catalog, renderers.TypeDashboard))
+	mux.HandleFunc("PUT /animation", commandHandler(services, catalog, renderers.TypeAnimation))
+	mux.HandleFunc("PUT /text", textHandler(services, catalog))
+	mux.HandleFunc("PUT /layout", layoutHandler(services, catalog))
+
+	mux.HandleFunc("GET /notifications", listNotificationsHandler(services.State))
+	mux.HandleFunc("POST /notifications", notifyHandler(services, catalog))
+
+	mux.HandleFunc("GET /overlays", listOverlaysHandler(services.Overlays))
+	mux.HandleFunc("PUT /overlays/{id}", setOverlayHandler(services.Overlays, catalog))
+	mux.HandleFunc("DELETE /overlays/{id}", deleteOverlayHandler(services.Overlays))
+
+	mux.HandleFunc("PUT /images/{name}", uploadAssetHandler(imageAssets(catalog)))
+	mux.HandleFunc("DELETE /images/{name}", deleteAssetHandler(imageAssets(catalog)))
+	mux.HandleFunc("PUT /gifs/{name}", uploadAssetHandler(gifAssets(catalog)))
+	mux.HandleFunc("DELETE /gifs/{name}", deleteAssetHandler(gifAssets(catalog)))
+
+	mux.HandleFunc("GET /brightness", getBrightnessHandler(services.Brightness))
+	mux.HandleFunc("PUT /brightness", setBrightnessHandler(services.Brightness))
+	mux.HandleFunc("GET /color", getColorHandler(services.Color))
+	mux.HandleFunc("PUT /color", setColorHandler(services.Color))
+
+	mux.HandleFunc("POST /config/reload", reloadConfigHandler(services.Reloader))
+
+	mux.HandleFunc("GET /playlists", listPlaylistsHandler(services.Playlists))
+	mux.HandleFunc("PUT /playlists/{name}", savePlaylistHandler(services.Playlists, catalog))
+	mux.HandleFunc("POST /playlists/{name}/start", startPlaylistHandler(services.Playlists))
+	mux.HandleFunc("POST /playlists/stop", stopPlaylistHandler(services.Playlists))
+
+	mux.HandleFunc("GET /sequences", listSequencesHandler(services.Sequences))
+	mux.HandleFunc("POST /sequences", startSequenceHandler(services, catalog))
+	mux.HandleFunc("DELETE /sequences/{id}", cancelSequenceHandler(services.Sequences))
+
+	mux.HandleFunc("GET /schedules", listSchedulesHandler(services.Schedules))
+	mux.HandleFunc("POST /schedules", saveScheduleHandler(services, catalog))
+	mux.HandleFunc("DELETE /schedules/{id}", deleteScheduleHandler(services.Schedules))
 
 	return mux
 }
@@ -89,13 +162,7 @@ type displayCommand struct {
 	Type      string `json:"type"`
 	Name      string `json:"name"`
 	Temporary bool   `json:"temporary"`
-}
-
-type commandSpec struct {
-	kind        string
-	commandType renderers.ScreenType
-	temporary   bool
-	available   func() ([]string, error)
+	Duration  string `json:"duration,omitempty"`
 }
 
 func catalogHandler(load func() ([]string, error)) http.HandlerFunc {
@@ -110,56 +177,63 @@ func catalogHandler(load func() ([]string, error)) http.HandlerFunc {
 	}
 }
 
-func commandHandler(commands chan renderers.Command, spec commandSpec) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		name := strings.TrimSpace(r.URL.Query().Get("name"))
-		if name == "" {
-			writeError(w, http.StatusBadRequest, "missing_name", "query parameter \"name\" is required")
-			return
+// commandParams returns the query parameters of a command besides its name
+// and transition.
+func commandParams(query url.Values) map[string]string {
+	params := map[string]string{}
+	for key, values := range query {
+		if key != "name" && key != "transition" {
+			params[key] = values[0]
 		}
+	}
+	return params
+}
 
-		items, err := spec.available()
-		if err != nil {
-			log.Printf("validate %s %q: %v", spec.kind, name, err)
-			writeError(w, http.StatusInternalServerError, "catalog_failed", "the catalog could not be loaded")
-			return
-		}
-		if !contains(items, name) {
-			writeError(w, http.StatusNotFound, spec.kind+"_not_found", spec.kind+" \""+name+"\" does not exist")
-			return
-		}
+// sendCommand sends a validated command to the update loop and waits for its
+// result. It writes an error response and returns false if the command
+// failed.
+func sendCommand(w http.ResponseWriter, r *http.Request, services Services, kind string, command renderers.Command) bool {
+	// Content chosen by hand takes over from a running playlist, while
+	// temporary content plays on top of it.
+	if !command.IsTemporary && services.Playlists != nil {
+		services.Playlists.Stop()
+	}
 
-		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-		defer cancel()
-		result := make(chan error, 1)
-		command := renderers.Command{
-			Type:        spec.commandType,
-			Name:        name,
-			IsTemporary: spec.temporary,
-			Context:     ctx,
-			Result:      result,
-		}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	result := make(chan error, 1)
+	command.Context = ctx
+	command.Result = result
 
-		select {
-		case commands <- command:
-		case <-ctx.Done():
-			writeCommandContextError(w, ctx.Err())
-			return
-		}
+	select {
+	case services.Commands <- command:
+	case <-ctx.Done():
+		writeCommandContextError(w, ctx.Err())
+		return false
+	}
 
-		select {
-		case err := <-result:
-			if err != nil {
-				writeRendererError(w, spec.kind, name, err)
-				return
-			}
-			writeJSON(w, http.StatusOK, displayResponse{Display: displayCommand{
-				Type: spec.kind, Name: name, Temporary: spec.temporary,
-			}})
-		case <-ctx.Done():
-			writeCommandContextError(w, ctx.Err())
+	select {
+	case err := <-result:
+		if err != nil {
+			writeRendererError(w, kind, command.Name, err)
+			return false
 		}
+		return true
+	case <-ctx.Done():
+		writeCommandContextError(w, ctx.Err())
+		return false
+	}
+}
+
+// parseTransition parses the optional transition of a command. It writes an
+// error response and returns false if the style is unknown.
+func parseTransition(w http.ResponseWriter, value string) (rgbmatrix.TransitionStyle, bool) {
+	style, err := rgbmatrix.ParseTransitionStyle(value)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_transition", err.Error())
+		return 0, false
 	}
+	return style, true
 }
 
 func writeRendererError(w http.ResponseWriter, kind, name string, err error) {
@@ -167,6 +241,8 @@ func writeRendererError(w http.ResponseWriter, kind, name string, err error) {
 	switch {
 	case errors.Is(err, renderers.ErrInvalidAsset):
 		writeError(w, http.StatusUnprocessableEntity, "invalid_"+kind, kind+" \""+name+"\" could not be decoded")
+	case errors.Is(err, renderers.ErrInvalidParameter):
+		writeError(w, http.StatusBadRequest, "invalid_parameter", err.Error())
 	case errors.Is(err, renderers.ErrServiceUnavailable):
 		writeError(w, http.StatusServiceUnavailable, "service_unavailable", kind+" \""+name+"\" is temporarily unavailable")
 	case errors.Is(err, renderers.ErrUnknownContent):
@@ -11,10 +11,11 @@ import (
 
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/display"
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
 )
 
 func TestCommandValidation(t *testing.T) {
-	handler := newHandler(make(chan renderers.Command), catalog{}, display.NewHub())
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{})
 	tests := []struct {
 		name      string
 		path      string
@@ -25,6 +26,11 @@ func TestCommandValidation(t *testing.T) {
 		{name: "blank name", path: "/animation?name=%20", status: http.StatusBadRequest, errorCode: "missing_name"},
 		{name: "unknown animation", path: "/animation?name=unknown", status: http.StatusNotFound, errorCode: "animation_not_found"},
 		{name: "path traversal", path: "/image?name=..%2F..%2Fsecret", status: http.StatusNotFound, errorCode: "image_not_found"},
+		{name: "unknown transition", path: "/animation?name=plasma&transition=spin", status: http.StatusBadRequest, errorCode: "invalid_transition"},
+		{name: "invalid animation parameter", path: "/animation?name=plasma&speed=fast", status: http.StatusBadRequest, errorCode: "invalid_parameter"},
+		{name: "unknown animation parameter", path: "/animation?name=plasma&seed=3", status: http.StatusBadRequest, errorCode: "invalid_parameter"},
+		{name: "unknown dashboard palette", path: "/dashboard?name=clock&palette=mud", status: http.StatusBadRequest, errorCode: "invalid_parameter"},
+		{name: "unknown dashboard parameter", path: "/dashboard?name=clock&speed=2", status: http.StatusBadRequest, errorCode: "invalid_parameter"},
 	}
 
 	for _, test := range tests {
@@ -35,9 +41,43 @@ func TestCommandValidation(t *testing.T) {
 	}
 }
 
+func TestCommandPassesTransition(t *testing.T) {
+	commands := make(chan renderers.Command)
+	handler := newHandler(testServices(commands), catalog{})
+	go func() {
+		command := <-commands
+		if command.Transition != rgbmatrix.TransitionDissolve {
+			t.Errorf("unexpected transition: %v", command.Transition)
+		}
+		command.Result <- nil
+	}()
+
+	response := performRequest(handler, http.MethodPut, "/animation?name=plasma&transition=dissolve")
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+}
+
+func TestDashboardPassesPalette(t *testing.T) {
+	commands := make(chan renderers.Command)
+	handler := newHandler(testServices(commands), catalog{})
+	go func() {
+		command := <-commands
+		if command.Type != renderers.TypeDashboard || len(command.Params) != 1 || command.Params["palette"] != "sunset" {
+			t.Errorf("unexpected command: %#v", command)
+		}
+		command.Result <- nil
+	}()
+
+	response := performRequest(handler, http.MethodPut, "/dashboard?name=clock&palette=sunset&transition=wipe")
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+}
+
 func TestCommandSuccessWaitsForRenderer(t *testing.T) {
 	commands := make(chan renderers.Command)
-	handler := newHandler(commands, catalog{}, display.NewHub())
+	handler := newHandler(testServices(commands), catalog{})
 	go func() {
 		command := <-commands
 		if command.Type != renderers.TypeAnimation || command.Name != "plasma" {
@@ -79,7 +119,7 @@ func TestRendererErrorsAreMapped(t *testing.T) {
 			// The catalog only needs a sorted entry for validation; preparation is
 			// represented by the command result in this handler-level test.
 			writeTestFile(t, root, "valid.png")
-			handler := newHandler(commands, catalog, display.NewHub())
+			handler := newHandler(testServices(commands), catalog)
 			go func() {
 				command := <-commands
 				command.Result <- test.err
@@ -92,13 +132,17 @@ func TestRendererErrorsAreMapped(t *testing.T) {
 }
 
 func TestHealthOnlyAcceptsGet(t *testing.T) {
-	handler := newHandler(make(chan renderers.Command), catalog{}, display.NewHub())
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{})
 	response := performRequest(handler, http.MethodPost, "/healthz")
 	if response.Code != http.StatusMethodNotAllowed {
 		t.Fatalf("unexpected status: %d", response.Code)
 	}
 }
 
+func testServices(commands chan renderers.Command) Services {
+	return Services{Commands: commands, Frames: display.NewHub()}
+}
+
 func performRequest(handler http.Handler, method, path string) *httptest.ResponseRecorder {
 	request := httptest.NewRequest(method, path, nil)
 	response := httptest.NewRecorder()
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/cron"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+const maxScheduleBodySize = 16 << 10
+
+// scheduleRequest is content that is shown whenever the cron expression
+// matches in the time zone.
+type scheduleRequest struct {
+	ID       string `json:"id"`
+	Cron     string `json:"cron"`
+	Timezone string `json:"timezone"`
+	displayRequest
+}
+
+type scheduleBody struct {
+	ID       string `json:"id"`
+	Cron     string `json:"cron"`
+	Timezone string `json:"timezone"`
+	displayCommand
+	Params     map[string]string `json:"params,omitempty"`
+	Transition string            `json:"transition,omitempty"`
+	// NextRun is left out if the expression does not match in the next
+	// years, such as February 30.
+	NextRun *time.Time `json:"next_run,omitempty"`
+}
+
+type scheduleResponse struct {
+	Schedule scheduleBody `json:"schedule"`
+	Replaced bool         `json:"replaced"`
+}
+
+type schedulesResponse struct {
+	Schedules []scheduleBody `json:"schedules"`
+}
+
+func newScheduleBody(schedule renderers.Schedule, now time.Time) scheduleBody {
+	body := scheduleBody{
+		ID:             schedule.ID,
+		Cron:           schedule.Expression.String(),
+		Timezone:       schedule.Location.String(),
+		displayCommand: newDisplayCommand(schedule.Command),
+		Params:         schedule.Command.Params,
+	}
+	if schedule.Command.Transition != rgbmatrix.TransitionDefault {
+		body.Transition = schedule.Command.Transition.String()
+	}
+	if next := schedule.Next(now); !next.IsZero() {
+		body.NextRun = &next
+	}
+	return body
+}
+
+func listSchedulesHandler(schedules *renderers.Schedules) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if schedules == nil {
+			writeSchedulesUnavailable(w)
+			return
+		}
+		now := time.Now()
+		response := schedulesResponse{Schedules: []scheduleBody{}}
+		for _, schedule := range schedules.List() {
+			response.Schedules = append(response.Schedules, newScheduleBody(schedule, now))
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+// saveScheduleHandler creates or replaces a schedule. Its content is
+// validated like content that is shown right away, so that a schedule does
+// not fail at night because of a typo.
+func saveScheduleHandler(services Services, catalog catalog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if services.Schedules == nil {
+			writeSchedulesUnavailable(w)
+			return
+		}
+
+		var body scheduleRequest
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxScheduleBodySize))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_json", "the request body is not a valid schedule")
+			return
+		}
+		expression, err := cron.Parse(body.Cron)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_cron", err.Error())
+			return
+		}
+		location, err := rgbmatrix.Schedule{Timezone: body.Timezone}.Location()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_timezone", "field \"timezone\" must be a time zone such as \"Europe/Berlin\"")
+			return
+		}
+		command, ok := parseDisplayRequest(w, services, catalog, body.displayRequest)
+		if !ok {
+			return
+		}
+
+		schedule := renderers.Schedule{ID: body.ID, Expression: expression, Location: location, Command: command}
+		replaced, err := services.Schedules.Save(schedule)
+		switch {
+		case errors.Is(err, renderers.ErrInvalidSchedule):
+			writeError(w, http.StatusBadRequest, "invalid_schedule", err.Error())
+			return
+		case err != nil:
+			log.Printf("save schedule %q: %v", schedule.ID, err)
+			writeError(w, http.StatusInternalServerError, "schedule_failed", "the schedule could not be saved")
+			return
+		}
+
+		log.Printf("schedule saved: id=%q cron=%q timezone=%s type=%s name=%q replaced=%t",
+			schedule.ID, expression, location, command.Type, command.Name, replaced)
+		status := http.StatusCreated
+		if replaced {
+			status = http.StatusOK
+		}
+		writeJSON(w, status, scheduleResponse{Schedule: newScheduleBody(schedule, time.Now()), Replaced: replaced})
+	}
+}
+
+func deleteScheduleHandler(schedules *renderers.Schedules) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if schedules == nil {
+			writeSchedulesUnavailable(w)
+			return
+		}
+		id := r.PathValue("id")
+		if !schedules.Delete(id) {
+			writeError(w, http.StatusNotFound, "schedule_not_found", "schedule \""+id+"\" does not exist")
+			return
+		}
+		log.Printf("schedule deleted: id=%q", id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ValidateSchedules checks the content of schedules against the catalog,
+// like the content of a schedule that is saved through the API. The servers
+// check the schedules of the config with it when they start, so that a typo
+// is reported then and not when the schedule is due.
+func ValidateSchedules(schedules []renderers.Schedule, layouts map[string]rgbmatrix.Layout) error {
+	return validateSchedules(schedules, Services{Layouts: layouts}, defaultCatalog())
+}
+
+func validateSchedules(schedules []renderers.Schedule, services Services, catalog catalog) error {
+	for _, schedule := range schedules {
+		command := schedule.Command
+		if err := validateContent(services, catalog, contentKind(command.Type), command); err != nil {
+			return fmt.Errorf("schedule %q: %w", schedule.ID, err)
+		}
+	}
+	return nil
+}
+
+func writeSchedulesUnavailable(w http.ResponseWriter) {
+	writeError(w, http.StatusServiceUnavailable, "schedules_unavailable", "schedules are not available on this server")
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+)
+
+func TestScheduleSaveListAndDelete(t *testing.T) {
+	services := testServices(make(chan renderers.Command))
+	services.Schedules = renderers.NewSchedules(services.Commands, nil)
+	handler := newHandler(services, catalog{})
+
+	body := `{"id":"night","cron":"0 22 * * *","timezone":"Europe/Berlin","type":"blank","transition":"crossfade"}`
+	response := performJSONRequest(handler, http.MethodPost, "/schedules", body)
+	if response.Code != http.StatusCreated {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	response = performJSONRequest(handler, http.MethodPost, "/schedules", body)
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+
+	response = performRequest(handler, http.MethodGet, "/schedules")
+	var list schedulesResponse
+	if err := json.NewDecoder(response.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Schedules) != 1 {
+		t.Fatalf("unexpected schedules: %#v", list.Schedules)
+	}
+	schedule := list.Schedules[0]
+	if schedule.ID != "night" || schedule.Cron != "0 22 * * *" || schedule.Timezone != "Europe/Berlin" ||
+		schedule.Type != "blank" || schedule.Transition != "crossfade" || schedule.NextRun == nil {
+		t.Fatalf("unexpected schedule: %#v", schedule)
+	}
+	location, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	if next := schedule.NextRun.In(location); next.Hour() != 22 || next.Minute() != 0 || !next.After(time.Now()) {
+		t.Fatalf("unexpected next run: %s", next)
+	}
+
+	response = performRequest(handler, http.MethodDelete, "/schedules/night")
+	if response.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	response = performRequest(handler, http.MethodDelete, "/schedules/night")
+	assertAPIError(t, response, http.StatusNotFound, "schedule_not_found")
+}
+
+func TestScheduleValidation(t *testing.T) {
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{})
+	response := performJSONRequest(handler, http.MethodPost, "/schedules", `{"id":"night","cron":"@daily","type":"blank"}`)
+	assertAPIError(t, response, http.StatusServiceUnavailable, "schedules_unavailable")
+
+	services := testServices(make(chan renderers.Command))
+	services.Schedules = renderers.NewSchedules(services.Commands, nil)
+	handler = newHandler(services, catalog{})
+	tests := []struct {
+		name      string
+		body      string
+		status    int
+		errorCode string
+	}{
+		{name: "invalid json", body: `{"id":"night","cron":"@daily","type":"blank","every":"day"}`, status: http.StatusBadRequest, errorCode: "invalid_json"},
+		{name: "invalid cron", body: `{"id":"night","cron":"0 25 * * *","type":"blank"}`, status: http.StatusBadRequest, errorCode: "invalid_cron"},
+		{name: "unknown timezone", body: `{"id":"night","cron":"@daily","timezone":"Mars/Olympus","type":"blank"}`, status: http.StatusBadRequest, errorCode: "invalid_timezone"},
+		{name: "unknown content", body: `{"id":"morning","cron":"0 7 * * *","type":"animation","name":"unknown"}`, status: http.StatusNotFound, errorCode: "animation_not_found"},
+		{name: "invalid id", body: `{"id":"good night","cron":"@daily","type":"blank"}`, status: http.StatusBadRequest, errorCode: "invalid_schedule"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := performJSONRequest(handler, http.MethodPost, "/schedules", test.body)
+			assertAPIError(t, response, test.status, test.errorCode)
+		})
+	}
+	if schedules := services.Schedules.List(); len(schedules) != 0 {
+		t.Fatalf("invalid schedule was saved: %#v", schedules)
+	}
+}
+
+func TestValidateSchedules(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "logo.png")
+	catalog := catalog{imagesDir: root}
+	valid := []renderers.Schedule{
+		{ID: "logo", Command: renderers.Command{Type: renderers.TypeImage, Name: "logo"}},
+		{ID: "night", Command: renderers.Command{Type: renderers.TypeBlank}},
+	}
+	if err := validateSchedules(valid, Services{}, catalog); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := append(valid, renderers.Schedule{ID: "morning", Command: renderers.Command{Type: renderers.TypeImage, Name: "sunrise"}})
+	err := validateSchedules(invalid, Services{}, catalog)
+	if err == nil || err.Error() != `schedule "morning": image "sunrise" does not exist` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+)
+
+const maxSequenceBodySize = 64 << 10
+
+type sequenceRequest struct {
+	Steps []displayRequest `json:"steps"`
+	// Priority is the priority of the temporary steps.
+	Priority int `json:"priority"`
+}
+
+type sequenceBody struct {
+	ID    uint64           `json:"id"`
+	Steps []displayCommand `json:"steps"`
+}
+
+type sequenceResponse struct {
+	Sequence sequenceBody `json:"sequence"`
+}
+
+type sequencesResponse struct {
+	Sequences []sequenceBody `json:"sequences"`
+}
+
+func listSequencesHandler(sequences *renderers.Sequences) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if sequences == nil {
+			writeSequencesUnavailable(w)
+			return
+		}
+		response := sequencesResponse{Sequences: []sequenceBody{}}
+		for _, sequence := range sequences.List() {
+			response.Sequences = append(response.Sequences, newSequenceBody(sequence))
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+// startSequenceHandler validates every step of a sequence before it starts
+// the sequence, so that a sequence never stops halfway because of a typo.
+func startSequenceHandler(services Services, catalog catalog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if services.Sequences == nil {
+			writeSequencesUnavailable(w)
+			return
+		}
+
+		var body sequenceRequest
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSequenceBodySize))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_json", "the request body is not a valid sequence")
+			return
+		}
+		if len(body.Steps) == 0 || len(body.Steps) > renderers.MaxSequenceSteps {
+			writeError(w, http.StatusBadRequest, "invalid_sequence", fmt.Sprintf("a sequence has between 1 and %d steps", renderers.MaxSequenceSteps))
+			return
+		}
+
+		steps := make([]renderers.Command, 0, len(body.Steps))
+		persistent := false
+		for _, request := range body.Steps {
+			command, ok := parseDisplayRequest(w, services, catalog, request)
+			if !ok {
+				return
+			}
+			command.Priority = body.Priority
+			persistent = persistent || !command.IsTemporary
+			steps = append(steps, command)
+		}
+
+		// Like other content chosen by hand, persistent steps take over
+		// from a running playlist.
+		if persistent && services.Playlists != nil {
+			services.Playlists.Stop()
+		}
+		sequence, err := services.Sequences.Start(steps)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_sequence", err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, sequenceResponse{Sequence: newSequenceBody(sequence)})
+	}
+}
+
+// cancelSequenceHandler stops a running sequence. Its temporary step that is
+// shown or waiting is withdrawn, while persistent steps stay.
+func cancelSequenceHandler(sequences *renderers.Sequences) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if sequences == nil {
+			writeSequencesUnavailable(w)
+			return
+		}
+		id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+		if err != nil || !sequences.Cancel(id) {
+			writeError(w, http.StatusNotFound, "sequence_not_found", "sequence \""+r.PathValue("id")+"\" is not running")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newSequenceBody(sequence renderers.Sequence) sequenceBody {
+	body := sequenceBody{ID: sequence.ID, Steps: make([]displayCommand, 0, len(sequence.Steps))}
+	for _, step := range sequence.Steps {
+		body.Steps = append(body.Steps, newDisplayCommand(step))
+	}
+	return body
+}
+
+func writeSequencesUnavailable(w http.ResponseWriter) {
+	writeError(w, http.StatusServiceUnavailable, "sequences_unavailable", "sequences are unavailable")
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestSequenceStartAndCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	commands := make(chan renderers.Command)
+	services := testServices(commands)
+	services.Sequences = renderers.NewSequences(ctx, commands)
+	go renderers.UpdateLoopWithMatrix(ctx, commands, rgbmatrix.NewMemory(16, 16), renderers.LoopOptions{
+		Withdrawals: services.Sequences.Withdrawals(),
+	})
+	handler := newHandler(services, catalog{})
+
+	response := performJSONRequest(handler, http.MethodPost, "/sequences", `{"steps":[
+		{"type":"animation","name":"ripple","temporary":true,"duration":"1m"},
+		{"type":"animation","name":"spiral","temporary":true,"duration":"5s"},
+		{"type":"dashboard","name":"clock"}
+	],"priority":5}`)
+
+	if response.Code != http.StatusCreated {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	var body sequenceResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	want := []displayCommand{
+		{Type: "animation", Name: "ripple", Temporary: true, Duration: "1m0s"},
+		{Type: "animation", Name: "spiral", Temporary: true, Duration: "5s"},
+		{Type: "dashboard", Name: "clock"},
+	}
+	if body.Sequence.ID != 1 || len(body.Sequence.Steps) != len(want) {
+		t.Fatalf("unexpected sequence: %#v", body.Sequence)
+	}
+	for index, step := range want {
+		if body.Sequence.Steps[index] != step {
+			t.Fatalf("unexpected step %d: %#v", index, body.Sequence.Steps[index])
+		}
+	}
+
+	response = performRequest(handler, http.MethodDelete, "/sequences/1")
+	if response.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	response = performRequest(handler, http.MethodDelete, "/sequences/1")
+	assertAPIError(t, response, http.StatusNotFound, "sequence_not_found")
+}
+
+func TestSequenceValidation(t *testing.T) {
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{})
+	response := performJSONRequest(handler, http.MethodPost, "/sequences", `{"steps":[{"type":"animation","name":"plasma"}]}`)
+	assertAPIError(t, response, http.StatusServiceUnavailable, "sequences_unavailable")
+
+	services := testServices(make(chan renderers.Command))
+	services.Sequences = renderers.NewSequences(context.Background(), services.Commands)
+	handler = newHandler(services, catalog{})
+	tests := []struct {
+		name      string
+		body      string
+		status    int
+		errorCode string
+	}{
+		{name: "invalid json", body: `{"steps":{}}`, status: http.StatusBadRequest, errorCode: "invalid_json"},
+		{name: "no steps", body: `{"steps":[]}`, status: http.StatusBadRequest, errorCode: "invalid_sequence"},
+		{name: "unknown content", body: `{"steps":[{"type":"animation","name":"plasma"},{"type":"animation","name":"unknown"}]}`, status: http.StatusNotFound, errorCode: "animation_not_found"},
+		{name: "persistent duration", body: `{"steps":[{"type":"animation","name":"plasma","duration":"5s"}]}`, status: http.StatusBadRequest, errorCode: "invalid_duration"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := performJSONRequest(handler, http.MethodPost, "/sequences", test.body)
+			assertAPIError(t, response, test.status, test.errorCode)
+		})
+	}
+	if sequences := services.Sequences.List(); len(sequences) != 0 {
+		t.Fatalf("invalid sequence was started: %#v", sequences)
+	}
+}
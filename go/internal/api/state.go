@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+)
+
+type displayStateResponse struct {
+	Display displayStateBody `json:"display"`
+}
+
+type displayStateBody struct {
+	Type      string             `json:"type,omitempty"`
+	Name      string             `json:"name,omitempty"`
+	Temporary bool               `json:"temporary"`
+	StartedAt *time.Time         `json:"started_at,omitempty"`
+	RevertTo  *contentBody       `json:"revert_to,omitempty"`
+	Failed    bool               `json:"failed"`
+	LastError *rendererErrorBody `json:"last_error,omitempty"`
+}
+
+type contentBody struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type rendererErrorBody struct {
+	Type    string    `json:"type"`
+	Name    string    `json:"name"`
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+}
+
+type rendererErrorsResponse struct {
+	Errors []rendererErrorBody `json:"errors"`
+}
+
+func displayStateHandler(state *renderers.State) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if state == nil {
+			writeError(w, http.StatusServiceUnavailable, "display_unavailable", "the display state is unavailable")
+			return
+		}
+		writeJSON(w, http.StatusOK, displayStateResponse{Display: newDisplayStateBody(state.Snapshot())})
+	}
+}
+
+func newDisplayStateBody(snapshot renderers.DisplayState) displayStateBody {
+	var body displayStateBody
+	if !snapshot.StartedAt.IsZero() {
+		startedAt := snapshot.StartedAt
+		body.Type = snapshot.Type.String()
+		body.Name = snapshot.Name
+		body.Temporary = snapshot.Temporary
+		body.Failed = snapshot.Failed
+		body.StartedAt = &startedAt
+	}
+	if snapshot.RevertTo != nil {
+		body.RevertTo = &contentBody{Type: snapshot.RevertTo.Type.String(), Name: snapshot.RevertTo.Name}
+	}
+	if snapshot.LastError != nil {
+		lastError := newRendererErrorBody(*snapshot.LastError)
+		body.LastError = &lastError
+	}
+	return body
+}
+
+// rendererErrorsHandler lists the recent renderer errors, newest first.
+func rendererErrorsHandler(state *renderers.State) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if state == nil {
+			writeError(w, http.StatusServiceUnavailable, "display_unavailable", "the display state is unavailable")
+			return
+		}
+		response := rendererErrorsResponse{Errors: []rendererErrorBody{}}
+		for _, err := range state.Errors() {
+			response.Errors = append(response.Errors, newRendererErrorBody(err))
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+func newRendererErrorBody(err renderers.RendererError) rendererErrorBody {
+	return rendererErrorBody{Type: err.Type.String(), Name: err.Name, Message: err.Message, At: err.At}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestDisplayState(t *testing.T) {
+	services := testServices(make(chan renderers.Command))
+	handler := newHandler(services, catalog{})
+	response := performRequest(handler, http.MethodGet, "/display")
+	assertAPIError(t, response, http.StatusServiceUnavailable, "display_unavailable")
+
+	services.State = renderers.NewState()
+	handler = newHandler(services, catalog{})
+	response = performRequest(handler, http.MethodGet, "/display")
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	var body displayStateResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Display.Type != "" || body.Display.StartedAt != nil || body.Display.LastError != nil {
+		t.Fatalf("unexpected state before the first renderer: %#v", body.Display)
+	}
+}
+
+func TestDisplayStateBody(t *testing.T) {
+	startedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	body := newDisplayStateBody(renderers.DisplayState{
+		Content:   renderers.Content{Type: renderers.TypeGIFOnce, Name: "success"},
+		Temporary: true,
+		StartedAt: startedAt,
+		RevertTo:  &renderers.Content{Type: renderers.TypeDashboard, Name: "clock"},
+		LastError: &renderers.RendererError{
+			Content: renderers.Content{Type: renderers.TypeImage, Name: "broken"},
+			Message: "invalid asset",
+			At:      startedAt,
+		},
+	})
+
+	if body.Type != "gif-once" || body.Name != "success" || !body.Temporary || !body.StartedAt.Equal(startedAt) {
+		t.Fatalf("unexpected content: %#v", body)
+	}
+	if body.RevertTo == nil || *body.RevertTo != (contentBody{Type: "dashboard", Name: "clock"}) {
+		t.Fatalf("unexpected revert target: %#v", body.RevertTo)
+	}
+	if body.LastError == nil || body.LastError.Type != "image" || body.LastError.Message != "invalid asset" {
+		t.Fatalf("unexpected last error: %#v", body.LastError)
+	}
+}
+
+func TestRendererErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	commands := make(chan renderers.Command)
+	services := testServices(commands)
+	services.State = renderers.NewState()
+	go renderers.UpdateLoopWithMatrix(ctx, commands, rgbmatrix.NewMemory(16, 16), renderers.LoopOptions{State: services.State})
+	for _, name := range []string{"first", "second"} {
+		command := renderers.Command{Type: renderers.TypeAnimation, Name: name, Result: make(chan error, 1)}
+		commands <- command
+		if err := <-command.Result; !errors.Is(err, renderers.ErrUnknownContent) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	response := performRequest(newHandler(services, catalog{}), http.MethodGet, "/display/errors")
+
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+	var body rendererErrorsResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Errors) != 2 || body.Errors[0].Name != "second" || body.Errors[1].Type != "animation" {
+		t.Fatalf("unexpected errors: %#v", body.Errors)
+	}
+}
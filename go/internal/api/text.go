@@ -0,0 +1,54 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+)
+
+// textParameters are the query parameters passed to the text renderer.
+var textParameters = []string{"font", "color", "background", "align", "mode", "speed"}
+
+func textHandler(services Services, catalog catalog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		message := strings.TrimSpace(query.Get("message"))
+		if message == "" {
+			writeError(w, http.StatusBadRequest, "missing_message", "query parameter \"message\" is required")
+			return
+		}
+
+		params := map[string]string{}
+		for _, key := range textParameters {
+			if query.Has(key) {
+				params[key] = query.Get(key)
+			}
+		}
+		showContent(w, r, services, catalog, displayRequest{
+			Type:       renderers.TypeText.String(),
+			Name:       message,
+			Params:     params,
+			Transition: query.Get("transition"),
+		})
+	}
+}
+
+// validateTextParams checks the text parameters and that their font exists.
+func validateTextParams(params map[string]string, catalog catalog) *contentError {
+	options, err := renderers.ParseTextOptions(params)
+	if err != nil {
+		return &contentError{http.StatusBadRequest, "invalid_parameter", err.Error()}
+	}
+
+	fonts, err := catalog.fonts()
+	if err != nil {
+		log.Printf("validate font %q: %v", options.Font, err)
+		return &contentError{http.StatusInternalServerError, "catalog_failed", "the catalog could not be loaded"}
+	}
+	if !contains(fonts, options.Font) {
+		return &contentError{http.StatusNotFound, "font_not_found", "font \"" + options.Font + "\" does not exist"}
+	}
+	return nil
+}
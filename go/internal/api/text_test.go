@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers"
+)
+
+func TestTextCommand(t *testing.T) {
+	fonts := t.TempDir()
+	writeTestFile(t, fonts, "5x7.bdf")
+	commands := make(chan renderers.Command)
+	handler := newHandler(testServices(commands), catalog{fontsDir: fonts})
+	go func() {
+		command := <-commands
+		if command.Type != renderers.TypeText || command.Name != "GOAL!" ||
+			command.Params["font"] != "5x7" || command.Params["mode"] != "marquee" {
+			t.Errorf("unexpected command: %#v", command)
+		}
+		command.Result <- nil
+	}()
+
+	response := performRequest(handler, http.MethodPut, "/text?message=GOAL!&font=5x7&mode=marquee")
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d, body: %s", response.Code, response.Body.String())
+	}
+}
+
+func TestTextValidation(t *testing.T) {
+	handler := newHandler(testServices(make(chan renderers.Command)), catalog{fontsDir: filepath.Join(t.TempDir(), "missing")})
+	tests := []struct {
+		name      string
+		path      string
+		status    int
+		errorCode string
+	}{
+		{name: "missing message", path: "/text", status: http.StatusBadRequest, errorCode: "missing_message"},
+		{name: "invalid color", path: "/text?message=hi&color=red", status: http.StatusBadRequest, errorCode: "invalid_parameter"},
+		{name: "unknown font", path: "/text?message=hi&font=comic", status: http.StatusNotFound, errorCode: "font_not_found"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := performRequest(handler, http.MethodPut, test.path)
+			assertAPIError(t, response, test.status, test.errorCode)
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// Package cron parses cron expressions and computes when they match next.
+// Expressions have the five fields minute, hour, day of month, month and
+// day of week, or are one of the macros such as @daily.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchYears bounds how far Next looks ahead for expressions that rarely
+// or never match, such as February 30.
+const searchYears = 5
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// field describes the values of one field of an expression.
+type field struct {
+	name     string
+	min, max int
+	// names are the names of the values from min on, if the field has any.
+	names []string
+}
+
+var (
+	minuteField  = field{name: "minute", min: 0, max: 59}
+	hourField    = field{name: "hour", min: 0, max: 23}
+	dayField     = field{name: "day of month", min: 1, max: 31}
+	monthField   = field{name: "month", min: 1, max: 12, names: monthNames}
+	weekdayField = field{name: "day of week", min: 0, max: 7, names: weekdayNames}
+)
+
+// Expression is a parsed cron expression.
+type Expression struct {
+	spec     string
+	minutes  set
+	hours    set
+	days     set
+	months   set
+	weekdays set
+	// anyDay and anyWeekday record day fields that start with "*". When both day
+	// fields are restricted, a day matches if either of them matches.
+	anyDay, anyWeekday bool
+}
+
+// set is a bit set of the values of a field.
+type set uint64
+
+func (s set) has(value int) bool {
+	return s&(1<<value) != 0
+}
+
+// Parse parses a cron expression such as "0 7 * * 1-5". Fields are values,
+// ranges such as 9-17, steps such as */15 or 8-18/2 and lists of them.
+// Months and days of the week may be given by their English abbreviations
+// such as jan or mon, and Sunday is both 0 and 7.
+func Parse(spec string) (Expression, error) {
+	expanded := strings.TrimSpace(spec)
+	if macro, ok := macros[strings.ToLower(expanded)]; ok {
+		expanded = macro
+	}
+	fields := strings.Fields(expanded)
+	if len(fields) != 5 {
+		return Expression{}, fmt.Errorf("cron expression %q does not have 5 fields", spec)
+	}
+
+	e := Expression{spec: spec, anyDay: strings.HasPrefix(fields[2], "*"), anyWeekday: strings.HasPrefix(fields[4], "*")}
+	var err error
+	for index, parse := range []struct {
+		field field
+		set   *set
+	}{
+		{minuteField, &e.minutes},
+		{hourField, &e.hours},
+		{dayField, &e.days},
+		{monthField, &e.months},
+		{weekdayField, &e.weekdays},
+	} {
+		if *parse.set, err = parse.field.parse(fields[index]); err != nil {
+			return Expression{}, fmt.Errorf("cron expression %q: %w", spec, err)
+		}
+	}
+	if e.weekdays.has(7) {
+		e.weekdays |= 1
+	}
+	return e, nil
+}
+
+// parse parses one field, a comma-separated list of values, ranges and
+// steps.
+func (f field) parse(value string) (set, error) {
+	var values set
+	for _, part := range strings.Split(strings.ToLower(value), ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("%s: step %q is not a positive number", f.name, stepPart)
+			}
+		}
+
+		first, last := f.min, f.max
+		switch from, to, isRange := strings.Cut(rangePart, "-"); {
+		case rangePart == "*":
+		case isRange:
+			var err error
+			if first, err = f.value(from); err != nil {
+				return 0, err
+			}
+			if last, err = f.value(to); err != nil {
+				return 0, err
+			}
+			if first > last {
+				return 0, fmt.Errorf("%s: range %q is empty", f.name, rangePart)
+			}
+		default:
+			var err error
+			if first, err = f.value(rangePart); err != nil {
+				return 0, err
+			}
+			// A single value with a step runs to the end of the field.
+			if !hasStep {
+				last = first
+			}
+		}
+		for v := first; v <= last; v += step {
+			values |= 1 << v
+		}
+	}
+	return values, nil
+}
+
+// value parses a number or name of the field.
+func (f field) value(value string) (int, error) {
+	for index, name := range f.names {
+		if value == name {
+			return f.min + index, nil
+		}
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < f.min || number > f.max {
+		return 0, fmt.Errorf("%s: %q is not between %d and %d", f.name, value, f.min, f.max)
+	}
+	return number, nil
+}
+
+// String returns the expression as it was parsed.
+func (e Expression) String() string {
+	return e.spec
+}
+
+// Next returns the first minute after the given time that the expression
+// matches, in the location of after. It returns the zero time if the
+// expression does not match within the next years.
+func (e Expression) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(searchYears, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !e.months.has(int(t.Month())):
+			t = later(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+		case !e.matchesDay(t):
+			t = later(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+		case !e.hours.has(t.Hour()):
+			t = later(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc))
+		case !e.minutes.has(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (e Expression) matchesDay(t time.Time) bool {
+	day, weekday := e.days.has(t.Day()), e.weekdays.has(int(t.Weekday()))
+	if !e.anyDay && !e.anyWeekday {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+// later returns next, or the minute after t when a change of the UTC offset
+// made next go back in time.
+func later(t, next time.Time) time.Time {
+	if !next.After(t) {
+		return t.Add(time.Minute)
+	}
+	return next
+}
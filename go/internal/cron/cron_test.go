@@ -0,0 +1,85 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRejectsInvalidExpressions(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+		"@often",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("expression %q was accepted", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	after := time.Date(2024, time.March, 15, 10, 30, 20, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{spec: "* * * * *", want: time.Date(2024, time.March, 15, 10, 31, 0, 0, time.UTC)},
+		{spec: "0 7 * * *", want: time.Date(2024, time.March, 16, 7, 0, 0, 0, time.UTC)},
+		{spec: "@hourly", want: time.Date(2024, time.March, 15, 11, 0, 0, 0, time.UTC)},
+		{spec: "@monthly", want: time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "@yearly", want: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "*/20 * * * *", want: time.Date(2024, time.March, 15, 10, 40, 0, 0, time.UTC)},
+		{spec: "45/5 9-17 * * *", want: time.Date(2024, time.March, 15, 10, 45, 0, 0, time.UTC)},
+		{spec: "0 9 * * mon-fri", want: time.Date(2024, time.March, 18, 9, 0, 0, 0, time.UTC)},
+		{spec: "0 9 * * 7", want: time.Date(2024, time.March, 17, 9, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 feb *", want: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 1,20 * *", want: time.Date(2024, time.March, 20, 0, 0, 0, 0, time.UTC)},
+		// Restricted days of the month and of the week match either.
+		{spec: "0 0 20 * sat", want: time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 30 feb *", want: time.Time{}},
+	}
+	for _, test := range tests {
+		expression, err := Parse(test.spec)
+		if err != nil {
+			t.Fatalf("parse %q: %v", test.spec, err)
+		}
+		if got := expression.Next(after); !got.Equal(test.want) {
+			t.Errorf("next of %q: got %s, want %s", test.spec, got, test.want)
+		}
+	}
+}
+
+func TestNextHonorsLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	expression, err := Parse("0 7 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := expression.Next(time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC).In(loc))
+	if want := time.Date(2024, time.June, 2, 11, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	// 02:30 does not exist on the day that daylight saving time starts.
+	expression, err = Parse("30 2 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = expression.Next(time.Date(2024, time.March, 9, 12, 0, 0, 0, loc))
+	if want := time.Date(2024, time.March, 11, 2, 30, 0, 0, loc); !got.Equal(want) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
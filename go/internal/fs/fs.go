@@ -1,10 +1,14 @@
 package fs
 
 import (
+	"bytes"
+	"fmt"
 	"image"
 	"image/gif"
 	"image/png"
+	"io"
 	"os"
+	"path/filepath"
 )
 
 func LoadGIF(filename string) (*gif.GIF, error) {
@@ -13,7 +17,7 @@ func LoadGIF(filename string) (*gif.GIF, error) {
 		return nil, err
 	}
 	defer file.Close()
-	return gif.DecodeAll(file)
+	return DecodeGIF(file)
 }
 
 func LoadPNG(filename string) (image.Image, error) {
@@ -22,5 +26,64 @@ func LoadPNG(filename string) (image.Image, error) {
 		return nil, err
 	}
 	defer file.Close()
-	return png.Decode(file)
+	return DecodePNG(file)
+}
+
+// DecodeGIF decodes all frames of a GIF, rejecting GIFs whose logical
+// screen is larger than MaxImageSize in either dimension.
+func DecodeGIF(r io.Reader) (*gif.GIF, error) {
+	return decodeBounded(r, gif.DecodeConfig, gif.DecodeAll)
+}
+
+// DecodePNG decodes a PNG, rejecting images larger than MaxImageSize in
+// either dimension.
+func DecodePNG(r io.Reader) (image.Image, error) {
+	return decodeBounded(r, png.DecodeConfig, png.Decode)
+}
+
+// MaxImageSize is the largest width and height of a decoded image. It
+// guards against small files that decode to huge images.
+const MaxImageSize = 4096
+
+func decodeBounded[T any](r io.Reader, config func(io.Reader) (image.Config, error), decode func(io.Reader) (T, error)) (T, error) {
+	var zero T
+	var header bytes.Buffer
+	c, err := config(io.TeeReader(r, &header))
+	if err != nil {
+		return zero, err
+	}
+	if c.Width > MaxImageSize || c.Height > MaxImageSize {
+		return zero, fmt.Errorf("image is %dx%d, the maximum is %dx%d", c.Width, c.Height, MaxImageSize, MaxImageSize)
+	}
+	return decode(io.MultiReader(&header, r))
+}
+
+// WriteFileAtomic writes data to a temporary file next to filename and
+// renames it into place, so readers see either the old or the new content.
+func WriteFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), filename)
 }
@@ -0,0 +1,44 @@
+package fs
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecodePNGRejectsHugeImages(t *testing.T) {
+	for _, size := range []int{16, MaxImageSize + 1} {
+		var body bytes.Buffer
+		if err := png.Encode(&body, image.NewGray(image.Rect(0, 0, size, 1))); err != nil {
+			t.Fatal(err)
+		}
+		img, err := DecodePNG(&body)
+		if size <= MaxImageSize && (err != nil || img.Bounds().Dx() != size) {
+			t.Fatalf("%d pixels wide: unexpected result: %v", size, err)
+		}
+		if size > MaxImageSize && err == nil {
+			t.Fatalf("%d pixels wide: image was accepted", size)
+		}
+	}
+}
+
+func TestWriteFileAtomicReplacesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "logo.png")
+	for _, content := range []string{"old", "new"} {
+		if err := WriteFileAtomic(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new" {
+		t.Fatalf("unexpected content: %q %v", data, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Fatalf("temporary files were left behind: %v", entries)
+	}
+}
@@ -0,0 +1,155 @@
+package palette
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"unicode/utf16"
+)
+
+const (
+	aseColorEntry = 0x0001
+	aseGroupStart = 0xc001
+	aseGroupEnd   = 0xc002
+	// aseMaxBlockSize bounds the blocks that are read into memory. Color
+	// entries are a few dozen bytes.
+	aseMaxBlockSize = 64 << 10
+)
+
+// ParseASE reads an Adobe Swatch Exchange file. RGB, gray, CMYK and Lab
+// swatches are converted to sRGB, and the name of the first group names the
+// palette.
+func ParseASE(r io.Reader) (Palette, error) {
+	var header struct {
+		Signature [4]byte
+		Major     uint16
+		Minor     uint16
+		Blocks    uint32
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil || string(header.Signature[:]) != "ASEF" {
+		return Palette{}, fmt.Errorf("%w: missing ASEF header", ErrInvalidFile)
+	}
+
+	var p Palette
+	for block := range header.Blocks {
+		var blockHeader struct {
+			Type   uint16
+			Length uint32
+		}
+		if err := binary.Read(r, binary.BigEndian, &blockHeader); err != nil {
+			return Palette{}, fmt.Errorf("%w: block %d: %v", ErrInvalidFile, block, err)
+		}
+		if blockHeader.Length > aseMaxBlockSize {
+			return Palette{}, fmt.Errorf("%w: block %d is %d bytes long", ErrInvalidFile, block, blockHeader.Length)
+		}
+		body := make([]byte, blockHeader.Length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return Palette{}, fmt.Errorf("%w: block %d: %v", ErrInvalidFile, block, err)
+		}
+
+		switch blockHeader.Type {
+		case aseGroupStart:
+			name, err := readASEName(bytes.NewReader(body))
+			if err != nil {
+				return Palette{}, fmt.Errorf("%w: block %d: %v", ErrInvalidFile, block, err)
+			}
+			if p.Name == "" {
+				p.Name = name
+			}
+		case aseColorEntry:
+			c, err := readASEColor(bytes.NewReader(body))
+			if err != nil {
+				return Palette{}, fmt.Errorf("%w: block %d: %v", ErrInvalidFile, block, err)
+			}
+			p.Colors = append(p.Colors, c)
+		case aseGroupEnd:
+		default:
+			// Unknown blocks are skipped like other readers do.
+		}
+	}
+	if len(p.Colors) == 0 {
+		return Palette{}, fmt.Errorf("%w: no colors", ErrInvalidFile)
+	}
+	return p, nil
+}
+
+// readASEName reads a length-prefixed, null-terminated UTF-16 name.
+func readASEName(r io.Reader) (string, error) {
+	var length uint16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return "", err
+	}
+	name := make([]uint16, length)
+	if err := binary.Read(r, binary.BigEndian, name); err != nil {
+		return "", err
+	}
+	if length > 0 && name[length-1] == 0 {
+		name = name[:length-1]
+	}
+	return string(utf16.Decode(name)), nil
+}
+
+func readASEColor(r io.Reader) (color.RGBA, error) {
+	if _, err := readASEName(r); err != nil {
+		return color.RGBA{}, err
+	}
+	var model [4]byte
+	if _, err := io.ReadFull(r, model[:]); err != nil {
+		return color.RGBA{}, err
+	}
+	values := func(count int) ([]float32, error) {
+		v := make([]float32, count)
+		return v, binary.Read(r, binary.BigEndian, v)
+	}
+
+	switch string(model[:]) {
+	case "RGB ":
+		v, err := values(3)
+		return color.RGBA{R: unit(v[0]), G: unit(v[1]), B: unit(v[2]), A: 255}, err
+	case "Gray":
+		v, err := values(1)
+		return color.RGBA{R: unit(v[0]), G: unit(v[0]), B: unit(v[0]), A: 255}, err
+	case "CMYK":
+		v, err := values(4)
+		k := 1 - float64(v[3])
+		channel := func(c float32) uint8 { return unit(float32((1 - float64(c)) * k)) }
+		return color.RGBA{R: channel(v[0]), G: channel(v[1]), B: channel(v[2]), A: 255}, err
+	case "LAB ":
+		v, err := values(3)
+		return cieLab(float64(v[0])*100, float64(v[1]), float64(v[2])), err
+	default:
+		return color.RGBA{}, fmt.Errorf("unsupported color model %q", model[:])
+	}
+}
+
+// unit converts a channel from 0 to 1 to a byte.
+func unit(c float32) uint8 {
+	return uint8(math.Max(0, math.Min(1, float64(c)))*255 + 0.5)
+}
+
+// cieLab converts a CIE L*a*b* color with a D50 white point to sRGB.
+func cieLab(l, a, b float64) color.RGBA {
+	const epsilon, kappa = 216.0 / 24389, 24389.0 / 27
+	fy := (l + 16) / 116
+	fx := fy + a/500
+	fz := fy - b/200
+	inverse := func(f float64) float64 {
+		if f*f*f > epsilon {
+			return f * f * f
+		}
+		return (116*f - 16) / kappa
+	}
+	x, y, z := 0.96422*inverse(fx), inverse(fy), 0.82521*inverse(fz)
+	if l <= kappa*epsilon {
+		y = l / kappa
+	}
+	// XYZ (D50) to linear sRGB with Bradford adaptation to D65.
+	return linearRGBA(
+		3.1338561*x-1.6168667*y-0.4906146*z,
+		-0.9787684*x+1.9161415*y+0.0334540*z,
+		0.0719453*x-0.2289914*y+1.4052427*z,
+	)
+}
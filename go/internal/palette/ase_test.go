@@ -0,0 +1,83 @@
+package palette
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image/color"
+	"testing"
+	"unicode/utf16"
+)
+
+// aseBlock encodes a block of an Adobe Swatch Exchange file.
+func aseBlock(kind uint16, name string, model string, values ...float32) []byte {
+	var body bytes.Buffer
+	if kind != aseGroupEnd {
+		encoded := append(utf16.Encode([]rune(name)), 0)
+		binary.Write(&body, binary.BigEndian, uint16(len(encoded)))
+		binary.Write(&body, binary.BigEndian, encoded)
+	}
+	if kind == aseColorEntry {
+		body.WriteString(model)
+		binary.Write(&body, binary.BigEndian, values)
+		binary.Write(&body, binary.BigEndian, uint16(2))
+	}
+	var block bytes.Buffer
+	binary.Write(&block, binary.BigEndian, kind)
+	binary.Write(&block, binary.BigEndian, uint32(body.Len()))
+	block.Write(body.Bytes())
+	return block.Bytes()
+}
+
+func aseFile(blocks ...[]byte) []byte {
+	var file bytes.Buffer
+	file.WriteString("ASEF")
+	binary.Write(&file, binary.BigEndian, []uint16{1, 0})
+	binary.Write(&file, binary.BigEndian, uint32(len(blocks)))
+	for _, block := range blocks {
+		file.Write(block)
+	}
+	return file.Bytes()
+}
+
+func TestParseASE(t *testing.T) {
+	p, err := ParseASE(bytes.NewReader(aseFile(
+		aseBlock(aseGroupStart, "Brand", ""),
+		aseBlock(aseColorEntry, "Red", "RGB ", 1, 0, 0),
+		aseBlock(aseColorEntry, "Gray", "Gray", 0.5),
+		aseBlock(aseColorEntry, "Cyan", "CMYK", 1, 0, 0, 0),
+		aseBlock(aseColorEntry, "White", "LAB ", 1, 0, 0),
+		aseBlock(aseGroupEnd, "", ""),
+	)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Brand" {
+		t.Errorf("name = %q, want Brand", p.Name)
+	}
+	want := []color.RGBA{{255, 0, 0, 255}, {128, 128, 128, 255}, {0, 255, 255, 255}, {255, 255, 255, 255}}
+	if len(p.Colors) != len(want) {
+		t.Fatalf("colors = %v, want %v", p.Colors, want)
+	}
+	for i, c := range p.Colors {
+		if c != want[i] {
+			t.Errorf("color %d = %v, want %v", i, c, want[i])
+		}
+	}
+}
+
+func TestParseASERejectsInvalidFiles(t *testing.T) {
+	oversized := aseFile(aseBlock(aseColorEntry, "Red", "RGB ", 1, 0, 0))
+	binary.BigEndian.PutUint32(oversized[14:], aseMaxBlockSize+1)
+	for name, data := range map[string][]byte{
+		"header":    []byte("GIMP"),
+		"truncated": aseFile(aseBlock(aseColorEntry, "Red", "RGB ", 1, 0, 0))[:20],
+		"model":     aseFile(aseBlock(aseColorEntry, "Spot", "HSV ", 1, 0, 0)),
+		"oversized": oversized,
+		"empty":     aseFile(aseBlock(aseGroupStart, "Empty", ""), aseBlock(aseGroupEnd, "", "")),
+	} {
+		if _, err := ParseASE(bytes.NewReader(data)); !errors.Is(err, ErrInvalidFile) {
+			t.Errorf("%s: error = %v, want ErrInvalidFile", name, err)
+		}
+	}
+}
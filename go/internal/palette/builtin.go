@@ -0,0 +1,37 @@
+package palette
+
+import (
+	"image/color"
+	"sort"
+)
+
+var builtins = map[string]Palette{
+	"ocean":  {Colors: []color.RGBA{{0, 24, 72, 255}, {0, 96, 160, 255}, {0, 190, 200, 255}, {120, 230, 220, 255}}},
+	"fire":   {Colors: []color.RGBA{{96, 0, 0, 255}, {220, 40, 0, 255}, {255, 140, 0, 255}, {255, 220, 80, 255}}},
+	"forest": {Colors: []color.RGBA{{10, 60, 20, 255}, {40, 130, 40, 255}, {150, 190, 60, 255}, {60, 100, 30, 255}}},
+	"sunset": {Colors: []color.RGBA{{60, 20, 100, 255}, {200, 40, 120, 255}, {255, 110, 60, 255}, {255, 200, 90, 255}}},
+	"ice":    {Colors: []color.RGBA{{200, 240, 255, 255}, {120, 180, 255, 255}, {40, 90, 200, 255}, {230, 250, 255, 255}}},
+	"neon":   {Colors: []color.RGBA{{255, 0, 200, 255}, {120, 0, 255, 255}, {0, 220, 255, 255}, {0, 255, 120, 255}}},
+	"pastel": {Colors: []color.RGBA{{255, 179, 186, 255}, {255, 223, 186, 255}, {255, 255, 186, 255}, {186, 255, 201, 255}, {186, 225, 255, 255}}},
+	"rainbow": {
+		Colors: []color.RGBA{{255, 0, 0, 255}, {0, 255, 0, 255}, {0, 0, 255, 255}},
+		Space:  OKLCh,
+	},
+}
+
+// Builtin returns the built-in palette with the given name.
+func Builtin(name string) (Palette, bool) {
+	p, ok := builtins[name]
+	p.Name = name
+	return p, ok
+}
+
+// BuiltinNames returns the names of the built-in palettes in order.
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,58 @@
+package palette
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseGPL reads a GIMP palette. The name of the palette is taken from its
+// Name header if it has one.
+func ParseGPL(r io.Reader) (Palette, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "GIMP Palette" {
+		if err := scanner.Err(); err != nil {
+			return Palette{}, err
+		}
+		return Palette{}, fmt.Errorf("%w: missing \"GIMP Palette\" header", ErrInvalidFile)
+	}
+
+	var p Palette
+	for line := 2; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if name, ok := strings.CutPrefix(text, "Name:"); ok {
+			p.Name = strings.TrimSpace(name)
+			continue
+		}
+		if strings.HasPrefix(text, "Columns:") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 3 {
+			return Palette{}, fmt.Errorf("%w: line %d: expected red, green and blue", ErrInvalidFile, line)
+		}
+		var channels [3]uint8
+		for i := range channels {
+			value, err := strconv.ParseUint(fields[i], 10, 8)
+			if err != nil {
+				return Palette{}, fmt.Errorf("%w: line %d: %q is not a channel value from 0 to 255", ErrInvalidFile, line, fields[i])
+			}
+			channels[i] = uint8(value)
+		}
+		p.Colors = append(p.Colors, color.RGBA{R: channels[0], G: channels[1], B: channels[2], A: 255})
+	}
+	if err := scanner.Err(); err != nil {
+		return Palette{}, err
+	}
+	if len(p.Colors) == 0 {
+		return Palette{}, fmt.Errorf("%w: no colors", ErrInvalidFile)
+	}
+	return p, nil
+}
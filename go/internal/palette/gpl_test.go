@@ -0,0 +1,41 @@
+package palette
+
+import (
+	"errors"
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGPL(t *testing.T) {
+	p, err := ParseGPL(strings.NewReader(`GIMP Palette
+Name: Traffic
+Columns: 3
+# red, yellow and green
+255   0   0	Red
+255 200   0	Yellow
+
+  0 160  40
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Palette{Name: "Traffic", Colors: []color.RGBA{{255, 0, 0, 255}, {255, 200, 0, 255}, {0, 160, 40, 255}}}
+	if !reflect.DeepEqual(p, want) {
+		t.Fatalf("palette = %+v, want %+v", p, want)
+	}
+}
+
+func TestParseGPLRejectsInvalidFiles(t *testing.T) {
+	for name, text := range map[string]string{
+		"header":  "Palette\n0 0 0\n",
+		"channel": "GIMP Palette\n0 256 0\n",
+		"short":   "GIMP Palette\n0 0\n",
+		"empty":   "GIMP Palette\nName: Empty\n",
+	} {
+		if _, err := ParseGPL(strings.NewReader(text)); !errors.Is(err, ErrInvalidFile) {
+			t.Errorf("%s: error = %v, want ErrInvalidFile", name, err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package palette
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrNotFound    = errors.New("palette not found")
+	ErrInvalidFile = errors.New("invalid palette file")
+)
+
+// Extensions are the file extensions of palettes that can be imported.
+var Extensions = []string{".gpl", ".ase"}
+
+// Load imports a palette file by its extension. The palette is named after
+// the file.
+func Load(path string) (Palette, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Palette{}, err
+	}
+	defer file.Close()
+
+	var p Palette
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".gpl":
+		p, err = ParseGPL(file)
+	case ".ase":
+		p, err = ParseASE(file)
+	default:
+		return Palette{}, fmt.Errorf("%w: unknown extension %q", ErrInvalidFile, ext)
+	}
+	if err != nil {
+		return Palette{}, fmt.Errorf("load palette %s: %w", path, err)
+	}
+	p.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return p, nil
+}
+
+// Find returns the built-in palette with the given name, or imports the
+// palette file of that name from dir. Built-in palettes take precedence.
+func Find(dir, name string) (Palette, error) {
+	if p, ok := Builtin(name); ok {
+		return p, nil
+	}
+	if name == "" || name != filepath.Base(name) {
+		return Palette{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	for _, ext := range Extensions {
+		p, err := Load(filepath.Join(dir, name+ext))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		return p, err
+	}
+	return Palette{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+}
+
+// Names returns the built-in palettes and the palette files in dir in order.
+// A missing dir has no palette files.
+func Names(dir string) ([]string, error) {
+	names := BuiltinNames()
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || !slices.Contains(Extensions, strings.ToLower(ext)) {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ext))
+	}
+	slices.Sort(names)
+	return slices.Compact(names), nil
+}
@@ -0,0 +1,63 @@
+package palette
+
+import (
+	"errors"
+	"image/color"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindAndNames(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"traffic.gpl": []byte("GIMP Palette\nName: Traffic lights\n255 0 0\n0 255 0\n"),
+		"brand.ase":   aseFile(aseBlock(aseColorEntry, "Blue", "RGB ", 0, 0, 1)),
+		"ocean.gpl":   []byte("GIMP Palette\n0 0 0\n"),
+		"notes.txt":   []byte("not a palette"),
+		"broken.gpl":  []byte("GIMP Palette\n"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names, err := Names(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"brand", "broken", "fire", "forest", "ice", "neon", "ocean", "pastel", "rainbow", "sunset", "traffic"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+
+	traffic, err := Find(dir, "traffic")
+	if err != nil || traffic.Name != "traffic" || len(traffic.Colors) != 2 {
+		t.Fatalf("Find(traffic) = %+v, %v", traffic, err)
+	}
+	brand, err := Find(dir, "brand")
+	if err != nil || !reflect.DeepEqual(brand.Colors, []color.RGBA{{0, 0, 255, 255}}) {
+		t.Fatalf("Find(brand) = %+v, %v", brand, err)
+	}
+	ocean, err := Find(dir, "ocean")
+	if err != nil || len(ocean.Colors) != 4 {
+		t.Fatalf("built-in ocean was not preferred: %+v, %v", ocean, err)
+	}
+	if _, err := Find(dir, "broken"); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("Find(broken) error = %v", err)
+	}
+	for _, name := range []string{"missing", "notes", "../traffic", ""} {
+		if _, err := Find(dir, name); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Find(%q) error = %v, want ErrNotFound", name, err)
+		}
+	}
+}
+
+func TestNamesWithoutDir(t *testing.T) {
+	names, err := Names(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || !reflect.DeepEqual(names, BuiltinNames()) {
+		t.Fatalf("Names = %v, %v", names, err)
+	}
+}
@@ -0,0 +1,82 @@
+package palette
+
+import (
+	"image/color"
+	"math"
+)
+
+// lab is a color in the OKLab space by Björn Ottosson.
+type lab struct {
+	l, a, b float64
+}
+
+func toOKLab(c color.RGBA) lab {
+	r, g, b := linear(c.R), linear(c.G), linear(c.B)
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+	return lab{
+		l: 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		a: 1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		b: 0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// rgba converts the color to sRGB, clipping colors outside of its gamut.
+func (c lab) rgba() color.RGBA {
+	l := c.l + 0.3963377774*c.a + 0.2158037573*c.b
+	m := c.l - 0.1055613458*c.a - 0.0638541728*c.b
+	s := c.l - 0.0894841775*c.a - 1.2914855480*c.b
+	l, m, s = l*l*l, m*m*m, s*s*s
+	return linearRGBA(
+		4.0767416621*l-3.3077115913*m+0.2309699292*s,
+		-1.2684380046*l+2.6097574011*m-0.3413193965*s,
+		-0.0041960863*l-0.7034186147*m+1.7076147010*s,
+	)
+}
+
+// mixOKLCh blends the lightness, chroma and hue of two colors, turning the
+// hue the short way around the color wheel. Gray colors take the hue of the
+// other color.
+func mixOKLCh(from, to lab, progress float64) lab {
+	fromChroma, toChroma := math.Hypot(from.a, from.b), math.Hypot(to.a, to.b)
+	fromHue, toHue := math.Atan2(from.b, from.a), math.Atan2(to.b, to.a)
+	const gray = 1e-4
+	if fromChroma < gray {
+		fromHue = toHue
+	}
+	if toChroma < gray {
+		toHue = fromHue
+	}
+	turn := math.Remainder(toHue-fromHue, 2*math.Pi)
+	hue := fromHue + turn*progress
+	chroma := lerp(fromChroma, toChroma, progress)
+	return lab{
+		l: lerp(from.l, to.l, progress),
+		a: chroma * math.Cos(hue),
+		b: chroma * math.Sin(hue),
+	}
+}
+
+// linear decodes an sRGB channel to linear light.
+func linear(channel uint8) float64 {
+	c := float64(channel) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// linearRGBA encodes linear light channels as sRGB.
+func linearRGBA(r, g, b float64) color.RGBA {
+	encode := func(c float64) uint8 {
+		c = math.Max(0, math.Min(1, c))
+		if c <= 0.0031308 {
+			c *= 12.92
+		} else {
+			c = 1.055*math.Pow(c, 1/2.4) - 0.055
+		}
+		return uint8(c*255 + 0.5)
+	}
+	return color.RGBA{R: encode(r), G: encode(g), B: encode(b), A: 255}
+}
@@ -0,0 +1,90 @@
+// Package palette provides named color gradients that renderers sample
+// colors from. Palettes are built in or imported from GIMP (.gpl) and Adobe
+// Swatch Exchange (.ase) files, and are interpolated in a perceptual color
+// space.
+package palette
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// Space is the color space a gradient is interpolated in.
+type Space int
+
+const (
+	// OKLab blends colors with even perceived steps in lightness and
+	// chroma.
+	OKLab Space = iota
+	// OKLCh blends hue around the color wheel, which keeps blends between
+	// distant hues saturated.
+	OKLCh
+	// SRGB blends the encoded channel values.
+	SRGB
+)
+
+func (s Space) String() string {
+	switch s {
+	case OKLab:
+		return "oklab"
+	case OKLCh:
+		return "oklch"
+	case SRGB:
+		return "srgb"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseSpace(name string) (Space, error) {
+	for s := OKLab; s.String() != "unknown"; s++ {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("color space %q is not one of oklab, oklch or srgb", name)
+}
+
+// Palette is a cyclic gradient through its colors, which are spaced evenly.
+type Palette struct {
+	Name   string
+	Colors []color.RGBA
+	Space  Space
+}
+
+// At returns the color at position t of the gradient, wrapping around
+// outside of 0 to 1. A palette without colors is black.
+func (p Palette) At(t float64) color.RGBA {
+	if len(p.Colors) == 0 {
+		return color.RGBA{A: 255}
+	}
+	t -= math.Floor(t)
+	position := t * float64(len(p.Colors))
+	index := int(position) % len(p.Colors)
+	return p.mix(p.Colors[index], p.Colors[(index+1)%len(p.Colors)], position-math.Floor(position))
+}
+
+// mix blends two colors in the color space of the palette.
+func (p Palette) mix(from, to color.RGBA, progress float64) color.RGBA {
+	switch p.Space {
+	case SRGB:
+		channel := func(a, b uint8) uint8 {
+			return uint8(float64(a) + (float64(b)-float64(a))*progress + 0.5)
+		}
+		return color.RGBA{R: channel(from.R, to.R), G: channel(from.G, to.G), B: channel(from.B, to.B), A: 255}
+	case OKLCh:
+		return mixOKLCh(toOKLab(from), toOKLab(to), progress).rgba()
+	default:
+		a, b := toOKLab(from), toOKLab(to)
+		return lab{
+			l: lerp(a.l, b.l, progress),
+			a: lerp(a.a, b.a, progress),
+			b: lerp(a.b, b.b, progress),
+		}.rgba()
+	}
+}
+
+func lerp(a, b, progress float64) float64 {
+	return a + (b-a)*progress
+}
@@ -0,0 +1,78 @@
+package palette
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestAtHitsColorsAndWraps(t *testing.T) {
+	p := Palette{Colors: []color.RGBA{{255, 0, 0, 255}, {0, 0, 255, 255}}}
+	for _, space := range []Space{OKLab, OKLCh, SRGB} {
+		p.Space = space
+		for _, tc := range []struct {
+			t    float64
+			want color.RGBA
+		}{
+			{0, color.RGBA{255, 0, 0, 255}},
+			{0.5, color.RGBA{0, 0, 255, 255}},
+			{1, color.RGBA{255, 0, 0, 255}},
+			{-0.5, color.RGBA{0, 0, 255, 255}},
+		} {
+			if got := p.At(tc.t); got != tc.want {
+				t.Errorf("%s: At(%g) = %v, want %v", space, tc.t, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestOKLabRoundTrip(t *testing.T) {
+	for _, c := range []color.RGBA{
+		{0, 0, 0, 255}, {255, 255, 255, 255}, {255, 0, 0, 255}, {12, 200, 99, 255}, {40, 90, 200, 255},
+	} {
+		if got := toOKLab(c).rgba(); got != c {
+			t.Errorf("round trip of %v = %v", c, got)
+		}
+	}
+}
+
+func TestPerceptualInterpolation(t *testing.T) {
+	black, white := color.RGBA{0, 0, 0, 255}, color.RGBA{255, 255, 255, 255}
+	gray := Palette{Colors: []color.RGBA{black, white}}
+	// The perceptual middle gray has half the lightness, which is darker
+	// than the average of the encoded channels.
+	middle := gray.At(0.25)
+	if l := toOKLab(middle).l; math.Abs(l-0.5) > 0.01 {
+		t.Errorf("OKLab middle gray %v has lightness %g", middle, l)
+	}
+	if srgb := (Palette{Colors: gray.Colors, Space: SRGB}).At(0.25); middle.R >= srgb.R {
+		t.Errorf("OKLab middle gray %v is not darker than sRGB middle gray %v", middle, srgb)
+	}
+
+	// Blending complementary hues in OKLCh keeps the chroma instead of
+	// passing through gray.
+	hues := Palette{Colors: []color.RGBA{{255, 0, 0, 255}, {0, 255, 255, 255}}}
+	lab := toOKLab(hues.At(0.25))
+	hues.Space = OKLCh
+	lch := toOKLab(hues.At(0.25))
+	if lab.a*lab.a+lab.b*lab.b >= lch.a*lch.a+lch.b*lch.b {
+		t.Errorf("OKLCh blend %v is not more saturated than OKLab blend %v", lch, lab)
+	}
+}
+
+func TestParseSpace(t *testing.T) {
+	for _, space := range []Space{OKLab, OKLCh, SRGB} {
+		if got, err := ParseSpace(space.String()); err != nil || got != space {
+			t.Errorf("ParseSpace(%q) = %v, %v", space, got, err)
+		}
+	}
+	if _, err := ParseSpace("hsv"); err == nil {
+		t.Error("ParseSpace accepted an unknown space")
+	}
+}
+
+func TestEmptyPaletteIsBlack(t *testing.T) {
+	if got := (Palette{}).At(0.3); got != (color.RGBA{A: 255}) {
+		t.Fatalf("At = %v", got)
+	}
+}
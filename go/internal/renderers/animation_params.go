@@ -0,0 +1,266 @@
+package renderers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers/animation"
+)
+
+type ParamType int
+
+const (
+	ParamFloat ParamType = iota
+	ParamInt
+	ParamEnum
+)
+
+func (t ParamType) String() string {
+	switch t {
+	case ParamFloat:
+		return "float"
+	case ParamInt:
+		return "int"
+	case ParamEnum:
+		return "enum"
+	default:
+		return "unknown"
+	}
+}
+
+// ParamSpec describes a parameter of an animation. Min and Max bound
+// numbers, and Values lists the choices of enums.
+type ParamSpec struct {
+	Name        string
+	Type        ParamType
+	Default     string
+	Min, Max    float64
+	Values      []string
+	Description string
+}
+
+func (p ParamSpec) validate(value string) error {
+	switch p.Type {
+	case ParamFloat:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(number) {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		return p.validateRange(number)
+	case ParamInt:
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+		return p.validateRange(float64(number))
+	case ParamEnum:
+		if !slices.Contains(p.Values, value) {
+			return fmt.Errorf("%q is not one of %s", value, strings.Join(p.Values, ", "))
+		}
+	}
+	return nil
+}
+
+func (p ParamSpec) validateRange(number float64) error {
+	if number < p.Min || number > p.Max {
+		return fmt.Errorf("must be between %g and %g", p.Min, p.Max)
+	}
+	return nil
+}
+
+var (
+	speedParam = ParamSpec{
+		Name: "speed", Type: ParamFloat, Default: "1", Min: 0.1, Max: 10,
+		Description: "Playback speed relative to the original animation",
+	}
+	paletteParam = ParamSpec{
+		Name: "palette", Type: ParamEnum, Default: DefaultPalette,
+		Description: "Palette the animation samples its colors from",
+	}
+	seedParam = ParamSpec{
+		Name: "seed", Type: ParamInt, Default: "0", Min: 0, Max: math.MaxInt32,
+		Description: "Seed of the random numbers; 0 uses a new seed every time",
+	}
+)
+
+// animationSchemas lists the parameters each animation supports. Every
+// animation has a speed and a palette, and seeds apply to those that use
+// random numbers.
+var animationSchemas = map[animation.Animation][]ParamSpec{
+	animation.Aurora:             {speedParam, paletteParam},
+	animation.BlobbyFusion:       {speedParam, paletteParam, seedParam},
+	animation.Checkerboard:       {speedParam, paletteParam},
+	animation.ColorWave:          {speedParam, paletteParam},
+	animation.Firefly:            {speedParam, paletteParam, seedParam},
+	animation.Kaleidoscope:       {speedParam, paletteParam},
+	animation.LavaLamp:           {speedParam, paletteParam},
+	animation.Lightning:          {speedParam, paletteParam, seedParam},
+	animation.Mandelbrot:         {speedParam, paletteParam},
+	animation.MatrixRain:         {speedParam, paletteParam, seedParam},
+	animation.Nebula:             {speedParam, paletteParam},
+	animation.Plasma:             {speedParam, paletteParam},
+	animation.RadarSweep:         {speedParam, paletteParam},
+	animation.Ripple:             {speedParam, paletteParam},
+	animation.Spectrum:           {speedParam, paletteParam},
+	animation.Spiral:             {speedParam, paletteParam},
+	animation.Starfield:          {speedParam, paletteParam, seedParam},
+	animation.Tunnel:             {speedParam, paletteParam},
+	animation.Vortex:             {speedParam, paletteParam},
+	animation.PixelBloom:         {speedParam, paletteParam},
+	animation.RGBFlow:            {speedParam, paletteParam},
+	animation.Glitch:             {speedParam, paletteParam, seedParam},
+	animation.HypnoticRings:      {speedParam, paletteParam},
+	animation.SpinningGrid:       {speedParam, paletteParam},
+	animation.HexPulse:           {speedParam, paletteParam},
+	animation.SnakeTrail:         {speedParam, paletteParam, seedParam},
+	animation.ExplosionBurst:     {speedParam, paletteParam},
+	animation.BeatGrid:           {speedParam, paletteParam},
+	animation.AudioOrbit:         {speedParam, paletteParam},
+	animation.AuroraCurtains:     {speedParam, paletteParam},
+	animation.UlamSpiral:         {speedParam, paletteParam},
+	animation.GameOfLife:         {speedParam, paletteParam, seedParam},
+	animation.VectorFieldFlow:    {speedParam, paletteParam, seedParam},
+	animation.SierpinskiTriangle: {speedParam, paletteParam},
+	animation.FluidDream:         {speedParam, paletteParam},
+	animation.FluidRainbow:       {speedParam, paletteParam},
+	animation.OrbitingMetaballs:  {speedParam, paletteParam},
+	animation.MarbleShader:       {speedParam, paletteParam},
+	animation.SolarSystem:        {speedParam, paletteParam},
+	animation.Darts_180:          {speedParam, paletteParam},
+	animation.Pacman:             {speedParam, paletteParam},
+}
+
+// AnimationSchema returns the parameters of the named animation.
+func AnimationSchema(name string) ([]ParamSpec, error) {
+	value, err := animation.AnimationString(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: animation %q", ErrUnknownContent, name)
+	}
+	// Palette files can be added while the server runs, so the choices are
+	// listed on every call.
+	schema := slices.Clone(animationSchemas[value])
+	for i := range schema {
+		if schema[i].Name == paletteParam.Name {
+			schema[i].Values = PaletteNames()
+		}
+	}
+	return schema, nil
+}
+
+// AnimationParams are the parsed parameters of an animation. The zero value
+// plays the animation as designed.
+type AnimationParams struct {
+	Speed   float64
+	Palette string
+	Seed    int64
+}
+
+// ParseAnimationParams validates command parameters against the schema of
+// the named animation. Missing parameters use their defaults.
+func ParseAnimationParams(name string, params map[string]string) (AnimationParams, error) {
+	schema, err := AnimationSchema(name)
+	if err != nil {
+		return AnimationParams{}, err
+	}
+	parsed := AnimationParams{Speed: 1, Palette: DefaultPalette}
+	for key, value := range params {
+		index := slices.IndexFunc(schema, func(spec ParamSpec) bool { return spec.Name == key })
+		if index < 0 {
+			return AnimationParams{}, fmt.Errorf("%w: animation %q has no parameter %q", ErrInvalidParameter, name, key)
+		}
+		if err := schema[index].validate(value); err != nil {
+			return AnimationParams{}, fmt.Errorf("%w: animation parameter %q: %v", ErrInvalidParameter, key, err)
+		}
+		switch key {
+		case "speed":
+			parsed.Speed, _ = strconv.ParseFloat(value, 64)
+		case "palette":
+			parsed.Palette = value
+		case "seed":
+			parsed.Seed, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+	return parsed, nil
+}
+
+// animationFrameRate is the frame rate that the per-frame motion of
+// animations was designed for.
+const animationFrameRate = 30
+
+// maxCatchUpSteps bounds the steps an animation takes in one frame after it
+// fell behind.
+const maxCatchUpSteps = 32
+
+// animated is embedded by animation renderers to apply their parameters.
+type animated struct {
+	paletted
+	paced
+	params AnimationParams
+	// last is the time of the previous frame and stepped the number of
+	// steps taken so far.
+	last    time.Duration
+	stepped int64
+}
+
+func (a *animated) setAnimationParams(params AnimationParams) {
+	a.params = params
+}
+
+func (a *animated) speed() float64 {
+	if a.params.Speed <= 0 {
+		return 1
+	}
+	return a.params.Speed
+}
+
+// run draws frames with the pacer of the display, starting the animation
+// clock over.
+func (a *animated) run(ctx context.Context, frame FrameFunc) error {
+	a.last, a.stepped = 0, 0
+	return a.paced.run(ctx, frame)
+}
+
+// seconds returns the animation time in seconds at frame time t, scaled by
+// the speed.
+func (a *animated) seconds(t time.Duration) float64 {
+	return t.Seconds() * a.speed()
+}
+
+// advance returns how many frames of animationFrameRate passed since the
+// previous frame, scaled by the speed. Motion that moves a fixed distance
+// per frame is multiplied by it to keep its pace at any frame rate.
+func (a *animated) advance(t time.Duration) float64 {
+	elapsed := t - a.last
+	a.last = t
+	return elapsed.Seconds() * animationFrameRate * a.speed()
+}
+
+// steps returns how many steps of the given interval, scaled by the speed,
+// are due at frame time t. Animations that update in discrete steps take
+// them independently of the frame rate.
+func (a *animated) steps(t, every time.Duration) int {
+	total := int64(t / a.interval(every))
+	due := total - a.stepped
+	a.stepped = total
+	return int(min(due, maxCatchUpSteps))
+}
+
+// interval scales the time between animation steps by the speed.
+func (a *animated) interval(d time.Duration) time.Duration {
+	return time.Duration(float64(d) / a.speed())
+}
+
+// random returns the source of random numbers for one run of the animation.
+func (a *animated) random() *rand.Rand {
+	seed := a.params.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
@@ -0,0 +1,121 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/palette"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers/animation"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestEveryAnimationHasSpeed(t *testing.T) {
+	for _, name := range animation.AnimationStrings() {
+		schema, err := AnimationSchema(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(schema) == 0 || schema[0].Name != "speed" {
+			t.Errorf("%s: unexpected schema %v", name, schema)
+		}
+	}
+	if _, err := AnimationSchema("unknown"); !errors.Is(err, ErrUnknownContent) {
+		t.Fatalf("unexpected error for an unknown animation: %v", err)
+	}
+}
+
+func TestParseAnimationParams(t *testing.T) {
+	params, err := ParseAnimationParams("glitch", map[string]string{"speed": "0.5", "palette": "ocean", "seed": "7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params != (AnimationParams{Speed: 0.5, Palette: "ocean", Seed: 7}) {
+		t.Fatalf("unexpected params: %#v", params)
+	}
+	if params, err := ParseAnimationParams("plasma", nil); err != nil || params != (AnimationParams{Speed: 1, Palette: DefaultPalette}) {
+		t.Fatalf("unexpected defaults: %#v, %v", params, err)
+	}
+
+	for _, invalid := range []map[string]string{
+		{"speed": "fast"},
+		{"speed": "20"},
+		{"palette": "mud"},
+		{"seed": "1.5"},
+		{"fps": "60"},
+	} {
+		if _, err := ParseAnimationParams("glitch", invalid); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("%v: unexpected error %v", invalid, err)
+		}
+	}
+	if _, err := ParseAnimationParams("plasma", map[string]string{"seed": "7"}); !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("seed was accepted by an animation without random numbers: %v", err)
+	}
+}
+
+func TestAnimatedAppliesParams(t *testing.T) {
+	var a animated
+	if r, g, b := a.hsv(0, 1, 1); r != 1 || g != 0 || b != 0 {
+		t.Fatalf("default palette changed the hue: %v %v %v", r, g, b)
+	}
+	a.setAnimationParams(AnimationParams{Speed: 2, Palette: "fire", Seed: 3})
+	fire, _ := palette.Builtin("fire")
+	a.setPalette(&fire)
+	if r, g, b := a.hsv(0, 1, 0.5); r != 0.5*96.0/255 || g != 0 || b != 0 {
+		t.Fatalf("unexpected palette color: %v %v %v", r, g, b)
+	}
+	if a.interval(100) != 50 {
+		t.Fatalf("interval was not scaled: %v", a.interval(100))
+	}
+	if a.random().Int63() != a.random().Int63() {
+		t.Fatal("seeded random numbers differ between runs")
+	}
+}
+
+func TestPalettedFallsBackWithoutPalette(t *testing.T) {
+	var p paletted
+	fallback := color.RGBA{R: 10, G: 20, B: 30, A: 255}
+	if got := p.colorAt(0.3, fallback); got != fallback {
+		t.Fatalf("unexpected color without palette: %v", got)
+	}
+	if r, g, b := p.rgbAt(0.3, 0.1, 0.2, 0.3); r != 0.1 || g != 0.2 || b != 0.3 {
+		t.Fatalf("unexpected channels without palette: %v %v %v", r, g, b)
+	}
+
+	p.setPalette(&palette.Palette{Colors: []color.RGBA{{R: 255, A: 255}, {B: 255, A: 255}}})
+	if got := p.colorAt(0.5, fallback); got != (color.RGBA{B: 255, A: 255}) {
+		t.Fatalf("unexpected palette color: %v", got)
+	}
+	if r, g, b := p.rgbAt(0, 0.1, 0.2, 0.3); r != 1 || g != 0 || b != 0 {
+		t.Fatalf("unexpected palette channels: %v %v %v", r, g, b)
+	}
+}
+
+func TestPrepareAnimationPassesParams(t *testing.T) {
+	screen := rgbmatrix.NewScreen(newPrepareMatrix(16, 16))
+	prepared, err := prepare(context.Background(), Command{
+		Type: TypeAnimation, Name: "plasma", Params: map[string]string{"speed": "3"},
+	}, screen, rgbmatrix.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renderer := prepared.renderer.(*PlasmaRenderer); renderer.params.Speed != 3 || renderer.palette != nil {
+		t.Fatalf("params were not passed to the renderer: %#v", renderer.params)
+	}
+	prepared, err = prepare(context.Background(), Command{
+		Type: TypeAnimation, Name: "pacman", Params: map[string]string{"palette": "neon"},
+	}, screen, rgbmatrix.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renderer := prepared.renderer.(*PacmanRenderer); renderer.palette == nil || renderer.palette.Name != "neon" {
+		t.Fatalf("palette was not passed to the renderer: %v", renderer.palette)
+	}
+	_, err = prepare(context.Background(), Command{
+		Type: TypeAnimation, Name: "plasma", Params: map[string]string{"speed": "0"},
+	}, screen, rgbmatrix.Config{})
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
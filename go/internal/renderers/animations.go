@@ -2,8 +2,8 @@ package renderers
 
 import (
 	"context"
+	"image/color"
 	"math"
-	"math/rand"
 	"time"
 
 	"github.com/fogleman/gg"
@@ -12,6 +12,7 @@ import (
 
 type BeatGridRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func BeatGrid(screen *rgbmatrix.Screen) *BeatGridRenderer {
@@ -19,43 +20,37 @@ func BeatGrid(screen *rgbmatrix.Screen) *BeatGridRenderer {
 }
 
 func (r *BeatGridRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
 	width := float64(dc.Width())
 	height := float64(dc.Height())
-	start := time.Now()
 
 	gridSize := 8.0 // size of each square
 	cols := int(width / gridSize)
 	rows := int(height / gridSize)
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			t := time.Since(start).Seconds()
-			dc.Clear()
-
-			for y := 0; y < rows; y++ {
-				for x := 0; x < cols; x++ {
-					phase := float64(x+y) * 0.6
-					brightness := 0.4 + 0.6*math.Sin(t*4+phase)
-					hue := math.Mod(t*0.15+float64(x+y)*0.05, 1.0)
-					r, g, b := hsvToRGB(hue, 1.0, brightness)
-					dc.SetRGB(r, g, b)
-					dc.DrawRectangle(float64(x)*gridSize, float64(y)*gridSize, gridSize-1, gridSize-1)
-					dc.Fill()
-				}
+	return r.run(ctx, func(elapsed time.Duration) error {
+		t := r.seconds(elapsed)
+		dc.Clear()
+
+		for y := 0; y < rows; y++ {
+			for x := 0; x < cols; x++ {
+				phase := float64(x+y) * 0.6
+				brightness := 0.4 + 0.6*math.Sin(t*4+phase)
+				hue := math.Mod(t*0.15+float64(x+y)*0.05, 1.0)
+				red, green, blue := r.hsv(hue, 1.0, brightness)
+				dc.SetRGB(red, green, blue)
+				dc.DrawRectangle(float64(x)*gridSize, float64(y)*gridSize, gridSize-1, gridSize-1)
+				dc.Fill()
 			}
-
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
 		}
-	}
+
+		return r.screen.Present()
+	})
 }
 
 type RGBFlowRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func RGBFlow(screen *rgbmatrix.Screen) *RGBFlowRenderer {
@@ -63,33 +58,18 @@ func RGBFlow(screen *rgbmatrix.Screen) *RGBFlowRenderer {
 }
 
 func (r *RGBFlowRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w, h := float64(dc.Width()), float64(dc.Height())
-	start := time.Now()
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			t := time.Since(start).Seconds()
-			dc.Clear()
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					phase := math.Sin(x*0.2 + y*0.2 + t)
-					hue := math.Mod((phase+1)/2+0.5*t, 1.0)
-					rVal, gVal, bVal := hsvToRGB(hue, 1.0, 1.0)
-					dc.SetRGB(math.Max(rVal, 0.1), math.Max(gVal, 0.1), math.Max(bVal, 0.1))
-					dc.SetPixel(int(x), int(y))
-				}
-			}
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
-		}
-	}
+	return r.runShader(ctx, r.screen, func(px, py int, t float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		phase := math.Sin(x*0.2 + y*0.2 + t)
+		hue := math.Mod((phase+1)/2+0.5*t, 1.0)
+		rVal, gVal, bVal := r.hsv(hue, 1.0, 1.0)
+		return rgb(math.Max(rVal, 0.1), math.Max(gVal, 0.1), math.Max(bVal, 0.1))
+	})
 }
 
 type PixelBloomRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func PixelBloom(screen *rgbmatrix.Screen) *PixelBloomRenderer {
@@ -97,38 +77,25 @@ func PixelBloom(screen *rgbmatrix.Screen) *PixelBloomRenderer {
 }
 
 func (r *PixelBloomRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w, h := float64(dc.Width()), float64(dc.Height())
+	bounds := r.screen.Frame().Bounds()
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
 	cx, cy := w/2, h/2
-	start := time.Now()
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			t := time.Since(start).Seconds()
-			dc.Clear()
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					dx := x - cx
-					dy := y - cy
-					dist := math.Hypot(dx, dy)
-					pulse := math.Sin(dist*0.2 - t*2)
-					hue := math.Mod(0.6+dist*0.01+t*0.05, 1.0)
-					bright := 0.5 + 0.5*pulse
-					rVal, gVal, bVal := hsvToRGB(hue, 1.0, bright)
-					dc.SetRGB(math.Max(rVal, 0.1), math.Max(gVal, 0.1), math.Max(bVal, 0.1))
-					dc.SetPixel(int(x), int(y))
-				}
-			}
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
-		}
-	}
+	return r.runShader(ctx, r.screen, func(px, py int, t float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		dx := x - cx
+		dy := y - cy
+		dist := math.Hypot(dx, dy)
+		pulse := math.Sin(dist*0.2 - t*2)
+		hue := math.Mod(0.6+dist*0.01+t*0.05, 1.0)
+		bright := 0.5 + 0.5*pulse
+		rVal, gVal, bVal := r.hsv(hue, 1.0, bright)
+		return rgb(math.Max(rVal, 0.1), math.Max(gVal, 0.1), math.Max(bVal, 0.1))
+	})
 }
 
 type GlitchRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Glitch(screen *rgbmatrix.Screen) *GlitchRenderer {
@@ -136,37 +103,35 @@ func Glitch(screen *rgbmatrix.Screen) *GlitchRenderer {
 }
 
 func (r *GlitchRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
 	w, h := dc.Width(), dc.Height()
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	for {
-		select {
-		case <-ctx.Done():
+	rng := r.random()
+	return r.run(ctx, func(elapsed time.Duration) error {
+		if r.steps(elapsed, 30*time.Millisecond) == 0 && elapsed > 0 {
 			return nil
-		default:
-			dc.Clear()
-			baseHue := rand.Float64()
-			for y := 0; y < h; y++ {
-				offset := 0
-				if rng.Float64() < 0.2 {
-					offset = rng.Intn(4) - 2 // -2 to 2 pixel horizontal glitch
-				}
-				hue := math.Mod(baseHue+float64(y)/float64(h), 1.0)
-				rVal, gVal, bVal := hsvToRGB(hue, 1.0, 1.0)
-				for x := 0; x < w; x++ {
-					tx := (x + offset + w) % w
-					dc.SetRGB(rVal, gVal, bVal)
-					dc.SetPixel(tx, y)
-				}
+		}
+		dc.Clear()
+		baseHue := rng.Float64()
+		for y := 0; y < h; y++ {
+			offset := 0
+			if rng.Float64() < 0.2 {
+				offset = rng.Intn(4) - 2 // -2 to 2 pixel horizontal glitch
+			}
+			hue := math.Mod(baseHue+float64(y)/float64(h), 1.0)
+			rVal, gVal, bVal := r.hsv(hue, 1.0, 1.0)
+			for x := 0; x < w; x++ {
+				tx := (x + offset + w) % w
+				dc.SetRGB(rVal, gVal, bVal)
+				dc.SetPixel(tx, y)
 			}
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
 		}
-	}
+		return r.screen.Present()
+	})
 }
 
 type RadarSweepRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func RadarSweep(screen *rgbmatrix.Screen) *RadarSweepRenderer {
@@ -174,65 +139,59 @@ func RadarSweep(screen *rgbmatrix.Screen) *RadarSweepRenderer {
 }
 
 func (r *RadarSweepRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
 	width := float64(dc.Width())
 	height := float64(dc.Height())
 	cx := width / 2
 	cy := height / 2
-	start := time.Now()
 
 	decay := make([][]float64, int(height))
 	for y := range decay {
 		decay[y] = make([]float64, int(width))
 	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.SetRGB(0, 0, 0)
-			dc.Clear()
-
-			// Update decay values
-			for y := 0; y < int(height); y++ {
-				for x := 0; x < int(width); x++ {
-					decay[y][x] *= 0.94
-				}
+	return r.run(ctx, func(elapsed time.Duration) error {
+		now := r.seconds(elapsed)
+		dc.SetRGB(0, 0, 0)
+		dc.Clear()
+
+		// Update decay values
+		for y := 0; y < int(height); y++ {
+			for x := 0; x < int(width); x++ {
+				decay[y][x] *= 0.94
 			}
+		}
 
-			// Draw radar sweep beam
-			sweepAngle := now * 1.5
-			for d := 0.0; d < math.Min(width, height)/2; d += 0.3 {
-				x := cx + math.Cos(sweepAngle)*d
-				y := cy + math.Sin(sweepAngle)*d
-				if x >= 0 && x < width && y >= 0 && y < height {
-					ix, iy := int(x), int(y)
-					decay[iy][ix] = 1.0
-				}
+		// Draw radar sweep beam
+		sweepAngle := now * 1.5
+		for d := 0.0; d < math.Min(width, height)/2; d += 0.3 {
+			x := cx + math.Cos(sweepAngle)*d
+			y := cy + math.Sin(sweepAngle)*d
+			if x >= 0 && x < width && y >= 0 && y < height {
+				ix, iy := int(x), int(y)
+				decay[iy][ix] = 1.0
 			}
+		}
 
-			// Render all pixels with current decay brightness
-			for y := 0; y < int(height); y++ {
-				for x := 0; x < int(width); x++ {
-					brightness := decay[y][x]
-					if brightness > 0.01 {
-						r, g, b := hsvToRGB(0.33, 1.0, brightness)
-						dc.SetRGB(r, g, b)
-						dc.SetPixel(x, y)
-					}
+		// Render all pixels with current decay brightness
+		for y := 0; y < int(height); y++ {
+			for x := 0; x < int(width); x++ {
+				brightness := decay[y][x]
+				if brightness > 0.01 {
+					red, green, blue := r.hsv(0.33, 1.0, brightness)
+					dc.SetRGB(red, green, blue)
+					dc.SetPixel(x, y)
 				}
 			}
-
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
 		}
-	}
+
+		return r.screen.Present()
+	})
 }
 
 type NebulaRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Nebula(screen *rgbmatrix.Screen) *NebulaRenderer {
@@ -240,54 +199,40 @@ func Nebula(screen *rgbmatrix.Screen) *NebulaRenderer {
 }
 
 func (r *NebulaRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w := float64(dc.Width())
-	h := float64(dc.Height())
-	start := time.Now()
+	bounds := r.screen.Frame().Bounds()
+	w := float64(bounds.Dx())
+	h := float64(bounds.Dy())
 
 	cx := w / 2
 	cy := h / 2
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.Clear()
-
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					// Normalize coordinates
-					nx := x / w
-					ny := y / h
-					dist := math.Hypot(x-cx, y-cy)
-
-					v := math.Sin(nx*10+now) +
-						math.Sin(ny*10-now*1.3) +
-						math.Sin((nx+ny)*10+now*1.1) +
-						math.Sin(dist*0.25-now*0.7)
-
-					hue := math.Mod(0.6+v*0.05+now*0.01, 1.0)
-					brightness := 0.3 + 0.7*(0.5+0.5*math.Sin(v+now))
-
-					r, g, b := hsvToRGB(hue, 1.0, brightness)
-					r = math.Max(r, 0.1)
-					g = math.Max(g, 0.1)
-					b = math.Max(b, 0.1)
-
-					dc.SetRGB(r, g, b)
-					dc.SetPixel(int(x), int(y))
-				}
-			}
+	return r.runShader(ctx, r.screen, func(px, py int, now float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		// Normalize coordinates
+		nx := x / w
+		ny := y / h
+		dist := math.Hypot(x-cx, y-cy)
 
-			r.screen.ShowImage(ctx, dc.Image())
-		}
-	}
+		v := math.Sin(nx*10+now) +
+			math.Sin(ny*10-now*1.3) +
+			math.Sin((nx+ny)*10+now*1.1) +
+			math.Sin(dist*0.25-now*0.7)
+
+		hue := math.Mod(0.6+v*0.05+now*0.01, 1.0)
+		brightness := 0.3 + 0.7*(0.5+0.5*math.Sin(v+now))
+
+		red, green, blue := r.hsv(hue, 1.0, brightness)
+		red = math.Max(red, 0.1)
+		green = math.Max(green, 0.1)
+		blue = math.Max(blue, 0.1)
+
+		return rgb(red, green, blue)
+	})
 }
 
 type AuroraRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Aurora(screen *rgbmatrix.Screen) *AuroraRenderer {
@@ -295,47 +240,33 @@ func Aurora(screen *rgbmatrix.Screen) *AuroraRenderer {
 }
 
 func (r *AuroraRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	width := float64(dc.Width())
-	height := float64(dc.Height())
-	start := time.Now()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.Clear()
-
-			for y := 0.0; y < height; y++ {
-				for x := 0.0; x < width; x++ {
-					xf := x / width * 2 * math.Pi
-					yf := y / height
+	bounds := r.screen.Frame().Bounds()
+	width := float64(bounds.Dx())
+	height := float64(bounds.Dy())
 
-					wave := math.Sin(xf*2 + now*1.5)
-					curve := math.Sin(yf*4*math.Pi + wave + now)
+	return r.runShader(ctx, r.screen, func(px, py int, now float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		xf := x / width * 2 * math.Pi
+		yf := y / height
 
-					hue := math.Mod(0.4+0.2*wave+now*0.01, 1.0)
-					brightness := 0.3 + 0.7*(0.5+0.5*curve)
+		wave := math.Sin(xf*2 + now*1.5)
+		curve := math.Sin(yf*4*math.Pi + wave + now)
 
-					r, g, b := hsvToRGB(hue, 1.0, brightness)
-					r = math.Max(r, 0.1)
-					g = math.Max(g, 0.1)
-					b = math.Max(b, 0.1)
+		hue := math.Mod(0.4+0.2*wave+now*0.01, 1.0)
+		brightness := 0.3 + 0.7*(0.5+0.5*curve)
 
-					dc.SetRGB(r, g, b)
-					dc.SetPixel(int(x), int(y))
-				}
-			}
+		red, green, blue := r.hsv(hue, 1.0, brightness)
+		red = math.Max(red, 0.1)
+		green = math.Max(green, 0.1)
+		blue = math.Max(blue, 0.1)
 
-			r.screen.ShowImage(ctx, dc.Image())
-		}
-	}
+		return rgb(red, green, blue)
+	})
 }
 
 type LavaLampRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func LavaLamp(screen *rgbmatrix.Screen) *LavaLampRenderer {
@@ -343,48 +274,34 @@ func LavaLamp(screen *rgbmatrix.Screen) *LavaLampRenderer {
 }
 
 func (r *LavaLampRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w := float64(dc.Width())
-	h := float64(dc.Height())
-	start := time.Now()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.Clear()
+	bounds := r.screen.Frame().Bounds()
+	w := float64(bounds.Dx())
+	h := float64(bounds.Dy())
 
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					xf := x / w * 2 * math.Pi
-					yf := y / h * 2 * math.Pi
+	return r.runShader(ctx, r.screen, func(px, py int, now float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		xf := x / w * 2 * math.Pi
+		yf := y / h * 2 * math.Pi
 
-					value := math.Sin(xf*2+now) +
-						math.Sin(yf*3+now*0.7) +
-						math.Sin((xf+yf)*2+now*1.3)
+		value := math.Sin(xf*2+now) +
+			math.Sin(yf*3+now*0.7) +
+			math.Sin((xf+yf)*2+now*1.3)
 
-					hue := math.Mod((value+3)/6+now*0.02, 1.0)
-					brightness := 0.4 + 0.6*math.Sin(value*3+now)
+		hue := math.Mod((value+3)/6+now*0.02, 1.0)
+		brightness := 0.4 + 0.6*math.Sin(value*3+now)
 
-					r, g, b := hsvToRGB(hue, 0.8, brightness)
-					r = math.Max(r, 0.1)
-					g = math.Max(g, 0.1)
-					b = math.Max(b, 0.1)
+		red, green, blue := r.hsv(hue, 0.8, brightness)
+		red = math.Max(red, 0.1)
+		green = math.Max(green, 0.1)
+		blue = math.Max(blue, 0.1)
 
-					dc.SetRGB(r, g, b)
-					dc.SetPixel(int(x), int(y))
-				}
-			}
-
-			r.screen.ShowImage(ctx, dc.Image())
-		}
-	}
+		return rgb(red, green, blue)
+	})
 }
 
 type ColorWaveRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func ColorWave(screen *rgbmatrix.Screen) *ColorWaveRenderer {
@@ -392,49 +309,31 @@ func ColorWave(screen *rgbmatrix.Screen) *ColorWaveRenderer {
 }
 
 func (r *ColorWaveRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w := float64(dc.Width())
-	h := float64(dc.Height())
+	return r.runShader(ctx, r.screen, func(px, py int, now float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		offset := math.Sin(now*0.5) * 20 // Directional offset oscillates over time
+		xx := x + offset
+		yy := y + offset
 
-	start := time.Now()
+		// Generate evolving pattern using sine waves
+		red := 0.5 + 0.5*math.Sin((xx+now*30)*0.1)
+		green := 0.5 + 0.5*math.Sin((yy+now*40)*0.1)
+		blue := 0.5 + 0.5*math.Sin((xx+yy+now*50)*0.1)
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.SetRGB(0, 0, 0)
-			dc.Clear()
-
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					offset := math.Sin(now*0.5) * 20 // Directional offset oscillates over time
-					xx := x + offset
-					yy := y + offset
-
-					// Generate evolving pattern using sine waves
-					red := 0.5 + 0.5*math.Sin((xx+now*30)*0.1)
-					green := 0.5 + 0.5*math.Sin((yy+now*40)*0.1)
-					blue := 0.5 + 0.5*math.Sin((xx+yy+now*50)*0.1)
-
-					// Prevent black by ensuring a minimum color threshold
-					red = math.Max(red, 0.1)
-					green = math.Max(green, 0.1)
-					blue = math.Max(blue, 0.1)
+		red, green, blue = r.rgbAt((xx+yy+now*50)*0.1/(2*math.Pi), red, green, blue)
 
-					dc.SetRGB(red, green, blue)
-					dc.SetPixel(int(x), int(y))
-				}
-			}
+		// Prevent black by ensuring a minimum color threshold
+		red = math.Max(red, 0.1)
+		green = math.Max(green, 0.1)
+		blue = math.Max(blue, 0.1)
 
-			r.screen.ShowImage(ctx, dc.Image())
-		}
-	}
+		return rgb(red, green, blue)
+	})
 }
 
 type PlasmaRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Plasma(screen *rgbmatrix.Screen) *PlasmaRenderer {
@@ -442,45 +341,30 @@ func Plasma(screen *rgbmatrix.Screen) *PlasmaRenderer {
 }
 
 func (r *PlasmaRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w := float64(dc.Width())
-	h := float64(dc.Height())
-
-	start := time.Now()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.Clear()
-
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					value := math.Sin(x*0.1+now) +
-						math.Sin(y*0.1+now) +
-						math.Sin((x+y)*0.1+now) +
-						math.Sin(math.Hypot(x-w/2, y-h/2)*0.1-now)
-
-					hue := (value + 4) / 8 // Normalize to [0, 1]
-					hue = math.Mod(hue, 1.0)
-					r, g, b := hsvToRGB(hue, 1.0, 1.0)
-					r = math.Max(r, 0.1)
-					g = math.Max(g, 0.1)
-					b = math.Max(b, 0.1)
-					dc.SetRGB(r, g, b)
-					dc.SetPixel(int(x), int(y))
-				}
-			}
-
-			r.screen.ShowImage(ctx, dc.Image())
-		}
-	}
+	bounds := r.screen.Frame().Bounds()
+	w := float64(bounds.Dx())
+	h := float64(bounds.Dy())
+
+	return r.runShader(ctx, r.screen, func(px, py int, now float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		value := math.Sin(x*0.1+now) +
+			math.Sin(y*0.1+now) +
+			math.Sin((x+y)*0.1+now) +
+			math.Sin(math.Hypot(x-w/2, y-h/2)*0.1-now)
+
+		hue := (value + 4) / 8 // Normalize to [0, 1]
+		hue = math.Mod(hue, 1.0)
+		red, green, blue := r.hsv(hue, 1.0, 1.0)
+		red = math.Max(red, 0.1)
+		green = math.Max(green, 0.1)
+		blue = math.Max(blue, 0.1)
+		return rgb(red, green, blue)
+	})
 }
 
 type RippleRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Ripple(screen *rgbmatrix.Screen) *RippleRenderer {
@@ -488,49 +372,35 @@ func Ripple(screen *rgbmatrix.Screen) *RippleRenderer {
 }
 
 func (r *RippleRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w := float64(dc.Width())
-	h := float64(dc.Height())
+	bounds := r.screen.Frame().Bounds()
+	w := float64(bounds.Dx())
+	h := float64(bounds.Dy())
 
 	cx := w / 2
 	cy := h / 2
-	start := time.Now()
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.Clear()
-
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					dx := x - cx
-					dy := y - cy
-					dist := math.Hypot(dx, dy)
-
-					value := math.Sin(dist*0.3 - now*3)
-					hue := math.Mod(value*0.25+now*0.1, 1.0)
-					brightness := 0.5 + 0.5*math.Sin(dist*0.2-now*2)
-
-					r, g, b := hsvToRGB(hue, 1.0, brightness)
-					r = math.Max(r, 0.1)
-					g = math.Max(g, 0.1)
-					b = math.Max(b, 0.1)
-
-					dc.SetRGB(r, g, b)
-					dc.SetPixel(int(x), int(y))
-				}
-			}
+	return r.runShader(ctx, r.screen, func(px, py int, now float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		dx := x - cx
+		dy := y - cy
+		dist := math.Hypot(dx, dy)
 
-			r.screen.ShowImage(ctx, dc.Image())
-		}
-	}
+		value := math.Sin(dist*0.3 - now*3)
+		hue := math.Mod(value*0.25+now*0.1, 1.0)
+		brightness := 0.5 + 0.5*math.Sin(dist*0.2-now*2)
+
+		red, green, blue := r.hsv(hue, 1.0, brightness)
+		red = math.Max(red, 0.1)
+		green = math.Max(green, 0.1)
+		blue = math.Max(blue, 0.1)
+
+		return rgb(red, green, blue)
+	})
 }
 
 type SpiralRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Spiral(screen *rgbmatrix.Screen) *SpiralRenderer {
@@ -538,43 +408,26 @@ func Spiral(screen *rgbmatrix.Screen) *SpiralRenderer {
 }
 
 func (r *SpiralRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w := float64(dc.Width())
-	h := float64(dc.Height())
+	bounds := r.screen.Frame().Bounds()
+	w := float64(bounds.Dx())
+	h := float64(bounds.Dy())
 
 	cx, cy := w/2, h/2
-	start := time.Now()
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.SetRGB(0, 0, 0)
-			dc.Clear()
-
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					dx := x - cx
-					dy := y - cy
-					angle := math.Atan2(dy, dx)
-
-					hue := (angle + now) / (2 * math.Pi)
-					hue = math.Mod(hue, 1.0)
-					r, g, b := hsvToRGB(hue, 1.0, 1.0)
-					r = math.Max(r, 0.1)
-					g = math.Max(g, 0.1)
-					b = math.Max(b, 0.1)
-					dc.SetRGB(r, g, b)
+	return r.runShader(ctx, r.screen, func(px, py int, now float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		dx := x - cx
+		dy := y - cy
+		angle := math.Atan2(dy, dx)
 
-					dc.SetPixel(int(x), int(y))
-				}
-			}
-
-			r.screen.ShowImage(ctx, dc.Image())
-		}
-	}
+		hue := (angle + now) / (2 * math.Pi)
+		hue = math.Mod(hue, 1.0)
+		red, green, blue := r.hsv(hue, 1.0, 1.0)
+		red = math.Max(red, 0.1)
+		green = math.Max(green, 0.1)
+		blue = math.Max(blue, 0.1)
+		return rgb(red, green, blue)
+	})
 }
 
 func hsvToRGB(h, s, v float64) (r, g, b float64) {
@@ -603,6 +456,7 @@ func hsvToRGB(h, s, v float64) (r, g, b float64) {
 
 type TunnelRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Tunnel(screen *rgbmatrix.Screen) *TunnelRenderer {
@@ -610,50 +464,36 @@ func Tunnel(screen *rgbmatrix.Screen) *TunnelRenderer {
 }
 
 func (r *TunnelRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w := float64(dc.Width())
-	h := float64(dc.Height())
+	bounds := r.screen.Frame().Bounds()
+	w := float64(bounds.Dx())
+	h := float64(bounds.Dy())
 
 	cx := w / 2
 	cy := h / 2
-	start := time.Now()
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.Clear()
-
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					dx := x - cx
-					dy := y - cy
-					dist := math.Hypot(dx, dy)
-					angle := math.Atan2(dy, dx)
-
-					depth := math.Sin(dist*0.1 - now)
-					radius := 1.0 / (0.1 + dist*0.05)
-
-					hue := math.Mod((angle/(2*math.Pi))+now*0.1+depth*0.5, 1.0)
-					r, g, b := hsvToRGB(hue, 1.0, radius)
-					r = math.Max(r, 0.1)
-					g = math.Max(g, 0.1)
-					b = math.Max(b, 0.1)
-
-					dc.SetRGB(r, g, b)
-					dc.SetPixel(int(x), int(y))
-				}
-			}
+	return r.runShader(ctx, r.screen, func(px, py int, now float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		dx := x - cx
+		dy := y - cy
+		dist := math.Hypot(dx, dy)
+		angle := math.Atan2(dy, dx)
 
-			r.screen.ShowImage(ctx, dc.Image())
-		}
-	}
+		depth := math.Sin(dist*0.1 - now)
+		radius := 1.0 / (0.1 + dist*0.05)
+
+		hue := math.Mod((angle/(2*math.Pi))+now*0.1+depth*0.5, 1.0)
+		red, green, blue := r.hsv(hue, 1.0, radius)
+		red = math.Max(red, 0.1)
+		green = math.Max(green, 0.1)
+		blue = math.Max(blue, 0.1)
+
+		return rgb(red, green, blue)
+	})
 }
 
 type SpectrumRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Spectrum(screen *rgbmatrix.Screen) *SpectrumRenderer {
@@ -661,48 +501,43 @@ func Spectrum(screen *rgbmatrix.Screen) *SpectrumRenderer {
 }
 
 func (r *SpectrumRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
 	width := dc.Width()
 	height := dc.Height()
-	start := time.Now()
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.Clear()
-
-			barCount := 16
-			barWidth := width / barCount
-
-			for i := 0; i < barCount; i++ {
-				freq := 0.5 + float64(i)*0.1
-				amplitude := math.Sin(now*freq+float64(i))*0.5 + 0.5
-				barHeight := int(float64(height) * amplitude)
-
-				hue := math.Mod(float64(i)/float64(barCount)+now*0.05, 1.0)
-
-				for y := height - 1; y >= height-barHeight; y-- {
-					for x := i * barWidth; x < (i+1)*barWidth; x++ {
-						r, g, b := hsvToRGB(hue, 1.0, 1.0)
-						r = math.Max(r, 0.1)
-						g = math.Max(g, 0.1)
-						b = math.Max(b, 0.1)
-						dc.SetRGB(r, g, b)
-						dc.SetPixel(x, y)
-					}
+	return r.run(ctx, func(elapsed time.Duration) error {
+		now := r.seconds(elapsed)
+		dc.Clear()
+
+		barCount := 16
+		barWidth := width / barCount
+
+		for i := 0; i < barCount; i++ {
+			freq := 0.5 + float64(i)*0.1
+			amplitude := math.Sin(now*freq+float64(i))*0.5 + 0.5
+			barHeight := int(float64(height) * amplitude)
+
+			hue := math.Mod(float64(i)/float64(barCount)+now*0.05, 1.0)
+
+			for y := height - 1; y >= height-barHeight; y-- {
+				for x := i * barWidth; x < (i+1)*barWidth; x++ {
+					red, green, blue := r.hsv(hue, 1.0, 1.0)
+					red = math.Max(red, 0.1)
+					green = math.Max(green, 0.1)
+					blue = math.Max(blue, 0.1)
+					dc.SetRGB(red, green, blue)
+					dc.SetPixel(x, y)
 				}
 			}
-
-			r.screen.ShowImage(ctx, dc.Image())
 		}
-	}
+
+		return r.screen.Present()
+	})
 }
 
 type StarfieldRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Starfield(screen *rgbmatrix.Screen) *StarfieldRenderer {
@@ -710,47 +545,46 @@ func Starfield(screen *rgbmatrix.Screen) *StarfieldRenderer {
 }
 
 func (r *StarfieldRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
 	w, h := dc.Width(), dc.Height()
 	cx, cy := float64(w)/2, float64(h)/2
+	rng := r.random()
 	stars := make([][3]float64, 100)
 	for i := range stars {
 		stars[i] = [3]float64{
-			(rand.Float64()*2 - 1) * cx,
-			(rand.Float64()*2 - 1) * cy,
-			rand.Float64()*1.5 + 0.5,
+			(rng.Float64()*2 - 1) * cx,
+			(rng.Float64()*2 - 1) * cy,
+			rng.Float64()*1.5 + 0.5,
 		}
 	}
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			dc.SetRGB(0.0, 0.0, 0.05) // dark background instead of full clear
-			dc.Clear()
-			for i := range stars {
-				stars[i][2] -= 0.02
-				if stars[i][2] <= 0.1 {
-					stars[i][0] = (rand.Float64()*2 - 1) * cx
-					stars[i][1] = (rand.Float64()*2 - 1) * cy
-					stars[i][2] = 1.5
-				}
-				sx := cx + stars[i][0]/stars[i][2]
-				sy := cy + stars[i][1]/stars[i][2]
-				if sx >= 0 && sx < float64(w) && sy >= 0 && sy < float64(h) {
-					brightness := 1.0 - (stars[i][2]-0.5)/1.5
-					brightness = math.Max(brightness, 0.1)
-					dc.SetRGB(brightness, brightness, brightness)
-					dc.SetPixel(int(sx), int(sy))
-				}
+	return r.run(ctx, func(elapsed time.Duration) error {
+		step := r.advance(elapsed)
+		dc.SetRGB(0.0, 0.0, 0.05) // dark background instead of full clear
+		dc.Clear()
+		for i := range stars {
+			stars[i][2] -= 0.02 * step
+			if stars[i][2] <= 0.1 {
+				stars[i][0] = (rng.Float64()*2 - 1) * cx
+				stars[i][1] = (rng.Float64()*2 - 1) * cy
+				stars[i][2] = 1.5
+			}
+			sx := cx + stars[i][0]/stars[i][2]
+			sy := cy + stars[i][1]/stars[i][2]
+			if sx >= 0 && sx < float64(w) && sy >= 0 && sy < float64(h) {
+				brightness := 1.0 - (stars[i][2]-0.5)/1.5
+				brightness = math.Max(brightness, 0.1)
+				red, green, blue := r.rgbAt(float64(i)/float64(len(stars)), 1, 1, 1)
+				dc.SetRGB(red*brightness, green*brightness, blue*brightness)
+				dc.SetPixel(int(sx), int(sy))
 			}
-			r.screen.ShowImage(ctx, dc.Image())
 		}
-	}
+		return r.screen.Present()
+	})
 }
 
 type FireflyRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Firefly(screen *rgbmatrix.Screen) *FireflyRenderer {
@@ -758,41 +592,39 @@ func Firefly(screen *rgbmatrix.Screen) *FireflyRenderer {
 }
 
 func (r *FireflyRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
 	w, h := dc.Width(), dc.Height()
 	type Firefly struct{ x, y, dx, dy float64 }
+	rng := r.random()
 	ff := make([]Firefly, 20)
 	for i := range ff {
-		ff[i] = Firefly{rand.Float64() * float64(w), rand.Float64() * float64(h), rand.Float64() - 0.5, rand.Float64() - 0.5}
+		ff[i] = Firefly{rng.Float64() * float64(w), rng.Float64() * float64(h), rng.Float64() - 0.5, rng.Float64() - 0.5}
 	}
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			dc.SetRGB(0, 0, 0.1)
-			dc.Clear()
-			for i := range ff {
-				ff[i].x += ff[i].dx
-				ff[i].y += ff[i].dy
-				if ff[i].x < 0 || ff[i].x >= float64(w) {
-					ff[i].dx *= -1
-				}
-				if ff[i].y < 0 || ff[i].y >= float64(h) {
-					ff[i].dy *= -1
-				}
-				hue := math.Mod(ff[i].x/float64(w)+ff[i].y/float64(h), 1)
-				r, g, b := hsvToRGB(hue, 1, 1)
-				dc.SetRGB(r, g, b)
-				dc.SetPixel(int(ff[i].x), int(ff[i].y))
+	return r.run(ctx, func(elapsed time.Duration) error {
+		step := r.advance(elapsed)
+		dc.SetRGB(0, 0, 0.1)
+		dc.Clear()
+		for i := range ff {
+			ff[i].x += ff[i].dx * step
+			ff[i].y += ff[i].dy * step
+			if ff[i].x < 0 || ff[i].x >= float64(w) {
+				ff[i].dx *= -1
+			}
+			if ff[i].y < 0 || ff[i].y >= float64(h) {
+				ff[i].dy *= -1
 			}
-			r.screen.ShowImage(ctx, dc.Image())
+			hue := math.Mod(ff[i].x/float64(w)+ff[i].y/float64(h), 1)
+			red, green, blue := r.hsv(hue, 1, 1)
+			dc.SetRGB(red, green, blue)
+			dc.SetPixel(int(ff[i].x), int(ff[i].y))
 		}
-	}
+		return r.screen.Present()
+	})
 }
 
 type MatrixRainRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func MatrixRain(screen *rgbmatrix.Screen) *MatrixRainRenderer {
@@ -800,49 +632,47 @@ func MatrixRain(screen *rgbmatrix.Screen) *MatrixRainRenderer {
 }
 
 func (r *MatrixRainRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
 	w, h := dc.Width(), dc.Height()
+	rng := r.random()
 	drops := make([]int, w)
 	for i := range drops {
-		drops[i] = rand.Intn(h)
+		drops[i] = rng.Intn(h)
 	}
 	trailLength := 10
 	lengths := make([]int, w)
 	for i := range lengths {
-		lengths[i] = 1 + rand.Intn(16) // lengths between 5 and 14
+		lengths[i] = 1 + rng.Intn(16) // lengths between 5 and 14
 	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			dc.SetRGB(0, 0, 0)
-			dc.Clear()
-
-			for x := 0; x < w; x++ {
-				headY := drops[x]
-				for t := 0; t < lengths[x]; t++ {
-					y := (headY - t + h) % h
-					brightness := 1.0 - float64(t)/float64(trailLength)
-					rVal, gVal, bVal := 0.0, brightness, 0.0
-					rVal = math.Max(rVal, 0.1)
-					gVal = math.Max(gVal, 0.1)
-					bVal = math.Max(bVal, 0.1)
-					dc.SetRGB(rVal, gVal, bVal)
-					dc.SetPixel(x, y)
-				}
-				drops[x] = (drops[x] + 1) % h
+	return r.run(ctx, func(elapsed time.Duration) error {
+		steps := r.steps(elapsed, 10*time.Millisecond)
+		dc.SetRGB(0, 0, 0)
+		dc.Clear()
+
+		for x := 0; x < w; x++ {
+			headY := drops[x]
+			for t := 0; t < lengths[x]; t++ {
+				y := (headY - t + h) % h
+				brightness := 1.0 - float64(t)/float64(trailLength)
+				rVal, gVal, bVal := r.rgbAt(float64(x)/float64(w), 0, 1, 0)
+				rVal, gVal, bVal = rVal*brightness, gVal*brightness, bVal*brightness
+				rVal = math.Max(rVal, 0.1)
+				gVal = math.Max(gVal, 0.1)
+				bVal = math.Max(bVal, 0.1)
+				dc.SetRGB(rVal, gVal, bVal)
+				dc.SetPixel(x, y)
 			}
-
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(10 * time.Millisecond)
+			drops[x] = (drops[x] + steps) % h
 		}
-	}
+
+		return r.screen.Present()
+	})
 }
 
 type CheckerboardRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Checkerboard(screen *rgbmatrix.Screen) *CheckerboardRenderer {
@@ -850,34 +680,30 @@ func Checkerboard(screen *rgbmatrix.Screen) *CheckerboardRenderer {
 }
 
 func (r *CheckerboardRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
 	w, h := dc.Width(), dc.Height()
-	start := time.Now()
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			t := time.Since(start).Seconds()
-			dc.Clear()
-			size := 8 + int(4*math.Sin(t))
-			for y := 0; y < h; y++ {
-				for x := 0; x < w; x++ {
-					if (x/size+y/size)%2 == 0 {
-						dc.SetRGB(1, 1, 1)
-					} else {
-						dc.SetRGB(0.1, 0.1, 0.1)
-					}
-					dc.SetPixel(x, y)
+	return r.run(ctx, func(elapsed time.Duration) error {
+		t := r.seconds(elapsed)
+		dc.Clear()
+		size := 8 + int(4*math.Sin(t))
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				if (x/size+y/size)%2 == 0 {
+					dc.SetRGB(r.rgbAt(0, 1, 1, 1))
+				} else {
+					red, green, blue := r.rgbAt(0.5, 1, 1, 1)
+					dc.SetRGB(red*0.1, green*0.1, blue*0.1)
 				}
+				dc.SetPixel(x, y)
 			}
-			r.screen.ShowImage(ctx, dc.Image())
 		}
-	}
+		return r.screen.Present()
+	})
 }
 
 type VortexRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Vortex(screen *rgbmatrix.Screen) *VortexRenderer {
@@ -885,37 +711,25 @@ func Vortex(screen *rgbmatrix.Screen) *VortexRenderer {
 }
 
 func (r *VortexRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w, h := float64(dc.Width()), float64(dc.Height())
+	bounds := r.screen.Frame().Bounds()
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
 	cx, cy := w/2, h/2
-	start := time.Now()
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			dc.Clear()
-			now := time.Since(start).Seconds()
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					dx := x - cx
-					dy := y - cy
-					angle := math.Atan2(dy, dx)
-					dist := math.Hypot(dx, dy)
-					value := math.Sin(dist*0.1 - now + angle)
-					hue := math.Mod((value+1)/2+now*0.02, 1)
-					r, g, b := hsvToRGB(hue, 1, 1)
-					dc.SetRGB(r, g, b)
-					dc.SetPixel(int(x), int(y))
-				}
-			}
-			r.screen.ShowImage(ctx, dc.Image())
-		}
-	}
+	return r.runShader(ctx, r.screen, func(px, py int, now float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		dx := x - cx
+		dy := y - cy
+		angle := math.Atan2(dy, dx)
+		dist := math.Hypot(dx, dy)
+		value := math.Sin(dist*0.1 - now + angle)
+		hue := math.Mod((value+1)/2+now*0.02, 1)
+		red, green, blue := r.hsv(hue, 1, 1)
+		return rgb(red, green, blue)
+	})
 }
 
 type LightningRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Lightning(screen *rgbmatrix.Screen) *LightningRenderer {
@@ -923,46 +737,42 @@ func Lightning(screen *rgbmatrix.Screen) *LightningRenderer {
 }
 
 func (r *LightningRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
 	w, h := dc.Width(), dc.Height()
-	var lastFlash time.Time
+	rng := r.random()
+	var lastFlash time.Duration
 	var flashX int
-	var flashing bool
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			// Dim background to gradually fade out
-			dc.SetRGBA(0, 0, 0, 0.1)
-			dc.Clear()
-
-			now := time.Now()
-			if flashing && now.Sub(lastFlash) > 100*time.Millisecond {
-				flashing = false
-			}
-			if !flashing && (lastFlash.IsZero() || now.Sub(lastFlash) > time.Duration(rand.Intn(1000))*time.Millisecond) {
-				lastFlash = now
-				flashX = rand.Intn(w)
-				flashing = true
-			}
-			if flashing {
-				x := flashX
-				for y := 0; y < h; y++ {
-					if x >= 0 && x < w {
-						dc.SetRGB(1, 1, 1)
-						dc.SetPixel(x, y)
-					}
-					x += rand.Intn(3) - 1
+	var flashing, flashed bool
+	return r.run(ctx, func(elapsed time.Duration) error {
+		// Dim background to gradually fade out
+		dc.SetRGBA(0, 0, 0, 0.1)
+		dc.Clear()
+
+		if flashing && elapsed-lastFlash > 100*time.Millisecond {
+			flashing = false
+		}
+		if !flashing && (!flashed || elapsed-lastFlash > r.interval(time.Duration(rng.Intn(1000))*time.Millisecond)) {
+			lastFlash = elapsed
+			flashX = rng.Intn(w)
+			flashing, flashed = true, true
+		}
+		if flashing {
+			x := flashX
+			dc.SetRGB(r.rgbAt(float64(flashX)/float64(w), 1, 1, 1))
+			for y := 0; y < h; y++ {
+				if x >= 0 && x < w {
+					dc.SetPixel(x, y)
 				}
+				x += rng.Intn(3) - 1
 			}
-			r.screen.ShowImage(ctx, dc.Image())
 		}
-	}
+		return r.screen.Present()
+	})
 }
 
 type MandelbrotRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Mandelbrot(screen *rgbmatrix.Screen) *MandelbrotRenderer {
@@ -970,44 +780,35 @@ func Mandelbrot(screen *rgbmatrix.Screen) *MandelbrotRenderer {
 }
 
 func (r *MandelbrotRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w, h := dc.Width(), dc.Height()
-	start := time.Now()
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.Clear()
-			zoom := 1.5 + 0.5*math.Sin(now*0.2)
-			centerX := -0.5 + 0.2*math.Sin(now*0.1)
-			centerY := 0.0 + 0.2*math.Cos(now*0.1)
-
-			for y := 0; y < h; y++ {
-				for x := 0; x < w; x++ {
-					cx := (float64(x)/float64(w))*zoom + centerX - zoom/2
-					cy := (float64(y)/float64(h))*zoom + centerY - zoom/2
-					zx, zy := 0.0, 0.0
-					iter, maxIter := 0, 30
-					for zx*zx+zy*zy < 4 && iter < maxIter {
-						tmp := zx*zx - zy*zy + cx
-						zy, zx = 2*zx*zy+cy, tmp
-						iter++
-					}
-					hue := float64(iter) / float64(maxIter)
-					r, g, b := hsvToRGB(hue, 1, 1)
-					dc.SetRGB(r, g, b)
-					dc.SetPixel(x, y)
-				}
+	frame := r.screen.Frame()
+	w, h := frame.Bounds().Dx(), frame.Bounds().Dy()
+	return r.run(ctx, func(elapsed time.Duration) error {
+		now := r.seconds(elapsed)
+		zoom := 1.5 + 0.5*math.Sin(now*0.2)
+		centerX := -0.5 + 0.2*math.Sin(now*0.1)
+		centerY := 0.0 + 0.2*math.Cos(now*0.1)
+
+		shade(frame, now, func(x, y int, _ float64) color.RGBA {
+			cx := (float64(x)/float64(w))*zoom + centerX - zoom/2
+			cy := (float64(y)/float64(h))*zoom + centerY - zoom/2
+			zx, zy := 0.0, 0.0
+			iter, maxIter := 0, 30
+			for zx*zx+zy*zy < 4 && iter < maxIter {
+				tmp := zx*zx - zy*zy + cx
+				zy, zx = 2*zx*zy+cy, tmp
+				iter++
 			}
-			r.screen.ShowImage(ctx, dc.Image())
-		}
-	}
+			hue := float64(iter) / float64(maxIter)
+			red, green, blue := r.hsv(hue, 1, 1)
+			return rgb(red, green, blue)
+		})
+		return r.screen.Present()
+	})
 }
 
 type BlobbyFusionRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func BlobbyFusion(screen *rgbmatrix.Screen) *BlobbyFusionRenderer {
@@ -1019,72 +820,65 @@ func (r *BlobbyFusionRenderer) Render(ctx context.Context, _ ...AfterRenderFunc)
 		x, y, radius, dx, dy float64
 	}
 
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w := float64(dc.Width())
-	h := float64(dc.Height())
-	start := time.Now()
+	frame := r.screen.Frame()
+	rng := r.random()
+	w := float64(frame.Bounds().Dx())
+	h := float64(frame.Bounds().Dy())
 
 	// Create some initial blobs
 	blobs := []Blob{}
 	for i := 0; i < 5; i++ {
 		blobs = append(blobs, Blob{
-			x:      rand.Float64() * w,
-			y:      rand.Float64() * h,
-			radius: 10 + rand.Float64()*10,
-			dx:     rand.Float64()*2 - 1,
-			dy:     rand.Float64()*2 - 1,
+			x:      rng.Float64() * w,
+			y:      rng.Float64() * h,
+			radius: 10 + rng.Float64()*10,
+			dx:     rng.Float64()*2 - 1,
+			dy:     rng.Float64()*2 - 1,
 		})
 	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.Clear()
+	return r.run(ctx, func(elapsed time.Duration) error {
+		now := r.seconds(elapsed)
+		step := r.advance(elapsed)
 
-			// Move blobs
-			for i := range blobs {
-				blobs[i].x += blobs[i].dx
-				blobs[i].y += blobs[i].dy
+		// Move blobs
+		for i := range blobs {
+			blobs[i].x += blobs[i].dx * step
+			blobs[i].y += blobs[i].dy * step
 
-				if blobs[i].x < 0 || blobs[i].x > w {
-					blobs[i].dx *= -1
-				}
-				if blobs[i].y < 0 || blobs[i].y > h {
-					blobs[i].dy *= -1
-				}
+			if blobs[i].x < 0 || blobs[i].x > w {
+				blobs[i].dx *= -1
+			}
+			if blobs[i].y < 0 || blobs[i].y > h {
+				blobs[i].dy *= -1
 			}
+		}
 
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					sum := 0.0
-					for _, b := range blobs {
-						dx := x - b.x
-						dy := y - b.y
-						dist := math.Hypot(dx, dy)
-						sum += b.radius * b.radius / (dist*dist + 1)
-					}
+		shade(frame, now, func(px, py int, now float64) color.RGBA {
+			x, y := float64(px), float64(py)
+			sum := 0.0
+			for _, b := range blobs {
+				dx := x - b.x
+				dy := y - b.y
+				dist := math.Hypot(dx, dy)
+				sum += b.radius * b.radius / (dist*dist + 1)
+			}
 
-					normalized := math.Min(sum/5.0, 1.0)
-					hue := math.Mod(0.6+0.3*normalized+now*0.02, 1.0)
-					val := math.Pow(normalized, 1.2)
+			normalized := math.Min(sum/5.0, 1.0)
+			hue := math.Mod(0.6+0.3*normalized+now*0.02, 1.0)
+			val := math.Pow(normalized, 1.2)
 
-					r, g, b := hsvToRGB(hue, 0.8, val)
-					dc.SetRGB(math.Max(r, 0.1), math.Max(g, 0.1), math.Max(b, 0.1))
-					dc.SetPixel(int(x), int(y))
-				}
-			}
+			red, green, blue := r.hsv(hue, 0.8, val)
+			return rgb(math.Max(red, 0.1), math.Max(green, 0.1), math.Max(blue, 0.1))
+		})
 
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
-		}
-	}
+		return r.screen.Present()
+	})
 }
 
 type KaleidoscopeRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func Kaleidoscope(screen *rgbmatrix.Screen) *KaleidoscopeRenderer {
@@ -1092,172 +886,139 @@ func Kaleidoscope(screen *rgbmatrix.Screen) *KaleidoscopeRenderer {
 }
 
 func (r *KaleidoscopeRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w := float64(dc.Width())
-	h := float64(dc.Height())
+	bounds := r.screen.Frame().Bounds()
+	w := float64(bounds.Dx())
+	h := float64(bounds.Dy())
 	cx := w / 2
 	cy := h / 2
-	start := time.Now()
+	segments := 6 // number of mirrored segments
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			dc.Clear()
-			now := time.Since(start).Seconds()
-			segments := 6 // number of mirrored segments
-
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					dx := x - cx
-					dy := y - cy
-					angle := math.Atan2(dy, dx) + now*0.5
-					dist := math.Hypot(dx, dy)
-
-					// wrap angle into a segment
-					angle = math.Mod(angle, 2*math.Pi/float64(segments))
-					xx := math.Cos(angle) * dist
-					yy := math.Sin(angle) * dist
-
-					value := math.Sin(xx*0.2+now) + math.Cos(yy*0.2+now*1.1)
-					hue := math.Mod(0.6+value*0.15+now*0.01, 1.0)
-					brightness := 0.3 + 0.7*math.Sin(value+now)
-
-					r, g, b := hsvToRGB(hue, 1.0, brightness)
-					dc.SetRGB(math.Max(r, 0.1), math.Max(g, 0.1), math.Max(b, 0.1))
-					dc.SetPixel(int(x), int(y))
-				}
-			}
+	return r.runShader(ctx, r.screen, func(px, py int, now float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		dx := x - cx
+		dy := y - cy
+		angle := math.Atan2(dy, dx) + now*0.5
+		dist := math.Hypot(dx, dy)
 
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
-		}
-	}
+		// wrap angle into a segment
+		angle = math.Mod(angle, 2*math.Pi/float64(segments))
+		xx := math.Cos(angle) * dist
+		yy := math.Sin(angle) * dist
+
+		value := math.Sin(xx*0.2+now) + math.Cos(yy*0.2+now*1.1)
+		hue := math.Mod(0.6+value*0.15+now*0.01, 1.0)
+		brightness := 0.3 + 0.7*math.Sin(value+now)
+
+		red, green, blue := r.hsv(hue, 1.0, brightness)
+		return rgb(math.Max(red, 0.1), math.Max(green, 0.1), math.Max(blue, 0.1))
+	})
 }
 
-type HypnoticRingsRenderer struct{ screen *rgbmatrix.Screen }
+type HypnoticRingsRenderer struct {
+	screen *rgbmatrix.Screen
+	animated
+}
 
 func HypnoticRings(screen *rgbmatrix.Screen) *HypnoticRingsRenderer {
 	return &HypnoticRingsRenderer{screen: screen}
 }
 
 func (r *HypnoticRingsRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w, h := float64(dc.Width()), float64(dc.Height())
+	bounds := r.screen.Frame().Bounds()
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
 	cx, cy := w/2, h/2
-	start := time.Now()
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.Clear()
-
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					dx, dy := x-cx, y-cy
-					dist := math.Hypot(dx, dy)
-					value := math.Sin(dist*0.2 - now*2)
-					bright := 0.5 + 0.5*value
-					hue := math.Mod(dist*0.01+now*0.1, 1.0)
-					r, g, b := hsvToRGB(hue, 1.0, bright)
-					dc.SetRGB(math.Max(r, 0.1), math.Max(g, 0.1), math.Max(b, 0.1))
-					dc.SetPixel(int(x), int(y))
-				}
-			}
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
-		}
-	}
+	return r.runShader(ctx, r.screen, func(px, py int, now float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		dx, dy := x-cx, y-cy
+		dist := math.Hypot(dx, dy)
+		value := math.Sin(dist*0.2 - now*2)
+		bright := 0.5 + 0.5*value
+		hue := math.Mod(dist*0.01+now*0.1, 1.0)
+		red, green, blue := r.hsv(hue, 1.0, bright)
+		return rgb(math.Max(red, 0.1), math.Max(green, 0.1), math.Max(blue, 0.1))
+	})
 }
 
-type SpinningGridRenderer struct{ screen *rgbmatrix.Screen }
+type SpinningGridRenderer struct {
+	screen *rgbmatrix.Screen
+	animated
+}
 
 func SpinningGrid(screen *rgbmatrix.Screen) *SpinningGridRenderer {
 	return &SpinningGridRenderer{screen: screen}
 }
 
 func (r *SpinningGridRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
 	w, h := float64(dc.Width()), float64(dc.Height())
 	cx, cy := w/2, h/2
-	start := time.Now()
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			angle := time.Since(start).Seconds()
-			dc.SetRGB(0, 0, 0)
-			dc.Clear()
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					dx := x - cx
-					dy := y - cy
-					rx := dx*math.Cos(angle) - dy*math.Sin(angle)
-					ry := dx*math.Sin(angle) + dy*math.Cos(angle)
-					if int(rx)%10 == 0 || int(ry)%10 == 0 {
-						hue := math.Mod(angle*0.1+rx*0.01+ry*0.01, 1.0)
-						r, g, b := hsvToRGB(hue, 1, 1)
-						dc.SetRGB(r, g, b)
-						dc.SetPixel(int(x), int(y))
-					}
+	return r.run(ctx, func(elapsed time.Duration) error {
+		angle := r.seconds(elapsed)
+		dc.SetRGB(0, 0, 0)
+		dc.Clear()
+		for y := 0.0; y < h; y++ {
+			for x := 0.0; x < w; x++ {
+				dx := x - cx
+				dy := y - cy
+				rx := dx*math.Cos(angle) - dy*math.Sin(angle)
+				ry := dx*math.Sin(angle) + dy*math.Cos(angle)
+				if int(rx)%10 == 0 || int(ry)%10 == 0 {
+					hue := math.Mod(angle*0.1+rx*0.01+ry*0.01, 1.0)
+					red, green, blue := r.hsv(hue, 1, 1)
+					dc.SetRGB(red, green, blue)
+					dc.SetPixel(int(x), int(y))
 				}
 			}
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
 		}
-	}
+		return r.screen.Present()
+	})
 }
 
-type HexPulseRenderer struct{ screen *rgbmatrix.Screen }
+type HexPulseRenderer struct {
+	screen *rgbmatrix.Screen
+	animated
+}
 
 func HexPulse(screen *rgbmatrix.Screen) *HexPulseRenderer {
 	return &HexPulseRenderer{screen: screen}
 }
 
 func (r *HexPulseRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
 	w, h := dc.Width(), dc.Height()
-	start := time.Now()
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			t := time.Since(start).Seconds()
-			dc.SetRGB(0, 0, 0)
-			dc.Clear()
-			radius := 4.0
-			dx := radius * 3 / 2
-			dy := radius * math.Sqrt(3)
-			for y := 0.0; y < float64(h); y += dy {
-				for x := 0.0; x < float64(w); x += dx {
-					offset := 0.0
-					if int(y/dy)%2 == 1 {
-						offset = radius * 0.75
-					}
-					dist := math.Hypot(x-float64(w)/2+offset, y-float64(h)/2)
-					pulse := 0.5 + 0.5*math.Sin(dist*0.2-t*4)
-					hue := math.Mod(dist*0.01+t*0.1, 1)
-					r, g, b := hsvToRGB(hue, 1.0, pulse)
-					dc.SetRGB(r, g, b)
-					dc.DrawCircle(x+offset, y, 1)
-					dc.Fill()
+	return r.run(ctx, func(elapsed time.Duration) error {
+		t := r.seconds(elapsed)
+		dc.SetRGB(0, 0, 0)
+		dc.Clear()
+		radius := 4.0
+		dx := radius * 3 / 2
+		dy := radius * math.Sqrt(3)
+		for y := 0.0; y < float64(h); y += dy {
+			for x := 0.0; x < float64(w); x += dx {
+				offset := 0.0
+				if int(y/dy)%2 == 1 {
+					offset = radius * 0.75
 				}
+				dist := math.Hypot(x-float64(w)/2+offset, y-float64(h)/2)
+				pulse := 0.5 + 0.5*math.Sin(dist*0.2-t*4)
+				hue := math.Mod(dist*0.01+t*0.1, 1)
+				red, green, blue := r.hsv(hue, 1.0, pulse)
+				dc.SetRGB(red, green, blue)
+				dc.DrawCircle(x+offset, y, 1)
+				dc.Fill()
 			}
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
 		}
-	}
+		return r.screen.Present()
+	})
 }
 
-type SnakeTrailRenderer struct{ screen *rgbmatrix.Screen }
+type SnakeTrailRenderer struct {
+	screen *rgbmatrix.Screen
+	animated
+}
 
 func SnakeTrail(screen *rgbmatrix.Screen) *SnakeTrailRenderer {
 	return &SnakeTrailRenderer{screen: screen}
@@ -1266,19 +1027,19 @@ func SnakeTrail(screen *rgbmatrix.Screen) *SnakeTrailRenderer {
 func (r *SnakeTrailRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
 	type Point struct{ x, y int }
 
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
+	rng := r.random()
 	w, h := dc.Width(), dc.Height()
 	snake := []Point{{w / 2, h / 2}}
 	dir := []Point{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
 	heading := 0
-	ticker := time.NewTicker(100 * time.Millisecond)
-	defer ticker.Stop()
 
-	for {
-		select {
-		case <-ctx.Done():
+	return r.run(ctx, func(elapsed time.Duration) error {
+		steps := r.steps(elapsed, 100*time.Millisecond)
+		if steps == 0 && elapsed > 0 {
 			return nil
-		case <-ticker.C:
+		}
+		for range steps {
 			head := snake[0]
 			nx := (head.x + dir[heading].x + w) % w
 			ny := (head.y + dir[heading].y + h) % h
@@ -1286,63 +1047,52 @@ func (r *SnakeTrailRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) e
 			if len(snake) > 20 {
 				snake = snake[:20]
 			}
-			if rand.Float64() < 0.3 {
-				heading = rand.Intn(4)
+			if rng.Float64() < 0.3 {
+				heading = rng.Intn(4)
 			}
+		}
 
-			dc.SetRGB(0, 0, 0)
-			dc.Clear()
-			for i, p := range snake {
-				hue := float64(i) / float64(len(snake))
-				r, g, b := hsvToRGB(hue, 1, 1)
-				dc.SetRGB(r, g, b)
-				dc.SetPixel(p.x, p.y)
-			}
-			r.screen.ShowImage(ctx, dc.Image())
+		dc.SetRGB(0, 0, 0)
+		dc.Clear()
+		for i, p := range snake {
+			hue := float64(i) / float64(len(snake))
+			red, green, blue := r.hsv(hue, 1, 1)
+			dc.SetRGB(red, green, blue)
+			dc.SetPixel(p.x, p.y)
 		}
-	}
+		return r.screen.Present()
+	})
 }
 
-type ExplosionBurstRenderer struct{ screen *rgbmatrix.Screen }
+type ExplosionBurstRenderer struct {
+	screen *rgbmatrix.Screen
+	animated
+}
 
 func ExplosionBurst(screen *rgbmatrix.Screen) *ExplosionBurstRenderer {
 	return &ExplosionBurstRenderer{screen: screen}
 }
 
 func (r *ExplosionBurstRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w, h := float64(dc.Width()), float64(dc.Height())
+	bounds := r.screen.Frame().Bounds()
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
 	cx, cy := w/2, h/2
-	start := time.Now()
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.Clear()
-
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					dx, dy := x-cx, y-cy
-					dist := math.Hypot(dx, dy)
-					ring := math.Sin(dist*0.5 - now*4)
-					brightness := 0.5 + 0.5*ring
-					hue := math.Mod(now*0.1+dist*0.02, 1.0)
-					r, g, b := hsvToRGB(hue, 1.0, brightness)
-					dc.SetRGB(r, g, b)
-					dc.SetPixel(int(x), int(y))
-				}
-			}
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
-		}
-	}
+	return r.runShader(ctx, r.screen, func(px, py int, now float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		dx, dy := x-cx, y-cy
+		dist := math.Hypot(dx, dy)
+		ring := math.Sin(dist*0.5 - now*4)
+		brightness := 0.5 + 0.5*ring
+		hue := math.Mod(now*0.1+dist*0.02, 1.0)
+		red, green, blue := r.hsv(hue, 1.0, brightness)
+		return rgb(red, green, blue)
+	})
 }
 
 type AudioOrbitRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func AudioOrbit(screen *rgbmatrix.Screen) *AudioOrbitRenderer {
@@ -1350,46 +1100,40 @@ func AudioOrbit(screen *rgbmatrix.Screen) *AudioOrbitRenderer {
 }
 
 func (r *AudioOrbitRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
 	w := float64(dc.Width())
 	h := float64(dc.Height())
 	cx := w / 2
 	cy := h / 2
-	start := time.Now()
 
 	numOrbits := 6
 	radius := math.Min(w, h) * 0.3
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			dc.SetRGB(0, 0, 0)
-			dc.Clear()
-			now := time.Since(start).Seconds()
-
-			for i := 0; i < numOrbits; i++ {
-				angle := now*1.5 + float64(i)*math.Pi*2/float64(numOrbits)
-				amp := math.Sin(now*3 + float64(i))
-				ringRadius := radius + 5*amp
-				x := cx + ringRadius*math.Cos(angle)
-				y := cy + ringRadius*math.Sin(angle)
-				hue := math.Mod(float64(i)/float64(numOrbits)+now*0.1, 1.0)
-				r, g, b := hsvToRGB(hue, 1.0, 1.0)
-				dc.SetRGB(r, g, b)
-				dc.DrawCircle(x, y, 2)
-				dc.Fill()
-			}
-
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
+	return r.run(ctx, func(elapsed time.Duration) error {
+		dc.SetRGB(0, 0, 0)
+		dc.Clear()
+		now := r.seconds(elapsed)
+
+		for i := 0; i < numOrbits; i++ {
+			angle := now*1.5 + float64(i)*math.Pi*2/float64(numOrbits)
+			amp := math.Sin(now*3 + float64(i))
+			ringRadius := radius + 5*amp
+			x := cx + ringRadius*math.Cos(angle)
+			y := cy + ringRadius*math.Sin(angle)
+			hue := math.Mod(float64(i)/float64(numOrbits)+now*0.1, 1.0)
+			red, green, blue := r.hsv(hue, 1.0, 1.0)
+			dc.SetRGB(red, green, blue)
+			dc.DrawCircle(x, y, 2)
+			dc.Fill()
 		}
-	}
+
+		return r.screen.Present()
+	})
 }
 
 type AuroraCurtainsRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func AuroraCurtains(screen *rgbmatrix.Screen) *AuroraCurtainsRenderer {
@@ -1397,46 +1141,28 @@ func AuroraCurtains(screen *rgbmatrix.Screen) *AuroraCurtainsRenderer {
 }
 
 func (r *AuroraCurtainsRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	width := float64(dc.Width())
-	height := float64(dc.Height())
-	start := time.Now()
+	height := float64(r.screen.Frame().Bounds().Dy())
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.SetRGB(0, 0, 0)
-			dc.Clear()
-
-			for x := 0.0; x < width; x++ {
-				wave := math.Sin(x*0.2 + now*1.5)
-				for y := 0.0; y < height; y++ {
-					yRatio := y / height
-					offset := math.Sin(yRatio*math.Pi*4 + wave*2 + now*0.5)
-					brightness := 0.4 + 0.6*(0.5+0.5*offset)
-
-					hue := math.Mod(0.3+0.2*wave+now*0.02, 1.0)
-					r, g, b := hsvToRGB(hue, 1.0, brightness)
-					r = math.Max(r, 0.1)
-					g = math.Max(g, 0.1)
-					b = math.Max(b, 0.1)
-
-					dc.SetRGB(r, g, b)
-					dc.SetPixel(int(x), int(y))
-				}
-			}
+	return r.runShader(ctx, r.screen, func(px, py int, now float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		wave := math.Sin(x*0.2 + now*1.5)
+		yRatio := y / height
+		offset := math.Sin(yRatio*math.Pi*4 + wave*2 + now*0.5)
+		brightness := 0.4 + 0.6*(0.5+0.5*offset)
 
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
-		}
-	}
+		hue := math.Mod(0.3+0.2*wave+now*0.02, 1.0)
+		red, green, blue := r.hsv(hue, 1.0, brightness)
+		red = math.Max(red, 0.1)
+		green = math.Max(green, 0.1)
+		blue = math.Max(blue, 0.1)
+
+		return rgb(red, green, blue)
+	})
 }
 
 type UlamSpiralRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func UlamSpiral(screen *rgbmatrix.Screen) *UlamSpiralRenderer {
@@ -1456,50 +1182,47 @@ func isPrime(n int) bool {
 }
 
 func (r *UlamSpiralRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
 	w, h := dc.Width(), dc.Height()
 	cx, cy := w/2, h/2
-	start := time.Now()
 
-	for {
-		select {
-		case <-ctx.Done():
+	return r.run(ctx, func(elapsed time.Duration) error {
+		if r.steps(elapsed, 150*time.Millisecond) == 0 && elapsed > 0 {
 			return nil
-		default:
-			dc.SetRGB(0, 0, 0)
-			dc.Clear()
-			x, y := 0, 0
-			dx, dy := 0, -1
-			steps := int(math.Max(float64(w), float64(h))) * int(math.Max(float64(w), float64(h)))
-			num := 1
-
-			for i := 0; i < steps; i++ {
-				sx := cx + x
-				sy := cy + y
-				if sx >= 0 && sx < w && sy >= 0 && sy < h {
-					if isPrime(num) {
-						t := time.Since(start).Seconds()
-						hue := math.Mod(float64(num)*0.01+t*0.1, 1.0)
-						r, g, b := hsvToRGB(hue, 1.0, 1.0)
-						dc.SetRGB(r, g, b)
-						dc.SetPixel(sx, sy)
-					}
-				}
-				if (x == y) || (x < 0 && x == -y) || (x > 0 && x == 1-y) {
-					dx, dy = -dy, dx
+		}
+		dc.SetRGB(0, 0, 0)
+		dc.Clear()
+		x, y := 0, 0
+		dx, dy := 0, -1
+		steps := int(math.Max(float64(w), float64(h))) * int(math.Max(float64(w), float64(h)))
+		num := 1
+
+		for i := 0; i < steps; i++ {
+			sx := cx + x
+			sy := cy + y
+			if sx >= 0 && sx < w && sy >= 0 && sy < h {
+				if isPrime(num) {
+					t := r.seconds(elapsed)
+					hue := math.Mod(float64(num)*0.01+t*0.1, 1.0)
+					red, green, blue := r.hsv(hue, 1.0, 1.0)
+					dc.SetRGB(red, green, blue)
+					dc.SetPixel(sx, sy)
 				}
-				x += dx
-				y += dy
-				num++
 			}
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(150 * time.Millisecond)
+			if (x == y) || (x < 0 && x == -y) || (x > 0 && x == 1-y) {
+				dx, dy = -dy, dx
+			}
+			x += dx
+			y += dy
+			num++
 		}
-	}
+		return r.screen.Present()
+	})
 }
 
 type GameOfLifeRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func GameOfLife(screen *rgbmatrix.Screen) *GameOfLifeRenderer {
@@ -1507,7 +1230,8 @@ func GameOfLife(screen *rgbmatrix.Screen) *GameOfLifeRenderer {
 }
 
 func (r *GameOfLifeRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
+	rng := r.random()
 	w, h := dc.Width(), dc.Height()
 	grid := make([][]bool, h)
 	next := make([][]bool, h)
@@ -1515,30 +1239,16 @@ func (r *GameOfLifeRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) e
 		grid[y] = make([]bool, w)
 		next[y] = make([]bool, w)
 		for x := range grid[y] {
-			grid[y][x] = rand.Float64() < 0.2
+			grid[y][x] = rng.Float64() < 0.2
 		}
 	}
 
-	for {
-		select {
-		case <-ctx.Done():
+	return r.run(ctx, func(elapsed time.Duration) error {
+		steps := r.steps(elapsed, 50*time.Millisecond)
+		if steps == 0 && elapsed > 0 {
 			return nil
-		default:
-			// Draw current grid
-			dc.SetRGB(0, 0, 0)
-			dc.Clear()
-			for y := 0; y < h; y++ {
-				for x := 0; x < w; x++ {
-					if grid[y][x] {
-						hue := float64((x*y)%360) / 360
-						r, g, b := hsvToRGB(hue, 1.0, 1.0)
-						dc.SetRGB(r, g, b)
-						dc.SetPixel(x, y)
-					}
-				}
-			}
-			r.screen.ShowImage(ctx, dc.Image())
-
+		}
+		for range steps {
 			// Compute next state
 			for y := 0; y < h; y++ {
 				for x := 0; x < w; x++ {
@@ -1565,13 +1275,28 @@ func (r *GameOfLifeRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) e
 
 			// Swap buffers
 			grid, next = next, grid
-			time.Sleep(50 * time.Millisecond)
 		}
-	}
+
+		// Draw current grid
+		dc.SetRGB(0, 0, 0)
+		dc.Clear()
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				if grid[y][x] {
+					hue := float64((x*y)%360) / 360
+					red, green, blue := r.hsv(hue, 1.0, 1.0)
+					dc.SetRGB(red, green, blue)
+					dc.SetPixel(x, y)
+				}
+			}
+		}
+		return r.screen.Present()
+	})
 }
 
 type VectorFieldFlowRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func VectorFieldFlow(screen *rgbmatrix.Screen) *VectorFieldFlowRenderer {
@@ -1581,66 +1306,62 @@ func VectorFieldFlow(screen *rgbmatrix.Screen) *VectorFieldFlowRenderer {
 func (r *VectorFieldFlowRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
 	type Particle struct{ x, y float64 }
 
-	dc := gg.NewContextForImage(r.screen.Canvas)
+	dc := gg.NewContextForRGBA(r.screen.Frame())
+	rng := r.random()
 	w := float64(dc.Width())
 	h := float64(dc.Height())
-	start := time.Now()
 
 	numParticles := 100
 	particles := make([]Particle, numParticles)
 	for i := range particles {
 		particles[i] = Particle{
-			x: rand.Float64() * w,
-			y: rand.Float64() * h,
+			x: rng.Float64() * w,
+			y: rng.Float64() * h,
 		}
 	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.SetRGB(0, 0, 0)
-			dc.Clear()
-
-			for i := range particles {
-				p := &particles[i]
-
-				// Curl-inspired sine/cos field
-				angle := math.Sin(p.y*0.1+now)*math.Pi + math.Cos(p.x*0.1-now)*math.Pi
-				dx := math.Cos(angle)
-				dy := math.Sin(angle)
-
-				p.x += dx * 0.5
-				p.y += dy * 0.5
-
-				// Wrap around
-				if p.x < 0 {
-					p.x += w
-				} else if p.x >= w {
-					p.x -= w
-				}
-				if p.y < 0 {
-					p.y += h
-				} else if p.y >= h {
-					p.y -= h
-				}
+	return r.run(ctx, func(elapsed time.Duration) error {
+		now := r.seconds(elapsed)
+		step := r.advance(elapsed)
+		dc.SetRGB(0, 0, 0)
+		dc.Clear()
+
+		for i := range particles {
+			p := &particles[i]
+
+			// Curl-inspired sine/cos field
+			angle := math.Sin(p.y*0.1+now)*math.Pi + math.Cos(p.x*0.1-now)*math.Pi
+			dx := math.Cos(angle)
+			dy := math.Sin(angle)
+
+			p.x += dx * 0.5 * step
+			p.y += dy * 0.5 * step
 
-				hue := math.Mod(float64(i)/float64(numParticles)+now*0.1, 1.0)
-				r, g, b := hsvToRGB(hue, 1.0, 1.0)
-				dc.SetRGB(r, g, b)
-				dc.SetPixel(int(p.x), int(p.y))
+			// Wrap around
+			if p.x < 0 {
+				p.x += w
+			} else if p.x >= w {
+				p.x -= w
+			}
+			if p.y < 0 {
+				p.y += h
+			} else if p.y >= h {
+				p.y -= h
 			}
 
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
+			hue := math.Mod(float64(i)/float64(numParticles)+now*0.1, 1.0)
+			red, green, blue := r.hsv(hue, 1.0, 1.0)
+			dc.SetRGB(red, green, blue)
+			dc.SetPixel(int(p.x), int(p.y))
 		}
-	}
+
+		return r.screen.Present()
+	})
 }
 
 type SierpinskiTriangleRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func SierpinskiTriangle(screen *rgbmatrix.Screen) *SierpinskiTriangleRenderer {
@@ -1671,41 +1392,35 @@ func (ren *SierpinskiTriangleRenderer) Render(ctx context.Context, _ ...AfterRen
 		drawTriangle(dc, x3, y3, bx, by, cx, cy, depth-1)
 	}
 
-	dc := gg.NewContextForImage(ren.screen.Canvas)
+	dc := gg.NewContextForRGBA(ren.screen.Frame())
 	width := float64(dc.Width())
 	height := float64(dc.Height())
-	start := time.Now()
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			depth := int(2 + math.Floor(math.Sin(now*0.5)*1.5)) // animate between 1 and 3
+	return ren.run(ctx, func(elapsed time.Duration) error {
+		now := ren.seconds(elapsed)
+		depth := int(2 + math.Floor(math.Sin(now*0.5)*1.5)) // animate between 1 and 3
 
-			dc.SetRGB(0, 0, 0)
-			dc.Clear()
+		dc.SetRGB(0, 0, 0)
+		dc.Clear()
 
-			hue := math.Mod(now*0.1, 1.0)
-			r, g, b := hsvToRGB(hue, 1.0, 1.0)
-			dc.SetRGB(r, g, b)
+		hue := math.Mod(now*0.1, 1.0)
+		red, green, blue := ren.hsv(hue, 1.0, 1.0)
+		dc.SetRGB(red, green, blue)
 
-			// Points of triangle
-			x1, y1 := width/2, 0.0
-			x2, y2 := 0.0, height
-			x3, y3 := width, height
+		// Points of triangle
+		x1, y1 := width/2, 0.0
+		x2, y2 := 0.0, height
+		x3, y3 := width, height
 
-			drawTriangle(dc, x1, y1, x2, y2, x3, y3, depth)
+		drawTriangle(dc, x1, y1, x2, y2, x3, y3, depth)
 
-			ren.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(200 * time.Millisecond)
-		}
-	}
+		return ren.screen.Present()
+	})
 }
 
 type FluidDreamRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func FluidDream(screen *rgbmatrix.Screen) *FluidDreamRenderer {
@@ -1713,58 +1428,43 @@ func FluidDream(screen *rgbmatrix.Screen) *FluidDreamRenderer {
 }
 
 func (r *FluidDreamRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	width := float64(dc.Width())
-	height := float64(dc.Height())
-	start := time.Now()
+	bounds := r.screen.Frame().Bounds()
+	width := float64(bounds.Dx())
+	height := float64(bounds.Dy())
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			t := time.Since(start).Seconds()
-			dc.Clear()
-
-			for y := 0.0; y < height; y++ {
-				for x := 0.0; x < width; x++ {
-					nx := x / width
-					ny := y / height
+	return r.runShader(ctx, r.screen, func(px, py int, t float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		nx := x / width
+		ny := y / height
 
-					// Smooth coordinate motion
-					u := nx + 0.1*math.Sin(ny*10+t*0.3)
-					v := ny + 0.1*math.Cos(nx*10-t*0.2)
+		// Smooth coordinate motion
+		u := nx + 0.1*math.Sin(ny*10+t*0.3)
+		v := ny + 0.1*math.Cos(nx*10-t*0.2)
 
-					// Complex wave field
-					value := math.Sin(u*8+v*4+t*0.7) +
-						math.Cos(u*10-v*8-t*0.5) +
-						math.Sin((u+v)*15-t*0.2)
+		// Complex wave field
+		value := math.Sin(u*8+v*4+t*0.7) +
+			math.Cos(u*10-v*8-t*0.5) +
+			math.Sin((u+v)*15-t*0.2)
 
-					value = value / 3.0 // normalize to [-1, 1]
+		value = value / 3.0 // normalize to [-1, 1]
 
-					hue := math.Mod((value+1)/2+t*0.1, 1.0)
-					brightness := 0.4 + 0.6*math.Sin(value*2+t*0.8)
+		hue := math.Mod((value+1)/2+t*0.1, 1.0)
+		brightness := 0.4 + 0.6*math.Sin(value*2+t*0.8)
 
-					r, g, b := hsvToRGB(hue, 1.0, brightness)
-					minVal := 0.15
-					r = math.Max(r, minVal)
-					g = math.Max(g, minVal)
-					b = math.Max(b, minVal)
+		red, green, blue := r.hsv(hue, 1.0, brightness)
+		minVal := 0.15
+		red = math.Max(red, minVal)
+		green = math.Max(green, minVal)
+		blue = math.Max(blue, minVal)
 
-					dc.SetRGB(r, g, b)
-					dc.SetPixel(int(x), int(y))
-				}
-			}
-
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
-		}
-	}
+		return rgb(red, green, blue)
+	})
 }
 
 // FluidRainbowRenderer creates a smoothly evolving rainbow that flows across the screen like liquid ink.
 type FluidRainbowRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func FluidRainbow(screen *rgbmatrix.Screen) *FluidRainbowRenderer {
@@ -1772,51 +1472,36 @@ func FluidRainbow(screen *rgbmatrix.Screen) *FluidRainbowRenderer {
 }
 
 func (r *FluidRainbowRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	width := float64(dc.Width())
-	height := float64(dc.Height())
-	start := time.Now()
+	bounds := r.screen.Frame().Bounds()
+	width := float64(bounds.Dx())
+	height := float64(bounds.Dy())
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			t := time.Since(start).Seconds()
-			dc.Clear()
-
-			for y := 0.0; y < height; y++ {
-				for x := 0.0; x < width; x++ {
-					xf := x / width
-					yf := y / height
-
-					// Offset fields for fluid movement
-					u := xf + 0.1*math.Sin(yf*10+t*0.4)
-					v := yf + 0.1*math.Cos(xf*10-t*0.3)
-
-					// Use sin+cos waves to distort hue across surface
-					value := math.Sin(u*6+t) + math.Cos(v*8-t*1.2)
-					hue := math.Mod((value+2)/4+t*0.05, 1.0)
-					brightness := 0.7 + 0.3*math.Sin(value*2+t*0.8)
-
-					r, g, b := hsvToRGB(hue, 1.0, brightness)
-					r = math.Max(r, 0.15)
-					g = math.Max(g, 0.15)
-					b = math.Max(b, 0.15)
-
-					dc.SetRGB(r, g, b)
-					dc.SetPixel(int(x), int(y))
-				}
-			}
+	return r.runShader(ctx, r.screen, func(px, py int, t float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		xf := x / width
+		yf := y / height
 
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
-		}
-	}
+		// Offset fields for fluid movement
+		u := xf + 0.1*math.Sin(yf*10+t*0.4)
+		v := yf + 0.1*math.Cos(xf*10-t*0.3)
+
+		// Use sin+cos waves to distort hue across surface
+		value := math.Sin(u*6+t) + math.Cos(v*8-t*1.2)
+		hue := math.Mod((value+2)/4+t*0.05, 1.0)
+		brightness := 0.7 + 0.3*math.Sin(value*2+t*0.8)
+
+		red, green, blue := r.hsv(hue, 1.0, brightness)
+		red = math.Max(red, 0.15)
+		green = math.Max(green, 0.15)
+		blue = math.Max(blue, 0.15)
+
+		return rgb(red, green, blue)
+	})
 }
 
 type OrbitingMetaballsRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func OrbitingMetaballs(screen *rgbmatrix.Screen) *OrbitingMetaballsRenderer {
@@ -1830,10 +1515,9 @@ func (r *OrbitingMetaballsRenderer) Render(ctx context.Context, _ ...AfterRender
 		offset float64
 	}
 
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w := float64(dc.Width())
-	h := float64(dc.Height())
-	start := time.Now()
+	bounds := r.screen.Frame().Bounds()
+	w := float64(bounds.Dx())
+	h := float64(bounds.Dy())
 
 	centerX := w / 2
 	centerY := h / 2
@@ -1845,47 +1529,34 @@ func (r *OrbitingMetaballsRenderer) Render(ctx context.Context, _ ...AfterRender
 		{radius: 8, speed: 1.5, offset: math.Pi},
 	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			now := time.Since(start).Seconds()
-			dc.Clear()
-
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					sum := 0.0
-					for _, blob := range blobs {
-						angle := now*blob.speed + blob.offset
-						orbX := centerX + math.Cos(angle)*w*0.25
-						orbY := centerY + math.Sin(angle)*h*0.25
-						dx := x - orbX
-						dy := y - orbY
-						distSq := dx*dx + dy*dy
-						sum += blob.radius * blob.radius / (distSq + 1)
-					}
-					val := math.Min(sum/4.0, 1.0)
-					hue := math.Mod(0.65+0.3*val+now*0.05, 1.0)
-					bright := math.Pow(val, 1.4)
+	return r.runShader(ctx, r.screen, func(px, py int, now float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		sum := 0.0
+		for _, blob := range blobs {
+			angle := now*blob.speed + blob.offset
+			orbX := centerX + math.Cos(angle)*w*0.25
+			orbY := centerY + math.Sin(angle)*h*0.25
+			dx := x - orbX
+			dy := y - orbY
+			distSq := dx*dx + dy*dy
+			sum += blob.radius * blob.radius / (distSq + 1)
+		}
+		val := math.Min(sum/4.0, 1.0)
+		hue := math.Mod(0.65+0.3*val+now*0.05, 1.0)
+		bright := math.Pow(val, 1.4)
 
-					r, g, b := hsvToRGB(hue, 0.8, bright)
-					r = math.Max(r, 0.15)
-					g = math.Max(g, 0.15)
-					b = math.Max(b, 0.15)
+		red, green, blue := r.hsv(hue, 0.8, bright)
+		red = math.Max(red, 0.15)
+		green = math.Max(green, 0.15)
+		blue = math.Max(blue, 0.15)
 
-					dc.SetRGB(r, g, b)
-					dc.SetPixel(int(x), int(y))
-				}
-			}
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
-		}
-	}
+		return rgb(red, green, blue)
+	})
 }
 
 type MarbleShaderRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 func MarbleShader(screen *rgbmatrix.Screen) *MarbleShaderRenderer {
@@ -1893,43 +1564,27 @@ func MarbleShader(screen *rgbmatrix.Screen) *MarbleShaderRenderer {
 }
 
 func (r *MarbleShaderRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w := float64(dc.Width())
-	h := float64(dc.Height())
-	start := time.Now()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			t := time.Since(start).Seconds()
-			dc.Clear()
-
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					xf := x / w
-					yf := y / h
+	bounds := r.screen.Frame().Bounds()
+	w := float64(bounds.Dx())
+	h := float64(bounds.Dy())
 
-					noise := math.Sin((xf*10+math.Sin(yf*10+t*0.2))*3 + t)
-					marble := math.Sin(xf*20 + noise*2 + t*0.5)
-					value := (marble + 1) / 2
+	return r.runShader(ctx, r.screen, func(px, py int, t float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		xf := x / w
+		yf := y / h
 
-					hue := math.Mod(0.5+value*0.3+t*0.02, 1.0)
-					brightness := 0.4 + 0.6*value
+		noise := math.Sin((xf*10+math.Sin(yf*10+t*0.2))*3 + t)
+		marble := math.Sin(xf*20 + noise*2 + t*0.5)
+		value := (marble + 1) / 2
 
-					r, g, b := hsvToRGB(hue, 0.7, brightness)
-					r = math.Max(r, 0.15)
-					g = math.Max(g, 0.15)
-					b = math.Max(b, 0.15)
+		hue := math.Mod(0.5+value*0.3+t*0.02, 1.0)
+		brightness := 0.4 + 0.6*value
 
-					dc.SetRGB(r, g, b)
-					dc.SetPixel(int(x), int(y))
-				}
-			}
+		red, green, blue := r.hsv(hue, 0.7, brightness)
+		red = math.Max(red, 0.15)
+		green = math.Max(green, 0.15)
+		blue = math.Max(blue, 0.15)
 
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
-		}
-	}
+		return rgb(red, green, blue)
+	})
 }
@@ -1,6 +1,12 @@
 package renderers
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
 
 type ScreenType int
 
@@ -11,6 +17,9 @@ const (
 	TypeDashboard
 	TypePlayground
 	TypeAnimation
+	TypeText
+	TypeLayout
+	TypeBlank
 )
 
 func (t ScreenType) String() string {
@@ -27,15 +36,71 @@ func (t ScreenType) String() string {
 		return "playground"
 	case TypeAnimation:
 		return "animation"
+	case TypeText:
+		return "text"
+	case TypeLayout:
+		return "layout"
+	case TypeBlank:
+		return "blank"
 	default:
 		return "unknown"
 	}
 }
 
+// ParseScreenType returns the ScreenType with the given String form.
+func ParseScreenType(name string) (ScreenType, error) {
+	for t := ScreenType(0); t.String() != "unknown"; t++ {
+		if t.String() == name {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: renderer type %q", ErrUnknownContent, name)
+}
+
 type Command struct {
-	Type        ScreenType
-	Name        string
+	Type ScreenType
+	// Name identifies the content. For TypeText it is the message itself,
+	// while TypeBlank has none.
+	Name string
+	// Params holds renderer-specific options, such as the font of text, the
+	// speed of an animation, the palette of an animation or dashboard or the
+	// content of each region of a layout.
+	Params map[string]string
+	// IsTemporary commands are notifications. They are queued by Priority
+	// and shown for Duration on top of the persistent renderer, and are
+	// dropped if they waited longer than a non-zero TTL.
 	IsTemporary bool
-	Context     context.Context
-	Result      chan error
+	Priority    int
+	Duration    time.Duration
+	TTL         time.Duration
+	// Transition is how the previous content blends into this one.
+	Transition rgbmatrix.TransitionStyle
+	// Finished is closed once a notification ended, expired or was
+	// withdrawn, if it is not nil.
+	Finished chan struct{}
+	// Queued receives the ID of a notification once it was queued, if it
+	// is not nil. It must be buffered.
+	Queued  chan uint64
+	Context context.Context
+	Result  chan error
+}
+
+// send sends a command to the update loop and waits until its renderer
+// started or failed, or ctx is done.
+func send(ctx context.Context, commands chan<- Command, command Command) error {
+	result := make(chan error, 1)
+	command.Context = ctx
+	command.Result = result
+	select {
+	case commands <- command:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
@@ -10,10 +10,9 @@ import (
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
 )
 
-const darts180FrameInterval = time.Second / 30
-
 type Darts180Renderer struct {
-	screen   *rgbmatrix.Screen
+	screen *rgbmatrix.Screen
+	animated
 	confetti []darts180Confetti
 }
 
@@ -48,21 +47,10 @@ func Darts180(screen *rgbmatrix.Screen) *Darts180Renderer {
 }
 
 func (r *Darts180Renderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	start := time.Now()
-	ticker := time.NewTicker(darts180FrameInterval)
-	defer ticker.Stop()
-
-	for {
-		if err := r.drawFrame(ctx, dc, time.Since(start).Seconds()); err != nil {
-			return err
-		}
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-		}
-	}
+	dc := gg.NewContextForRGBA(r.screen.Frame())
+	return r.run(ctx, func(elapsed time.Duration) error {
+		return r.drawFrame(ctx, dc, r.seconds(elapsed))
+	})
 }
 
 func (r *Darts180Renderer) drawFrame(ctx context.Context, dc *gg.Context, elapsed float64) error {
@@ -76,7 +64,7 @@ func (r *Darts180Renderer) drawFrame(ctx context.Context, dc *gg.Context, elapse
 	r.drawDarts(dc, width, height, elapsed)
 	r.drawScore(dc, width, height, elapsed)
 
-	return r.screen.ShowImage(ctx, dc.Image())
+	return r.screen.Present()
 }
 
 func (r *Darts180Renderer) drawNeonBackdrop(dc *gg.Context, width, height, elapsed float64) {
@@ -88,7 +76,7 @@ func (r *Darts180Renderer) drawNeonBackdrop(dc *gg.Context, width, height, elaps
 	for band := 0; band < 5; band++ {
 		y := vanishingY + float64(band*band)*height*0.025
 		hue := math.Mod(elapsed*0.08+float64(band)*0.12+0.55, 1)
-		red, green, blue := hsvToRGB(hue, 0.85, 0.65)
+		red, green, blue := r.hsv(hue, 0.85, 0.65)
 		dc.SetRGBA(red, green, blue, 0.32)
 		dc.SetLineWidth(0.6)
 		dc.DrawLine(2, y, width-3, y)
@@ -96,7 +84,7 @@ func (r *Darts180Renderer) drawNeonBackdrop(dc *gg.Context, width, height, elaps
 	}
 	for lane := -4; lane <= 4; lane++ {
 		hue := math.Mod(0.82+float64(lane)*0.035+elapsed*0.04, 1)
-		red, green, blue := hsvToRGB(hue, 0.9, 0.8)
+		red, green, blue := r.hsv(hue, 0.9, 0.8)
 		dc.SetRGBA(red, green, blue, 0.30)
 		dc.DrawLine(vanishingX+float64(lane)*2, vanishingY, vanishingX+float64(lane)*width*0.15, height)
 		dc.Stroke()
@@ -114,7 +102,7 @@ func (r *Darts180Renderer) drawConfetti(dc *gg.Context, width, height, elapsed f
 		x := math.Mod(piece.x+math.Sin(elapsed*1.7+piece.phase)*4+width, width)
 		y := math.Mod(piece.y+elapsed*piece.speed, height)
 		brightness := 0.65 + 0.35*math.Sin(elapsed*4+piece.phase)
-		red, green, blue := hsvToRGB(math.Mod(piece.hue+elapsed*0.04, 1), 0.9, brightness)
+		red, green, blue := r.hsv(math.Mod(piece.hue+elapsed*0.04, 1), 0.9, brightness)
 		dc.SetRGBA(red, green, blue, 0.85)
 		dc.SetLineWidth(1)
 		dc.DrawLine(x, y, x+math.Sin(piece.phase)*2, y+1)
@@ -202,7 +190,7 @@ func (r *Darts180Renderer) drawScore(dc *gg.Context, width, height, elapsed floa
 	startY := int(height)/2 - (7*scale)/2 + max(2, int(height*0.05))
 	pulse := 0.82 + 0.18*math.Sin(elapsed*5)
 	hue := math.Mod(0.88+elapsed*0.035, 1)
-	red, green, blue := hsvToRGB(hue, 0.82, pulse)
+	red, green, blue := r.hsv(hue, 0.82, pulse)
 
 	for index, digit := range []byte("180") {
 		x := startX + index*(digitWidth+gap)
@@ -16,7 +16,7 @@ func TestDarts180DrawsAFrame(t *testing.T) {
 	}
 	screen := rgbmatrix.NewScreen(matrix)
 	renderer := Darts180(screen)
-	dc := gg.NewContextForImage(screen.Canvas)
+	dc := gg.NewContextForRGBA(screen.Frame())
 
 	if err := renderer.drawFrame(context.Background(), dc, 1.8); err != nil {
 		t.Fatal(err)
@@ -14,16 +14,14 @@ import (
 
 type UserCountDashboardRenderer struct {
 	*MatrixWriter
+	paletted
+	paced
 	UserCount  int
 	MatchCount int
 	c          *autodarts.AutodartsWSClient
 }
 
-func UserCountDashboard(screen *rgbmatrix.Screen) (*UserCountDashboardRenderer, error) {
-	config, err := rgbmatrix.LoadConfigFile("./config.toml")
-	if err != nil {
-		return nil, fmt.Errorf("load config: %w", err)
-	}
+func UserCountDashboard(screen *rgbmatrix.Screen, config rgbmatrix.Config) (*UserCountDashboardRenderer, error) {
 	font, err := rgbmatrix.LoadBDF(config.Dashboards.Font)
 	if err != nil {
 		return nil, fmt.Errorf("load dashboard font: %w", err)
@@ -42,11 +40,11 @@ func (r *UserCountDashboardRenderer) Render(ctx context.Context, _ ...AfterRende
 	onUsers := r.c.OnOnlineUsersChange(ctx)
 	onMatch := r.c.OnMatchCountChange(ctx)
 
-	blue := color.RGBA{0, 0, 200, 255}
+	blue := r.colorAt(0, color.RGBA{0, 0, 200, 255})
 	white := color.RGBA{200, 200, 200, 255}
-	cyan := color.RGBA{0, 200, 200, 255}
+	cyan := r.colorAt(0.5, color.RGBA{0, 200, 200, 255})
 
-	draw := func() {
+	draw := func(clock string) {
 		r.WriteLn("Autodarts", blue)
 		r.MatrixWriter.y += 5
 		r.Write("Users:   ", white)
@@ -54,20 +52,40 @@ func (r *UserCountDashboardRenderer) Render(ctx context.Context, _ ...AfterRende
 		r.Write("Matches: ", white)
 		r.WriteLn(p.Sprintf("%5d", r.MatchCount), cyan)
 		r.NewLine()
-		r.WriteLn(time.Now().Format("15:04"), white)
+		r.WriteLn(clock, white)
 		r.Flush()
 	}
 
-	for {
-		select {
-		case <-ctx.Done():
+	// The dashboard only redraws when the counts or the clock changed.
+	var shown struct {
+		users, matches int
+		clock          string
+	}
+	return r.run(ctx, func(time.Duration) error {
+		for polling := true; polling; {
+			select {
+			case msg, ok := <-onUsers:
+				if !ok {
+					onUsers = nil
+					continue
+				}
+				r.UserCount = msg.Online
+			case msg, ok := <-onMatch:
+				if !ok {
+					onMatch = nil
+					continue
+				}
+				r.MatchCount = msg.Count
+			default:
+				polling = false
+			}
+		}
+		clock := time.Now().Format("15:04")
+		if shown.clock == clock && shown.users == r.UserCount && shown.matches == r.MatchCount {
 			return nil
-		case msg := <-onUsers:
-			r.UserCount = msg.Online
-			draw()
-		case msg := <-onMatch:
-			r.MatchCount = msg.Count
-			draw()
 		}
-	}
+		shown.users, shown.matches, shown.clock = r.UserCount, r.MatchCount, clock
+		draw(clock)
+		return nil
+	})
 }
@@ -15,15 +15,13 @@ import (
 
 type ShopifyDashboardRenderer struct {
 	*MatrixWriter
+	paletted
+	paced
 	Data   shopifyData
 	config rgbmatrix.Config
 }
 
-func ShopifyDashboard(ctx context.Context, screen *rgbmatrix.Screen) (*ShopifyDashboardRenderer, error) {
-	config, err := rgbmatrix.LoadConfigFile("./config.toml")
-	if err != nil {
-		return nil, fmt.Errorf("load config: %w", err)
-	}
+func ShopifyDashboard(ctx context.Context, screen *rgbmatrix.Screen, config rgbmatrix.Config) (*ShopifyDashboardRenderer, error) {
 	font, err := rgbmatrix.LoadBDF(config.Dashboards.Font)
 	if err != nil {
 		return nil, fmt.Errorf("load dashboard font: %w", err)
@@ -40,18 +38,13 @@ func ShopifyDashboard(ctx context.Context, screen *rgbmatrix.Screen) (*ShopifyDa
 }
 
 func (r *ShopifyDashboardRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	t := time.NewTicker(5 * time.Second)
-	defer t.Stop()
-
-	orange := color.RGBA{200, 100, 0, 255}
+	orange := r.colorAt(0.75, color.RGBA{200, 100, 0, 255})
 	white := color.RGBA{200, 200, 200, 255}
-	green := color.RGBA{0, 200, 0, 255}
-	blue := color.RGBA{0, 200, 200, 255}
+	green := r.colorAt(0, color.RGBA{0, 200, 0, 255})
+	blue := r.colorAt(0.5, color.RGBA{0, 200, 200, 255})
 
 	p := message.NewPrinter(language.English)
 
-	requestCtx, cancelRequest := context.WithCancel(ctx)
-
 	draw := func() {
 		r.WriteLn("Shopify", green)
 		r.MatrixWriter.y += 5
@@ -69,31 +62,62 @@ func (r *ShopifyDashboardRenderer) Render(ctx context.Context, _ ...AfterRenderF
 		r.Flush()
 	}
 
-	draw()
+	updates := make(chan shopifyData, 1)
+	go r.poll(ctx, updates)
+
+	// The dashboard flashes orange for shopifyFlash when the data changed
+	// and otherwise only redraws when the flash ends.
+	var flashUntil time.Duration
+	flashing, drawn := false, false
+	return r.run(ctx, func(t time.Duration) error {
+		if !drawn {
+			draw()
+			drawn = true
+		}
+		select {
+		case data := <-updates:
+			if data != r.Data {
+				r.Data = data
+				r.screen.Fill(orange)
+				draw()
+				flashUntil, flashing = t+shopifyFlash, true
+			}
+		default:
+		}
+		if flashing && t >= flashUntil {
+			r.screen.Fill(color.RGBA{0, 0, 0, 0})
+			draw()
+			flashing = false
+		}
+		return nil
+	})
+}
+
+const (
+	shopifyPollInterval = 5 * time.Second
+	shopifyFlash        = 200 * time.Millisecond
+)
 
+// poll fetches the Shopify data every shopifyPollInterval until ctx is done.
+func (r *ShopifyDashboardRenderer) poll(ctx context.Context, updates chan<- shopifyData) {
+	t := time.NewTicker(shopifyPollInterval)
+	defer t.Stop()
 	for {
 		select {
 		case <-ctx.Done():
-			cancelRequest()
-			return nil
+			return
 		case <-t.C:
+			requestCtx, cancelRequest := context.WithTimeout(ctx, 1*time.Second)
+			data, err := getTotalSales(requestCtx, r.config)
 			cancelRequest()
-			requestCtx, cancelRequest = context.WithTimeout(ctx, 1*time.Second)
-			go func() {
-				defer cancelRequest()
-				newData, err := getTotalSales(requestCtx, r.config)
-				if err != nil {
-					return
-				}
-				if newData != r.Data {
-					r.Data = newData
-					r.screen.Fill(orange)
-					draw()
-					time.Sleep(200 * time.Millisecond)
-					r.screen.Fill(color.RGBA{0, 0, 0, 0})
-					draw()
-				}
-			}()
+			if err != nil {
+				continue
+			}
+			select {
+			case updates <- data:
+			case <-ctx.Done():
+				return
+			}
 		}
 	}
 }
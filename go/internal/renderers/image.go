@@ -5,18 +5,26 @@ import (
 	"fmt"
 	"image"
 	"image/gif"
+	"path/filepath"
 
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/fs"
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
 )
 
+const (
+	// ImagesDir contains the PNGs that images and image overlays show.
+	ImagesDir = "images/pngs"
+	// GIFsDir contains the GIFs that GIF content plays.
+	GIFsDir = "images/gifs"
+)
+
 type ImageRenderer struct {
 	screen *rgbmatrix.Screen
 	image  image.Image
 }
 
 func Image(screen *rgbmatrix.Screen, path string) (*ImageRenderer, error) {
-	img, err := fs.LoadPNG(fmt.Sprintf("images/pngs/%s.png", path))
+	img, err := fs.LoadPNG(filepath.Join(ImagesDir, path+".png"))
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidAsset, err)
 	}
@@ -27,30 +35,42 @@ func (r *ImageRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error
 	return r.screen.ShowImage(ctx, r.image)
 }
 
+// BlankRenderer turns all LEDs off.
+type BlankRenderer struct {
+	screen *rgbmatrix.Screen
+}
+
+func Blank(screen *rgbmatrix.Screen) *BlankRenderer {
+	return &BlankRenderer{screen: screen}
+}
+
+func (r *BlankRenderer) Render(context.Context, ...AfterRenderFunc) error {
+	return r.screen.Clear()
+}
+
 type GIFOnceRenderer struct {
 	screen *rgbmatrix.Screen
 	gif    *gif.GIF
 }
 
 func GIFOnce(screen *rgbmatrix.Screen, path string) (*GIFOnceRenderer, error) {
-	img, err := fs.LoadGIF(fmt.Sprintf("images/gifs/%s.gif", path))
+	img, err := fs.LoadGIF(filepath.Join(GIFsDir, path+".gif"))
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidAsset, err)
 	}
 	return &GIFOnceRenderer{screen: screen, gif: img}, nil
 }
 
+// Render plays the GIF once and calls the callback when it finished, unless
+// ctx was done before.
 func (r *GIFOnceRenderer) Render(ctx context.Context, cb ...AfterRenderFunc) error {
-	go func() {
-		select {
-		case <-ctx.Done():
-			return
-		case <-r.screen.PlayGIF(ctx, r.gif):
-			if len(cb) == 1 {
-				cb[0]()
-			}
+	select {
+	case <-ctx.Done():
+	case <-r.screen.PlayGIF(ctx, r.gif):
+		if len(cb) == 1 && ctx.Err() == nil {
+			cb[0]()
 		}
-	}()
+	}
 	return nil
 }
 
@@ -60,14 +80,15 @@ type GIFLoopRenderer struct {
 }
 
 func GIFLoop(screen *rgbmatrix.Screen, path string) (*GIFLoopRenderer, error) {
-	img, err := fs.LoadGIF(fmt.Sprintf("images/gifs/%s.gif", path))
+	img, err := fs.LoadGIF(filepath.Join(GIFsDir, path+".gif"))
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidAsset, err)
 	}
 	return &GIFLoopRenderer{screen: screen, gif: img}, nil
 }
 
+// Render loops the GIF until ctx is done.
 func (r *GIFLoopRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	r.screen.LoopGIF(ctx, r.gif)
+	<-r.screen.LoopGIF(ctx, r.gif)
 	return nil
 }
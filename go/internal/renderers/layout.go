@@ -0,0 +1,119 @@
+package renderers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+// ParseRegionContent parses the content of a layout region in the form
+// type:name, for example dashboard:clock. Only content that can run
+// indefinitely on a part of the screen is allowed.
+func ParseRegionContent(value string) (Content, error) {
+	kind, name, ok := strings.Cut(value, ":")
+	if !ok || name == "" {
+		return Content{}, fmt.Errorf("%w: region content %q is not in the form type:name", ErrInvalidParameter, value)
+	}
+	screenType, err := ParseScreenType(kind)
+	if err != nil {
+		return Content{}, fmt.Errorf("%w: region content %q: %v", ErrInvalidParameter, value, err)
+	}
+	switch screenType {
+	case TypeImage, TypeGIF, TypeDashboard, TypeAnimation:
+		return Content{Type: screenType, Name: name}, nil
+	default:
+		return Content{}, fmt.Errorf("%w: %s cannot be shown in a region", ErrInvalidParameter, screenType)
+	}
+}
+
+type layoutRegion struct {
+	name     string
+	prepared preparedRenderer
+}
+
+// LayoutRenderer shows content in the regions of a layout. Each region
+// renderer draws into its own sub-screen, and the compositor renders the
+// combined frame.
+type LayoutRenderer struct {
+	compositor *rgbmatrix.Compositor
+	regions    []layoutRegion
+}
+
+// prepareLayout prepares the renderers of a layout. Params assign content to
+// regions by name; regions without content stay black. Region content
+// samples from palette and is drawn at the frame rate of config.
+func prepareLayout(ctx context.Context, cmd Command, matrix rgbmatrix.Matrix, config rgbmatrix.Config, palette string) (preparedRenderer, error) {
+	layout, ok := config.Layouts[cmd.Name]
+	if !ok {
+		return preparedRenderer{}, fmt.Errorf("%w: layout %q", ErrUnknownContent, cmd.Name)
+	}
+
+	names := make([]string, 0, len(cmd.Params))
+	for name := range cmd.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	renderer := &LayoutRenderer{compositor: rgbmatrix.NewCompositor(matrix)}
+	for _, name := range names {
+		region, ok := layout.Region(name)
+		if !ok {
+			return preparedRenderer{}, fmt.Errorf("%w: layout %q has no region %q", ErrInvalidParameter, cmd.Name, name)
+		}
+		content, err := ParseRegionContent(cmd.Params[name])
+		if err != nil {
+			return preparedRenderer{}, err
+		}
+		screen := rgbmatrix.NewScreen(renderer.compositor.Region(region.Bounds()))
+		prepared, err := prepare(ctx, withPalette(Command{Type: content.Type, Name: content.Name}, palette), screen, config)
+		if err != nil {
+			return preparedRenderer{}, fmt.Errorf("region %q: %w", name, err)
+		}
+		setFPS(prepared.renderer, config.Display.FPS)
+		renderer.regions = append(renderer.regions, layoutRegion{name: name, prepared: prepared})
+	}
+	return preparedRenderer{renderer: renderer, async: true}, nil
+}
+
+// Render starts the region renderers and composes their frames until ctx is
+// done. A failing region stops the whole layout.
+func (r *LayoutRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
+	ctx, cancel := context.WithCancel(ctx)
+	// The regions are stopped before the layout returns, so that none of
+	// them draws once the next renderer started.
+	var running []<-chan struct{}
+	defer func() {
+		cancel()
+		for _, done := range running {
+			<-done
+		}
+	}()
+
+	failures := make(chan error, len(r.regions))
+	for _, region := range r.regions {
+		exited := func(err error) {
+			if err != nil {
+				failures <- fmt.Errorf("region %q: %w", region.name, err)
+			}
+		}
+		done, err := region.prepared.start(ctx, exited)
+		running = append(running, done)
+		if err != nil {
+			return fmt.Errorf("region %q: %w", region.name, err)
+		}
+	}
+
+	composed := make(chan error, 1)
+	go func() { composed <- r.compositor.Run(ctx) }()
+	select {
+	case err := <-failures:
+		cancel()
+		<-composed
+		return err
+	case err := <-composed:
+		return err
+	}
+}
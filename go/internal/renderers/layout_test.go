@@ -0,0 +1,54 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers/animation"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestPrepareLayout(t *testing.T) {
+	layouts := map[string]rgbmatrix.Layout{
+		"split": {Regions: []rgbmatrix.Region{
+			{Name: "left", Width: 8, Height: 16},
+			{Name: "right", X: 8, Width: 8, Height: 16},
+		}},
+	}
+	plasma := animation.AnimationStrings()[0]
+	matrix := newPrepareMatrix(16, 16)
+
+	prepared, err := prepareLayout(context.Background(), Command{
+		Type:   TypeLayout,
+		Name:   "split",
+		Params: map[string]string{"left": "animation:" + plasma, "right": "animation:" + plasma},
+	}, matrix, rgbmatrix.Config{Layouts: layouts}, DefaultPalette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layout, ok := prepared.renderer.(*LayoutRenderer)
+	if !ok || !prepared.async || len(layout.regions) != 2 || layout.regions[0].name != "left" {
+		t.Fatalf("layout was not prepared correctly: %#v", prepared)
+	}
+
+	tests := []struct {
+		name string
+		cmd  Command
+		want error
+	}{
+		{name: "unknown layout", cmd: Command{Name: "quad"}, want: ErrUnknownContent},
+		{name: "unknown region", cmd: Command{Name: "split", Params: map[string]string{"top": "animation:" + plasma}}, want: ErrInvalidParameter},
+		{name: "temporary content", cmd: Command{Name: "split", Params: map[string]string{"left": "gif-once:party"}}, want: ErrInvalidParameter},
+		{name: "text content", cmd: Command{Name: "split", Params: map[string]string{"left": "text:hello"}}, want: ErrInvalidParameter},
+		{name: "missing name", cmd: Command{Name: "split", Params: map[string]string{"left": "animation"}}, want: ErrInvalidParameter},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := prepareLayout(context.Background(), test.cmd, matrix, rgbmatrix.Config{Layouts: layouts}, DefaultPalette)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package renderers
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultNotificationDuration is how long a notification is shown when its
+// command has no duration. GIF-once notifications without a duration are
+// shown until the GIF finished playing instead.
+const DefaultNotificationDuration = 10 * time.Second
+
+// Notification is temporary content that is shown on top of the persistent
+// renderer. Notifications with a higher priority preempt the active one;
+// notifications with the same priority are shown in the order they arrived.
+type Notification struct {
+	ID uint64
+	Content
+	Priority int
+	// Duration is how long the notification is shown. Zero means until
+	// its renderer finished, which only GIF-once content does.
+	Duration time.Duration
+	// TTL is how long the notification may wait in the queue before it is
+	// dropped. Zero means it never expires.
+	TTL        time.Duration
+	EnqueuedAt time.Time
+	// Active reports whether the notification is shown right now.
+	Active bool
+	// expiresAt is when a waiting notification is dropped, or zero if it
+	// never is.
+	expiresAt time.Time
+}
+
+// queuedNotification is a notification owned by the update loop.
+type queuedNotification struct {
+	Notification
+	cmd      Command
+	prepared preparedRenderer
+	// remaining is the display time left, which is less than Duration
+	// after the notification was preempted.
+	remaining time.Duration
+	startedAt time.Time
+	shown     bool
+	ended     bool
+}
+
+func newQueuedNotification(id uint64, cmd Command, prepared preparedRenderer, now time.Time) *queuedNotification {
+	duration := cmd.Duration
+	if duration <= 0 && cmd.Type != TypeGIFOnce {
+		duration = DefaultNotificationDuration
+	}
+	stored := cmd
+	stored.Context = nil
+	stored.Result = nil
+	stored.Queued = nil
+	return &queuedNotification{
+		Notification: Notification{
+			ID:         id,
+			Content:    Content{Type: cmd.Type, Name: cmd.Name},
+			Priority:   cmd.Priority,
+			Duration:   duration,
+			TTL:        cmd.TTL,
+			EnqueuedAt: now,
+		},
+		cmd:       stored,
+		prepared:  prepared,
+		remaining: duration,
+	}
+}
+
+// end closes the Finished channel of the notification once it leaves the
+// update loop.
+func (n *queuedNotification) end() {
+	if n.cmd.Finished != nil && !n.ended {
+		close(n.cmd.Finished)
+	}
+	n.ended = true
+}
+
+func (n *queuedNotification) expired(now time.Time) bool {
+	return !n.shown && n.TTL > 0 && now.Sub(n.EnqueuedAt) > n.TTL
+}
+
+// notificationQueue holds waiting notifications, highest priority first and
+// in arrival order within a priority.
+type notificationQueue []*queuedNotification
+
+func (q *notificationQueue) push(n *queuedNotification) {
+	index := sort.Search(len(*q), func(i int) bool {
+		other := (*q)[i]
+		return other.Priority < n.Priority || (other.Priority == n.Priority && other.ID > n.ID)
+	})
+	*q = append(*q, nil)
+	copy((*q)[index+1:], (*q)[index:])
+	(*q)[index] = n
+}
+
+// remove removes the notification that closes finished when it ended. It
+// returns nil if no such notification waits.
+func (q *notificationQueue) remove(finished chan struct{}) *queuedNotification {
+	for index, n := range *q {
+		if n.cmd.Finished == finished {
+			*q = append((*q)[:index], (*q)[index+1:]...)
+			return n
+		}
+	}
+	return nil
+}
+
+// pop removes and returns the next notification, dropping notifications whose
+// TTL expired. It returns nil if the queue is empty.
+func (q *notificationQueue) pop(now time.Time) (next *queuedNotification, expired []*queuedNotification) {
+	for len(*q) > 0 {
+		n := (*q)[0]
+		*q = (*q)[1:]
+		if n.expired(now) {
+			expired = append(expired, n)
+			continue
+		}
+		return n, expired
+	}
+	return nil, expired
+}
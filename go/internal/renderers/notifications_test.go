@@ -0,0 +1,79 @@
+package renderers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestNotificationQueueOrder(t *testing.T) {
+	now := time.Now()
+	var queue notificationQueue
+	for _, n := range []Notification{
+		{ID: 1, Priority: 0},
+		{ID: 2, Priority: 5},
+		{ID: 3, Priority: 0, TTL: time.Second, EnqueuedAt: now.Add(-time.Minute)},
+		{ID: 4, Priority: 5},
+		{ID: 5, Priority: 1},
+	} {
+		queue.push(&queuedNotification{Notification: n})
+	}
+
+	var order []uint64
+	var dropped []uint64
+	for {
+		n, expired := queue.pop(now)
+		for _, e := range expired {
+			dropped = append(dropped, e.ID)
+		}
+		if n == nil {
+			break
+		}
+		order = append(order, n.ID)
+	}
+	if want := []uint64{2, 4, 5, 1}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("unexpected order: got %v, want %v", order, want)
+	}
+	if want := []uint64{3}; !reflect.DeepEqual(dropped, want) {
+		t.Fatalf("unexpected expired notifications: got %v, want %v", dropped, want)
+	}
+}
+
+func TestNotificationsPreemptAndRestorePersistentRenderer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	commands := make(chan Command)
+	state := NewState()
+	go updateLoop(ctx, commands, rgbmatrix.NewMemory(16, 16), LoopOptions{State: state})
+
+	for _, command := range []Command{
+		{Type: TypeAnimation, Name: "plasma"},
+		{Type: TypeAnimation, Name: "ripple", IsTemporary: true, Duration: 150 * time.Millisecond},
+		{Type: TypeAnimation, Name: "spiral", IsTemporary: true, Priority: 5, Duration: 50 * time.Millisecond},
+		{Type: TypeAnimation, Name: "tunnel", IsTemporary: true, Duration: 50 * time.Millisecond},
+		{Type: TypeAnimation, Name: "vortex"},
+	} {
+		if err := sendCommand(commands, command); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var names []string
+	for _, n := range state.Notifications() {
+		names = append(names, n.Name)
+	}
+	if want := []string{"spiral", "ripple", "tunnel"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("unexpected notifications: got %v, want %v", names, want)
+	}
+	if snapshot := state.Snapshot(); snapshot.Name != "spiral" || snapshot.RevertTo == nil || snapshot.RevertTo.Name != "vortex" {
+		t.Fatalf("persistent content replaced the active notification: %#v", snapshot)
+	}
+
+	waitForState(t, state, func(snapshot DisplayState) bool { return !snapshot.Temporary && snapshot.Name == "vortex" })
+	if len(state.Notifications()) != 0 {
+		t.Fatalf("notifications left after the queue drained: %v", state.Notifications())
+	}
+}
@@ -0,0 +1,340 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"log"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/fs"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+const (
+	// DefaultClockFormat is the time layout of clock overlays without one.
+	DefaultClockFormat = "15:04"
+	// DefaultOverlayFont is the font of text and clock overlays without one.
+	DefaultOverlayFont = "5x7"
+)
+
+const overlayClockInterval = time.Second
+
+var ErrInvalidOverlay = errors.New("invalid overlay")
+
+var errOverlaysClosed = errors.New("overlays are closed")
+
+var overlayIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+type OverlayType int
+
+const (
+	OverlayText OverlayType = iota
+	OverlayImage
+	OverlayClock
+)
+
+func (t OverlayType) String() string {
+	switch t {
+	case OverlayText:
+		return "text"
+	case OverlayImage:
+		return "image"
+	case OverlayClock:
+		return "clock"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseOverlayType(name string) (OverlayType, error) {
+	for t := OverlayText; t.String() != "unknown"; t++ {
+		if t.String() == name {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: type %q is not one of text, image or clock", ErrInvalidOverlay, name)
+}
+
+// Overlay is content drawn on top of whatever the display shows. Its top
+// left corner is at X, Y and overlays with a higher Z are drawn above lower
+// ones.
+type Overlay struct {
+	ID   string
+	Type OverlayType
+	// Content is the message of text overlays, the image name of image
+	// overlays and the Go time layout of clock overlays.
+	Content string
+	X, Y, Z int
+	// Params style text and clock overlays with the font, color and
+	// background text parameters. The background is transparent unless it
+	// is set.
+	Params map[string]string
+}
+
+type overlayStyle struct {
+	font       *rgbmatrix.BDFFont
+	color      color.RGBA
+	background color.RGBA
+}
+
+// activeOverlay is an overlay with the resources to draw it again.
+type activeOverlay struct {
+	Overlay
+	style overlayStyle
+	image image.Image
+	// text is the clock text that is shown.
+	text string
+}
+
+// Overlays manages the layers drawn on top of the display content.
+type Overlays struct {
+	layers *rgbmatrix.Layers
+	// imagesDir and fontsDir contain the PNGs of image overlays and the
+	// fonts of text and clock overlays.
+	imagesDir string
+	fontsDir  string
+
+	mu       sync.Mutex
+	overlays map[string]*activeOverlay
+	now      func() time.Time
+	// closed is set by Close, which stops Run through stop and waits for
+	// done if Run was started.
+	closed bool
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewOverlays returns overlays drawn into layers that load their images from
+// imagesDir and their fonts from fontsDir, such as ImagesDir and FontsDir.
+func NewOverlays(layers *rgbmatrix.Layers, imagesDir, fontsDir string) *Overlays {
+	return &Overlays{
+		layers:    layers,
+		imagesDir: imagesDir,
+		fontsDir:  fontsDir,
+		overlays:  map[string]*activeOverlay{},
+		now:       time.Now,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Set shows an overlay, replacing the overlay with the same ID, and reports
+// whether it replaced one.
+func (o *Overlays) Set(overlay Overlay) (bool, error) {
+	active, err := o.load(overlay)
+	if err != nil {
+		return false, err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return false, errOverlaysClosed
+	}
+	_, replaced := o.overlays[overlay.ID]
+	if err := o.drawLocked(active); err != nil {
+		return false, err
+	}
+	o.overlays[overlay.ID] = active
+	return replaced, nil
+}
+
+// Delete removes an overlay and reports whether it existed.
+func (o *Overlays) Delete(id string) (bool, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.overlays[id]; !ok {
+		return false, nil
+	}
+	delete(o.overlays, id)
+	_, err := o.layers.RemoveLayer(id)
+	return true, err
+}
+
+// List returns the overlays from bottom to top.
+func (o *Overlays) List() []Overlay {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	overlays := make([]Overlay, 0, len(o.overlays))
+	for _, active := range o.overlays {
+		overlays = append(overlays, active.Overlay)
+	}
+	sort.Slice(overlays, func(i, j int) bool {
+		if overlays[i].Z != overlays[j].Z {
+			return overlays[i].Z < overlays[j].Z
+		}
+		return overlays[i].ID < overlays[j].ID
+	})
+	return overlays
+}
+
+// Run redraws clock overlays when their text changes until ctx is done or
+// the overlays are closed.
+func (o *Overlays) Run(ctx context.Context) {
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return
+	}
+	done := make(chan struct{})
+	o.done = done
+	o.mu.Unlock()
+	defer close(done)
+
+	ticker := time.NewTicker(overlayClockInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.stop:
+			return
+		case <-ticker.C:
+		}
+		o.tick()
+	}
+}
+
+// Close removes every overlay and waits for Run to return, so that nothing
+// is drawn over the display any more. Overlays cannot be set afterwards.
+// Closing nil overlays does nothing.
+func (o *Overlays) Close() error {
+	if o == nil {
+		return nil
+	}
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return nil
+	}
+	o.closed = true
+	close(o.stop)
+	var errs []error
+	for id := range o.overlays {
+		if _, err := o.layers.RemoveLayer(id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	o.overlays = map[string]*activeOverlay{}
+	done := o.done
+	o.mu.Unlock()
+
+	if done != nil {
+		<-done
+	}
+	return errors.Join(errs...)
+}
+
+func (o *Overlays) tick() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, active := range o.overlays {
+		if active.Type != OverlayClock || active.text == o.clockText(active) {
+			continue
+		}
+		if err := o.drawLocked(active); err != nil {
+			log.Printf("overlay %q could not be redrawn: %v", active.ID, err)
+		}
+	}
+}
+
+// load validates an overlay and loads its font or image.
+func (o *Overlays) load(overlay Overlay) (*activeOverlay, error) {
+	if !overlayIDPattern.MatchString(overlay.ID) {
+		return nil, fmt.Errorf("%w: id %q may only contain letters, digits, '-' and '_'", ErrInvalidOverlay, overlay.ID)
+	}
+	width, height := o.layers.Geometry()
+	if overlay.X < 0 || overlay.Y < 0 || overlay.X >= width || overlay.Y >= height {
+		return nil, fmt.Errorf("%w: position %d,%d is outside of the %dx%d display", ErrInvalidOverlay, overlay.X, overlay.Y, width, height)
+	}
+
+	active := &activeOverlay{Overlay: overlay}
+	switch overlay.Type {
+	case OverlayImage:
+		if len(overlay.Params) > 0 {
+			return nil, fmt.Errorf("%w: image overlays have no parameters", ErrInvalidParameter)
+		}
+		if overlay.Content == "" || overlay.Content != filepath.Base(overlay.Content) {
+			return nil, fmt.Errorf("%w: invalid image name %q", ErrInvalidOverlay, overlay.Content)
+		}
+		img, err := fs.LoadPNG(filepath.Join(o.imagesDir, overlay.Content+".png"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: load image %q: %v", ErrInvalidAsset, overlay.Content, err)
+		}
+		active.image = img
+	case OverlayText, OverlayClock:
+		if overlay.Type == OverlayText && overlay.Content == "" {
+			return nil, fmt.Errorf("%w: text overlays need content", ErrInvalidOverlay)
+		}
+		if overlay.Type == OverlayClock && overlay.Content == "" {
+			active.Content = DefaultClockFormat
+		}
+		style, err := parseOverlayStyle(o.fontsDir, overlay.Params)
+		if err != nil {
+			return nil, err
+		}
+		active.style = style
+	default:
+		return nil, fmt.Errorf("%w: unknown type %s", ErrInvalidOverlay, overlay.Type)
+	}
+	return active, nil
+}
+
+func parseOverlayStyle(fontsDir string, params map[string]string) (overlayStyle, error) {
+	fontName := DefaultOverlayFont
+	style := overlayStyle{color: defaultTextOptions().Color}
+	for key, value := range params {
+		var err error
+		switch key {
+		case "font":
+			if value == "" || value != filepath.Base(value) {
+				err = fmt.Errorf("invalid font name")
+			}
+			fontName = value
+		case "color":
+			style.color, err = parseHexColor(value)
+		case "background":
+			style.background, err = parseHexColor(value)
+		default:
+			return overlayStyle{}, fmt.Errorf("%w: unknown overlay parameter %q", ErrInvalidParameter, key)
+		}
+		if err != nil {
+			return overlayStyle{}, fmt.Errorf("%w: overlay parameter %q: %v", ErrInvalidParameter, key, err)
+		}
+	}
+
+	font, err := rgbmatrix.LoadBDF(filepath.Join(fontsDir, fontName+".bdf"))
+	if err != nil {
+		return overlayStyle{}, fmt.Errorf("%w: load font %q: %v", ErrInvalidAsset, fontName, err)
+	}
+	style.font = font
+	return style, nil
+}
+
+func (o *Overlays) clockText(active *activeOverlay) string {
+	return o.now().Format(active.Content)
+}
+
+// drawLocked renders the overlay into an image and sets it as a layer.
+func (o *Overlays) drawLocked(active *activeOverlay) error {
+	img := active.image
+	if active.Type != OverlayImage {
+		text := active.Content
+		if active.Type == OverlayClock {
+			text = o.clockText(active)
+			active.text = text
+		}
+		canvas := image.NewRGBA(image.Rect(0, 0, active.style.font.TextWidth(text), active.style.font.Height()))
+		draw.Draw(canvas, canvas.Bounds(), image.NewUniform(active.style.background), image.Point{}, draw.Src)
+		rgbmatrix.DrawText(canvas, active.style.font, text, 0, 0, active.style.color)
+		img = canvas
+	}
+	return o.layers.SetLayer(active.ID, rgbmatrix.Layer{Image: img, At: image.Pt(active.X, active.Y), Z: active.Z})
+}
@@ -0,0 +1,81 @@
+package renderers
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/display"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+// repositoryFonts are the fonts of the repository, seen from this package.
+var repositoryFonts = filepath.Join("..", "..", "..", FontsDir)
+
+func TestOverlaysDrawAndRedrawClocks(t *testing.T) {
+	var frame display.Frame
+	layers := rgbmatrix.NewLayers(rgbmatrix.NewObservable(rgbmatrix.NewMemory(32, 8), func(f display.Frame) { frame = f }))
+	overlays := NewOverlays(layers, t.TempDir(), repositoryFonts)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	overlays.now = func() time.Time { return now }
+
+	replaced, err := overlays.Set(Overlay{ID: "clock", Type: OverlayClock, X: 1, Z: 2, Params: map[string]string{"color": "ff0000"}})
+	if err != nil || replaced {
+		t.Fatalf("clock was not added: replaced=%t err=%v", replaced, err)
+	}
+	if !bytes.Contains(frame.Pixels, []byte{255, 0, 0}) {
+		t.Fatal("clock overlay was not drawn")
+	}
+	if _, err := overlays.Set(Overlay{ID: "live", Type: OverlayText, Content: "LIVE", X: 20, Y: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if list := overlays.List(); len(list) != 2 || list[0].ID != "live" || list[1].Content != DefaultClockFormat {
+		t.Fatalf("unexpected overlays: %#v", list)
+	}
+
+	drawn := bytes.Clone(frame.Pixels)
+	overlays.tick()
+	if !bytes.Equal(frame.Pixels, drawn) {
+		t.Fatal("clock was redrawn although its text did not change")
+	}
+	now = now.Add(time.Minute)
+	overlays.tick()
+	if bytes.Equal(frame.Pixels, drawn) {
+		t.Fatal("clock was not redrawn after its text changed")
+	}
+
+	if deleted, err := overlays.Delete("clock"); err != nil || !deleted {
+		t.Fatalf("clock was not deleted: %v", err)
+	}
+	if bytes.Contains(frame.Pixels, []byte{255, 0, 0}) {
+		t.Fatal("deleted overlay is still drawn")
+	}
+	if deleted, _ := overlays.Delete("clock"); deleted {
+		t.Fatal("missing overlay was reported as deleted")
+	}
+}
+
+func TestOverlaysRejectInvalidOverlays(t *testing.T) {
+	overlays := NewOverlays(rgbmatrix.NewLayers(rgbmatrix.NewMemory(32, 8)), t.TempDir(), t.TempDir())
+	tests := []struct {
+		name    string
+		overlay Overlay
+		want    error
+	}{
+		{name: "invalid id", overlay: Overlay{ID: "../live", Type: OverlayText, Content: "LIVE"}, want: ErrInvalidOverlay},
+		{name: "outside", overlay: Overlay{ID: "live", Type: OverlayText, Content: "LIVE", X: 32}, want: ErrInvalidOverlay},
+		{name: "empty text", overlay: Overlay{ID: "live", Type: OverlayText}, want: ErrInvalidOverlay},
+		{name: "unknown parameter", overlay: Overlay{ID: "live", Type: OverlayText, Content: "LIVE", Params: map[string]string{"mode": "marquee"}}, want: ErrInvalidParameter},
+		{name: "invalid color", overlay: Overlay{ID: "live", Type: OverlayText, Content: "LIVE", Params: map[string]string{"color": "red"}}, want: ErrInvalidParameter},
+		{name: "image parameters", overlay: Overlay{ID: "logo", Type: OverlayImage, Content: "logo", Params: map[string]string{"color": "ff0000"}}, want: ErrInvalidParameter},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := overlays.Set(test.overlay); !errors.Is(err, test.want) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package renderers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+const (
+	// DefaultFPS is the frame rate of paced renderers without one.
+	DefaultFPS = rgbmatrix.DefaultFPS
+	// MaxFPS is the highest frame rate of paced renderers.
+	MaxFPS = rgbmatrix.MaxFPS
+)
+
+// FrameFunc draws the frame at time t since the renderer started.
+type FrameFunc func(t time.Duration) error
+
+// FrameStats describe how well a Pacer kept its frame rate.
+type FrameStats struct {
+	Frames  uint64
+	Dropped uint64
+	// FrameTime is the average time a frame took to draw, and
+	// MaxFrameTime the longest.
+	FrameTime    time.Duration
+	MaxFrameTime time.Duration
+}
+
+// Pacer calls a frame function at a target frame rate. Frames that cannot
+// start on time because the previous frame took too long are dropped, so
+// slow renderers fall behind in frame rate instead of in time.
+type Pacer struct {
+	interval time.Duration
+	now      func() time.Time
+	// wait blocks until d passed and reports false if ctx is done first.
+	wait func(ctx context.Context, d time.Duration) bool
+
+	mu    sync.Mutex
+	stats FrameStats
+	total time.Duration
+}
+
+// NewPacer returns a pacer for the given frame rate. Rates outside of 1 to
+// MaxFPS use DefaultFPS.
+func NewPacer(fps int) *Pacer {
+	if fps < 1 || fps > MaxFPS {
+		fps = DefaultFPS
+	}
+	return &Pacer{interval: time.Second / time.Duration(fps), now: time.Now, wait: waitFor}
+}
+
+// Run calls frame until ctx is done or frame fails.
+func (p *Pacer) Run(ctx context.Context, frame FrameFunc) error {
+	start := p.now()
+	next := start
+	for ctx.Err() == nil {
+		began := p.now()
+		if err := frame(began.Sub(start)); err != nil {
+			return err
+		}
+		finished := p.now()
+
+		next = next.Add(p.interval)
+		var dropped time.Duration
+		if late := finished.Sub(next); late > 0 {
+			dropped = late/p.interval + 1
+			next = next.Add(dropped * p.interval)
+		}
+		p.record(finished.Sub(began), uint64(dropped))
+
+		if !p.wait(ctx, next.Sub(p.now())) {
+			break
+		}
+	}
+	if stats := p.Stats(); stats.Dropped > 0 {
+		log.Printf("renderer dropped frames: frames=%d dropped=%d frame_time=%s max_frame_time=%s target=%s",
+			stats.Frames, stats.Dropped, stats.FrameTime, stats.MaxFrameTime, p.interval)
+	}
+	return nil
+}
+
+func (p *Pacer) record(frameTime time.Duration, dropped uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stats.Frames++
+	p.stats.Dropped += dropped
+	p.total += frameTime
+	p.stats.FrameTime = p.total / time.Duration(p.stats.Frames)
+	p.stats.MaxFrameTime = max(p.stats.MaxFrameTime, frameTime)
+}
+
+// Stats returns the frame statistics so far.
+func (p *Pacer) Stats() FrameStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats
+}
+
+func waitFor(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// paced is embedded by renderers that draw their frames with a Pacer.
+type paced struct {
+	fps int
+}
+
+func (p *paced) setFPS(fps int) {
+	p.fps = fps
+}
+
+// run draws frames at the frame rate of the display until ctx is done.
+func (p *paced) run(ctx context.Context, frame FrameFunc) error {
+	return NewPacer(p.fps).Run(ctx, frame)
+}
+
+// fpsSetter is implemented by renderers that embed paced.
+type fpsSetter interface {
+	setFPS(int)
+}
+
+// setFPS sets the frame rate of a paced renderer.
+func setFPS(renderer Renderer, fps int) {
+	if setter, ok := renderer.(fpsSetter); ok {
+		setter.setFPS(fps)
+	}
+}
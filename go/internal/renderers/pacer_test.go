@@ -0,0 +1,100 @@
+package renderers
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+// fakeClock lets a Pacer run on simulated time.
+func fakeClock(p *Pacer) *time.Time {
+	now := time.Unix(0, 0)
+	p.now = func() time.Time { return now }
+	p.wait = func(ctx context.Context, d time.Duration) bool {
+		if d > 0 {
+			now = now.Add(d)
+		}
+		return ctx.Err() == nil
+	}
+	return &now
+}
+
+func TestPacerDropsLateFrames(t *testing.T) {
+	p := NewPacer(10)
+	now := fakeClock(p)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	durations := []time.Duration{10 * time.Millisecond, 250 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond}
+	var times []time.Duration
+	err := p.Run(ctx, func(t time.Duration) error {
+		times = append(times, t)
+		*now = now.Add(durations[len(times)-1])
+		if len(times) == len(durations) {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []time.Duration{0, 100 * time.Millisecond, 400 * time.Millisecond, 500 * time.Millisecond}
+	if len(times) != len(want) {
+		t.Fatalf("frames at %v, want %v", times, want)
+	}
+	for i := range want {
+		if times[i] != want[i] {
+			t.Fatalf("frames at %v, want %v", times, want)
+		}
+	}
+	stats := p.Stats()
+	if stats != (FrameStats{Frames: 4, Dropped: 2, FrameTime: 70 * time.Millisecond, MaxFrameTime: 250 * time.Millisecond}) {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestPacerStopsOnFrameError(t *testing.T) {
+	p := NewPacer(0)
+	fakeClock(p)
+	if p.interval != time.Second/DefaultFPS {
+		t.Fatalf("interval = %v, want the default frame rate", p.interval)
+	}
+	failed := context.DeadlineExceeded
+	if err := p.Run(context.Background(), func(time.Duration) error { return failed }); err != failed {
+		t.Fatalf("Run returned %v, want %v", err, failed)
+	}
+}
+
+func TestAnimatedStepsFollowTime(t *testing.T) {
+	var a animated
+	a.setAnimationParams(AnimationParams{Speed: 2})
+	if steps := a.steps(0, 100*time.Millisecond); steps != 0 {
+		t.Fatalf("steps at start = %d", steps)
+	}
+	if steps := a.steps(120*time.Millisecond, 100*time.Millisecond); steps != 2 {
+		t.Fatalf("steps after 120ms at double speed = %d, want 2", steps)
+	}
+	if steps := a.steps(140*time.Millisecond, 100*time.Millisecond); steps != 0 {
+		t.Fatalf("steps were taken twice: %d", steps)
+	}
+	if steps := a.steps(time.Hour, 100*time.Millisecond); steps != maxCatchUpSteps {
+		t.Fatalf("steps after a stall = %d, want %d", steps, maxCatchUpSteps)
+	}
+
+	// A new run starts the animation clock over.
+	a.run(canceled(), func(time.Duration) error { return nil })
+	if steps := a.steps(120*time.Millisecond, 100*time.Millisecond); steps != 2 {
+		t.Fatalf("steps after a restart = %d, want 2", steps)
+	}
+	if step := a.advance(time.Second / 10); math.Abs(step-6) > 1e-6 {
+		t.Fatalf("advance over three frames at double speed = %v, want 6", step)
+	}
+}
+
+func canceled() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
@@ -10,15 +10,15 @@ import (
 )
 
 const (
-	pacmanFrameInterval = time.Second / 30
-	pacmanRoundSeconds  = 18.0
-	pacmanRunSeconds    = 14.0
-	pacmanPowerSecond   = 1.4
-	pacmanGhostSpeed    = 0.018
+	pacmanRoundSeconds = 18.0
+	pacmanRunSeconds   = 14.0
+	pacmanPowerSecond  = 1.4
+	pacmanGhostSpeed   = 0.018
 )
 
 type PacmanRenderer struct {
 	screen *rgbmatrix.Screen
+	animated
 }
 
 type pacmanPoint struct {
@@ -53,21 +53,10 @@ func Pacman(screen *rgbmatrix.Screen) *PacmanRenderer {
 }
 
 func (r *PacmanRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	start := time.Now()
-	ticker := time.NewTicker(pacmanFrameInterval)
-	defer ticker.Stop()
-
-	for {
-		if err := r.drawFrame(ctx, dc, time.Since(start).Seconds()); err != nil {
-			return err
-		}
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-		}
-	}
+	dc := gg.NewContextForRGBA(r.screen.Frame())
+	return r.run(ctx, func(elapsed time.Duration) error {
+		return r.drawFrame(ctx, dc, r.seconds(elapsed))
+	})
 }
 
 func (r *PacmanRenderer) drawFrame(ctx context.Context, dc *gg.Context, elapsed float64) error {
@@ -91,7 +80,7 @@ func (r *PacmanRenderer) drawFrame(ctx context.Context, dc *gg.Context, elapsed
 		r.drawLevelComplete(dc, point, width, height, scale, roundSecond-pacmanRunSeconds)
 	}
 	r.drawCaptureScores(dc, scale, roundSecond, width, height)
-	return r.screen.ShowImage(ctx, dc.Image())
+	return r.screen.Present()
 }
 
 func (r *PacmanRenderer) drawMaze(dc *gg.Context, width, height, scale float64) {
@@ -205,7 +194,8 @@ func pacmanGhostCaptureSecond(ghost pacmanGhost) float64 {
 	return (ghost.start - ghost.release*pacmanGhostSpeed) / (pacmanSpeed - pacmanGhostSpeed)
 }
 
-func (r *PacmanRenderer) drawLevelGhost(dc *gg.Context, ghost pacmanGhost, _ int, scale, elapsed, roundSecond, width, height float64) {
+func (r *PacmanRenderer) drawLevelGhost(dc *gg.Context, ghost pacmanGhost, index int, scale, elapsed, roundSecond, width, height float64) {
+	ghost.red, ghost.green, ghost.blue = r.rgbAt(float64(index)/float64(len(pacmanGhosts)), ghost.red, ghost.green, ghost.blue)
 	house := pacmanPoint{x: width / 2, y: height / 2, direction: 0}
 	startPoint := pacmanPath(ghost.start, width, height)
 	if roundSecond < ghost.release {
@@ -299,7 +289,7 @@ func (r *PacmanRenderer) drawLevelComplete(dc *gg.Context, point pacmanPoint, wi
 	for ring := 1; ring <= 3; ring++ {
 		radius := math.Mod(elapsed*18+float64(ring)*10, math.Min(width, height)*0.48)
 		hue := math.Mod(elapsed*0.18+float64(ring)*0.22, 1)
-		red, green, blue := hsvToRGB(hue, 0.9, 1)
+		red, green, blue := r.hsv(hue, 0.9, 1)
 		dc.SetRGBA(red, green, blue, 0.55*(1-radius/(math.Min(width, height)*0.5)))
 		dc.SetLineWidth(math.Max(0.7, scale))
 		dc.DrawCircle(centerX, centerY, radius)
@@ -312,7 +302,7 @@ func (r *PacmanRenderer) drawLevelComplete(dc *gg.Context, point pacmanPoint, wi
 		x := centerX + math.Cos(angle)*distance
 		y := centerY + math.Sin(angle)*distance*0.55
 		hue := math.Mod(float64(spark)/18+elapsed*0.12, 1)
-		red, green, blue := hsvToRGB(hue, 0.9, 1)
+		red, green, blue := r.hsv(hue, 0.9, 1)
 		dc.SetRGB(red, green, blue)
 		dc.DrawCircle(x, y, math.Max(0.5, scale*0.7))
 		dc.Fill()
@@ -17,7 +17,7 @@ func TestPacmanDrawsAFrame(t *testing.T) {
 	}
 	screen := rgbmatrix.NewScreen(matrix)
 	renderer := Pacman(screen)
-	dc := gg.NewContextForImage(screen.Canvas)
+	dc := gg.NewContextForRGBA(screen.Frame())
 
 	if err := renderer.drawFrame(context.Background(), dc, 2.5); err != nil {
 		t.Fatal(err)
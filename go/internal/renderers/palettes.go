@@ -0,0 +1,105 @@
+package renderers
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"log"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/palette"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+// DefaultPalette keeps the colors a renderer was designed with.
+const DefaultPalette = "default"
+
+// PalettesDir contains the .gpl and .ase palette files that renderers can use
+// besides the built-in palettes.
+const PalettesDir = "assets/palettes"
+
+// PaletteNames returns the palettes renderers can use, starting with
+// DefaultPalette.
+func PaletteNames() []string {
+	names, err := palette.Names(PalettesDir)
+	if err != nil {
+		log.Printf("list palettes: %v", err)
+		names = palette.BuiltinNames()
+	}
+	return append([]string{DefaultPalette}, names...)
+}
+
+// loadPalette returns the named palette of dir, such as PalettesDir, or nil
+// for DefaultPalette.
+func loadPalette(dir, name string) (*palette.Palette, error) {
+	if name == "" || name == DefaultPalette {
+		return nil, nil
+	}
+	p, err := palette.Find(dir, name)
+	if errors.Is(err, palette.ErrNotFound) {
+		return nil, fmt.Errorf("%w: unknown palette %q", ErrInvalidParameter, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAsset, err)
+	}
+	return &p, nil
+}
+
+// displayPalette returns the display palette of config, or DefaultPalette
+// if it cannot be loaded from PalettesDir.
+func displayPalette(config rgbmatrix.Config) string {
+	name := config.Display.Palette
+	if _, err := loadPalette(PalettesDir, name); err != nil {
+		log.Printf("display palette is ignored: %v", err)
+		return DefaultPalette
+	}
+	return name
+}
+
+// paletted is embedded by animations and dashboards to sample their colors
+// from the active palette. Without a palette the colors are left as
+// designed. Images, GIFs, text and overlays keep their own colors: images
+// and GIFs show their pixels, and text and overlays are colored by their
+// color parameter.
+type paletted struct {
+	palette *palette.Palette
+}
+
+func (p *paletted) setPalette(active *palette.Palette) {
+	p.palette = active
+}
+
+// hsv returns the color of a hue like hsvToRGB, or the palette color at the
+// hue if a palette is active.
+func (p *paletted) hsv(h, s, v float64) (r, g, b float64) {
+	if p.palette == nil {
+		return hsvToRGB(h, s, v)
+	}
+	c := p.palette.At(h)
+	channel := func(value uint8) float64 {
+		return v * (1 - s + s*float64(value)/255)
+	}
+	return channel(c.R), channel(c.G), channel(c.B)
+}
+
+// colorAt returns the palette color at position t, or fallback if no
+// palette is active.
+func (p *paletted) colorAt(t float64, fallback color.RGBA) color.RGBA {
+	if p.palette == nil {
+		return fallback
+	}
+	return p.palette.At(t)
+}
+
+// rgbAt is colorAt for channels from 0 to 1.
+func (p *paletted) rgbAt(t, red, green, blue float64) (r, g, b float64) {
+	if p.palette == nil {
+		return red, green, blue
+	}
+	c := p.palette.At(t)
+	return float64(c.R) / 255, float64(c.G) / 255, float64(c.B) / 255
+}
+
+// paletteSetter is implemented by renderers that embed paletted.
+type paletteSetter interface {
+	setPalette(*palette.Palette)
+}
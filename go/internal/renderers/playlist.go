@@ -0,0 +1,261 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// playlistPrepareTimeout bounds how long a playlist waits for one entry's
+// renderer to start before skipping it.
+const playlistPrepareTimeout = 5 * time.Second
+
+var ErrInvalidPlaylist = errors.New("invalid playlist")
+
+// PlaylistEntry is one step of a playlist: the content to show and how long
+// it stays on the display before the next entry replaces it.
+type PlaylistEntry struct {
+	Type     ScreenType
+	Name     string
+	Duration time.Duration
+}
+
+// Playlist is a named sequence of content. Shuffled playlists are reordered
+// on every pass; playlists without Repeat stop after one pass and leave the
+// last entry on the display.
+type Playlist struct {
+	Name    string
+	Entries []PlaylistEntry
+	Shuffle bool
+	Repeat  bool
+}
+
+func (p Playlist) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPlaylist)
+	}
+	if len(p.Entries) == 0 {
+		return fmt.Errorf("%w: %q has no entries", ErrInvalidPlaylist, p.Name)
+	}
+	for index, entry := range p.Entries {
+		switch entry.Type {
+		case TypeImage, TypeGIF, TypeDashboard, TypeAnimation:
+		default:
+			return fmt.Errorf("%w: entry %d has unsupported type %s", ErrInvalidPlaylist, index, entry.Type)
+		}
+		if entry.Name == "" {
+			return fmt.Errorf("%w: entry %d has no name", ErrInvalidPlaylist, index)
+		}
+		if entry.Duration <= 0 {
+			return fmt.Errorf("%w: entry %d has no duration", ErrInvalidPlaylist, index)
+		}
+	}
+	return nil
+}
+
+// Playlists stores named playlists and runs at most one of them at a time by
+// sending its entries to the update loop as regular commands.
+type Playlists struct {
+	ctx      context.Context
+	commands chan<- Command
+
+	// control serializes Start and Stop so that only one run is active.
+	control sync.Mutex
+
+	mu        sync.Mutex
+	playlists map[string]Playlist
+	active    *playlistRun
+	// file saves the running playlist, if it is not nil.
+	file *StateFile
+}
+
+type playlistRun struct {
+	name   string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewPlaylists returns an empty playlist store. Running playlists stop when
+// ctx is cancelled.
+func NewPlaylists(ctx context.Context, commands chan<- Command) *Playlists {
+	return &Playlists{
+		ctx:       ctx,
+		commands:  commands,
+		playlists: make(map[string]Playlist),
+	}
+}
+
+// SaveTo saves the running playlist to file whenever it starts or stops,
+// so that it can be restored after a restart.
+func (p *Playlists) SaveTo(file *StateFile) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.file = file
+}
+
+// Save creates or replaces a playlist. A running playlist keeps its previous
+// entries until it is started again.
+func (p *Playlists) Save(playlist Playlist) error {
+	if err := playlist.validate(); err != nil {
+		return err
+	}
+	playlist.Entries = append([]PlaylistEntry(nil), playlist.Entries...)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.playlists[playlist.Name] = playlist
+	return nil
+}
+
+// List returns all playlists sorted by name.
+func (p *Playlists) List() []Playlist {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	items := make([]Playlist, 0, len(p.playlists))
+	for _, playlist := range p.playlists {
+		items = append(items, playlist)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items
+}
+
+// Active returns the name of the running playlist, if any.
+func (p *Playlists) Active() (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.active == nil {
+		return "", false
+	}
+	return p.active.name, true
+}
+
+// Start stops the running playlist and starts the named one.
+func (p *Playlists) Start(name string) error {
+	p.control.Lock()
+	defer p.control.Unlock()
+
+	p.mu.Lock()
+	playlist, ok := p.playlists[name]
+	p.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: playlist %q", ErrUnknownContent, name)
+	}
+
+	p.stop()
+
+	ctx, cancel := context.WithCancel(p.ctx)
+	run := &playlistRun{name: name, cancel: cancel, done: make(chan struct{})}
+	p.mu.Lock()
+	p.active = run
+	file := p.file
+	p.mu.Unlock()
+	file.savePlaylist(&playlist)
+
+	log.Printf("playlist started: playlist=%q entries=%d shuffle=%t repeat=%t",
+		name, len(playlist.Entries), playlist.Shuffle, playlist.Repeat)
+	go func() {
+		defer close(run.done)
+		defer p.finish(run)
+		p.run(ctx, playlist)
+	}()
+	return nil
+}
+
+// Stop stops the running playlist and waits until it no longer sends
+// commands. It reports whether a playlist was running.
+func (p *Playlists) Stop() bool {
+	p.control.Lock()
+	defer p.control.Unlock()
+	return p.stop()
+}
+
+func (p *Playlists) stop() bool {
+	p.mu.Lock()
+	run := p.active
+	p.active = nil
+	file := p.file
+	p.mu.Unlock()
+	if run == nil {
+		return false
+	}
+	file.savePlaylist(nil)
+
+	run.cancel()
+	<-run.done
+	log.Printf("playlist stopped: playlist=%q", run.name)
+	return true
+}
+
+func (p *Playlists) finish(run *playlistRun) {
+	run.cancel()
+	p.mu.Lock()
+	finished := p.active == run
+	if finished {
+		p.active = nil
+	}
+	file := p.file
+	p.mu.Unlock()
+	if !finished {
+		return
+	}
+	// A playlist that stops because the server shuts down stays saved, so
+	// that it runs again after the restart.
+	if p.ctx.Err() != nil {
+		log.Printf("playlist interrupted: playlist=%q", run.name)
+		return
+	}
+	log.Printf("playlist finished: playlist=%q", run.name)
+	file.savePlaylist(nil)
+}
+
+func (p *Playlists) run(ctx context.Context, playlist Playlist) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for {
+		order := rng.Perm(len(playlist.Entries))
+		if !playlist.Shuffle {
+			sort.Ints(order)
+		}
+
+		started := 0
+		for _, index := range order {
+			entry := playlist.Entries[index]
+			if err := p.show(ctx, entry); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf("playlist entry skipped: playlist=%q type=%s name=%q error=%v",
+					playlist.Name, entry.Type, entry.Name, err)
+				continue
+			}
+			started++
+
+			timer := time.NewTimer(entry.Duration)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+
+		if !playlist.Repeat {
+			return
+		}
+		if started == 0 {
+			log.Printf("playlist stopped: playlist=%q error=no entry could be started", playlist.Name)
+			return
+		}
+	}
+}
+
+func (p *Playlists) show(ctx context.Context, entry PlaylistEntry) error {
+	ctx, cancel := context.WithTimeout(ctx, playlistPrepareTimeout)
+	defer cancel()
+	return send(ctx, p.commands, Command{Type: entry.Type, Name: entry.Name})
+}
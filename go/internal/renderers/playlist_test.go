@@ -0,0 +1,110 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlaylistSkipsEntriesThatFailToPrepare(t *testing.T) {
+	commands := make(chan Command)
+	playlists := NewPlaylists(context.Background(), commands)
+	err := playlists.Save(Playlist{Name: "evening", Entries: []PlaylistEntry{
+		{Type: TypeDashboard, Name: "clock", Duration: time.Millisecond},
+		{Type: TypeImage, Name: "broken", Duration: time.Hour},
+		{Type: TypeAnimation, Name: "plasma", Duration: time.Millisecond},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := playlists.Start("evening"); err != nil {
+		t.Fatal(err)
+	}
+
+	var shown []string
+	for range 3 {
+		command := receiveCommand(t, commands)
+		shown = append(shown, command.Type.String()+"/"+command.Name)
+		if command.Name == "broken" {
+			command.Result <- ErrInvalidAsset
+		} else {
+			command.Result <- nil
+		}
+	}
+
+	want := []string{"dashboard/clock", "image/broken", "animation/plasma"}
+	if !reflect.DeepEqual(shown, want) {
+		t.Fatalf("unexpected entries: got %v, want %v", shown, want)
+	}
+	waitForPlaylistToFinish(t, playlists)
+}
+
+func TestPlaylistRepeatsUntilStopped(t *testing.T) {
+	commands := make(chan Command)
+	playlists := NewPlaylists(context.Background(), commands)
+	err := playlists.Save(Playlist{Name: "loop", Repeat: true, Entries: []PlaylistEntry{
+		{Type: TypeAnimation, Name: "plasma", Duration: time.Millisecond},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := playlists.Start("loop"); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		command := receiveCommand(t, commands)
+		command.Result <- nil
+	}
+	if name, ok := playlists.Active(); !ok || name != "loop" {
+		t.Fatalf("unexpected active playlist: %q %t", name, ok)
+	}
+	if !playlists.Stop() {
+		t.Fatal("stop did not report the running playlist")
+	}
+	if _, ok := playlists.Active(); ok {
+		t.Fatal("playlist is still active after stop")
+	}
+}
+
+func TestPlaylistValidation(t *testing.T) {
+	playlists := NewPlaylists(context.Background(), make(chan Command))
+	tests := []Playlist{
+		{Name: "empty"},
+		{Name: "temporary", Entries: []PlaylistEntry{{Type: TypeGIFOnce, Name: "party", Duration: time.Second}}},
+		{Name: "no-duration", Entries: []PlaylistEntry{{Type: TypeImage, Name: "logo"}}},
+	}
+	for _, playlist := range tests {
+		if err := playlists.Save(playlist); !errors.Is(err, ErrInvalidPlaylist) {
+			t.Errorf("%s: unexpected error: %v", playlist.Name, err)
+		}
+	}
+	if err := playlists.Start("missing"); !errors.Is(err, ErrUnknownContent) {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+}
+
+func receiveCommand(t *testing.T, commands chan Command) Command {
+	t.Helper()
+	select {
+	case command := <-commands:
+		return command
+	case <-time.After(time.Second):
+		t.Fatal("playlist did not send a command")
+		return Command{}
+	}
+}
+
+func waitForPlaylistToFinish(t *testing.T, playlists *Playlists) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, ok := playlists.Active(); !ok {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("playlist did not finish")
+}
@@ -3,8 +3,8 @@ package renderers
 import (
 	"context"
 	"fmt"
-	"log"
 
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/palette"
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers/animation"
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/renderers/dashboard"
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
@@ -15,19 +15,27 @@ type preparedRenderer struct {
 	async    bool
 }
 
-func (p preparedRenderer) start(ctx context.Context, callbacks ...AfterRenderFunc) error {
+// start runs the renderer. Errors of synchronous renderers are returned,
+// while asynchronous renderers run in a goroutine that calls exited with
+// their error once they returned. The returned channel is closed once the
+// renderer returned, before exited is called.
+func (p preparedRenderer) start(ctx context.Context, exited func(error)) (<-chan struct{}, error) {
+	done := make(chan struct{})
 	if !p.async {
-		return p.renderer.Render(ctx, callbacks...)
+		defer close(done)
+		return done, p.render(ctx)
 	}
 	go func() {
-		if err := p.renderer.Render(ctx, callbacks...); err != nil {
-			log.Printf("renderer stopped with error: %v", err)
-		}
+		err := p.render(ctx)
+		close(done)
+		exited(err)
 	}()
-	return nil
+	return done, nil
 }
 
-func prepare(ctx context.Context, cmd Command, screen *rgbmatrix.Screen) (preparedRenderer, error) {
+// prepare prepares the renderer of a command. Dashboards take their settings
+// from config.
+func prepare(ctx context.Context, cmd Command, screen *rgbmatrix.Screen, config rgbmatrix.Config) (preparedRenderer, error) {
 	switch cmd.Type {
 	case TypePlayground:
 		return preparedRenderer{renderer: MarbleShader(screen), async: true}, nil
@@ -36,42 +44,110 @@ func prepare(ctx context.Context, cmd Command, screen *rgbmatrix.Screen) (prepar
 		return preparedRenderer{renderer: renderer}, err
 	case TypeGIF:
 		renderer, err := GIFLoop(screen, cmd.Name)
-		return preparedRenderer{renderer: renderer}, err
+		return preparedRenderer{renderer: renderer, async: true}, err
 	case TypeGIFOnce:
 		renderer, err := GIFOnce(screen, cmd.Name)
-		return preparedRenderer{renderer: renderer}, err
+		return preparedRenderer{renderer: renderer, async: true}, err
 	case TypeDashboard:
-		return prepareDashboard(ctx, cmd.Name, screen)
+		return prepareDashboard(ctx, cmd.Name, cmd.Params, PalettesDir, screen, config)
 	case TypeAnimation:
-		return prepareAnimation(cmd.Name, screen)
+		return prepareAnimation(cmd.Name, cmd.Params, screen)
+	case TypeText:
+		return prepareText(cmd.Name, cmd.Params, screen)
+	case TypeBlank:
+		return preparedRenderer{renderer: Blank(screen)}, nil
 	default:
 		return preparedRenderer{}, fmt.Errorf("%w: renderer type %d", ErrUnknownContent, cmd.Type)
 	}
 }
 
-func prepareDashboard(ctx context.Context, name string, screen *rgbmatrix.Screen) (preparedRenderer, error) {
+// withPalette sets the palette of animations and dashboards that do not
+// choose one.
+func withPalette(cmd Command, name string) Command {
+	if name == "" || name == DefaultPalette || (cmd.Type != TypeAnimation && cmd.Type != TypeDashboard) {
+		return cmd
+	}
+	if _, ok := cmd.Params["palette"]; ok {
+		return cmd
+	}
+	params := make(map[string]string, len(cmd.Params)+1)
+	for key, value := range cmd.Params {
+		params[key] = value
+	}
+	params["palette"] = name
+	cmd.Params = params
+	return cmd
+}
+
+// ValidateDashboardParams checks the parameters of a dashboard. Dashboards
+// only take a palette.
+func ValidateDashboardParams(params map[string]string) error {
+	_, err := parseDashboardParams(PalettesDir, params)
+	return err
+}
+
+func parseDashboardParams(palettesDir string, params map[string]string) (*palette.Palette, error) {
+	for key := range params {
+		if key != "palette" {
+			return nil, fmt.Errorf("%w: dashboards have no parameter %q", ErrInvalidParameter, key)
+		}
+	}
+	return loadPalette(palettesDir, params["palette"])
+}
+
+// prepareDashboard prepares a dashboard with its palette from palettesDir.
+func prepareDashboard(ctx context.Context, name string, params map[string]string, palettesDir string, screen *rgbmatrix.Screen, config rgbmatrix.Config) (preparedRenderer, error) {
 	value, err := dashboard.DashboardString(name)
 	if err != nil {
 		return preparedRenderer{}, fmt.Errorf("%w: dashboard %q", ErrUnknownContent, name)
 	}
+	active, err := parseDashboardParams(palettesDir, params)
+	if err != nil {
+		return preparedRenderer{}, err
+	}
 
 	var renderer Renderer
 	switch value {
 	case dashboard.Clock:
 		renderer = Clock(screen)
 	case dashboard.Autodarts:
-		renderer, err = UserCountDashboard(screen)
+		renderer, err = UserCountDashboard(screen, config)
 	case dashboard.Shopify:
-		renderer, err = ShopifyDashboard(ctx, screen)
+		renderer, err = ShopifyDashboard(ctx, screen, config)
+	}
+	if err != nil {
+		return preparedRenderer{}, err
+	}
+	if setter, ok := renderer.(paletteSetter); ok {
+		setter.setPalette(active)
+	} else if active != nil {
+		return preparedRenderer{}, fmt.Errorf("%w: dashboard %q has no palette", ErrInvalidParameter, name)
+	}
+	return preparedRenderer{renderer: renderer, async: true}, nil
+}
+
+func prepareText(message string, params map[string]string, screen *rgbmatrix.Screen) (preparedRenderer, error) {
+	options, err := ParseTextOptions(params)
+	if err != nil {
+		return preparedRenderer{}, err
 	}
-	return preparedRenderer{renderer: renderer, async: true}, err
+	renderer, err := Text(screen, message, options)
+	return preparedRenderer{renderer: renderer, async: options.Mode == TextMarquee}, err
 }
 
-func prepareAnimation(name string, screen *rgbmatrix.Screen) (preparedRenderer, error) {
+func prepareAnimation(name string, params map[string]string, screen *rgbmatrix.Screen) (preparedRenderer, error) {
 	value, err := animation.AnimationString(name)
 	if err != nil {
 		return preparedRenderer{}, fmt.Errorf("%w: animation %q", ErrUnknownContent, name)
 	}
+	parsed, err := ParseAnimationParams(name, params)
+	if err != nil {
+		return preparedRenderer{}, err
+	}
+	active, err := loadPalette(PalettesDir, parsed.Palette)
+	if err != nil {
+		return preparedRenderer{}, err
+	}
 
 	factories := map[animation.Animation]func(*rgbmatrix.Screen) Renderer{
 		animation.Aurora:             func(s *rgbmatrix.Screen) Renderer { return Aurora(s) },
@@ -120,5 +196,12 @@ func prepareAnimation(name string, screen *rgbmatrix.Screen) (preparedRenderer,
 	if !ok {
 		return preparedRenderer{}, fmt.Errorf("%w: animation %q", ErrUnknownContent, name)
 	}
-	return preparedRenderer{renderer: factory(screen), async: true}, nil
+	renderer := factory(screen)
+	if animated, ok := renderer.(interface{ setAnimationParams(AnimationParams) }); ok {
+		animated.setAnimationParams(parsed)
+	}
+	if setter, ok := renderer.(paletteSetter); ok {
+		setter.setPalette(active)
+	}
+	return preparedRenderer{renderer: renderer, async: true}, nil
 }
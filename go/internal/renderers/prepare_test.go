@@ -3,6 +3,7 @@ package renderers
 import (
 	"context"
 	"errors"
+	"image"
 	"image/color"
 	"os"
 	"path/filepath"
@@ -16,7 +17,7 @@ func TestEveryAnimationCanBePrepared(t *testing.T) {
 	screen := rgbmatrix.NewScreen(newPrepareMatrix(16, 16))
 	for _, name := range animation.AnimationStrings() {
 		t.Run(name, func(t *testing.T) {
-			prepared, err := prepare(context.Background(), Command{Type: TypeAnimation, Name: name}, screen)
+			prepared, err := prepare(context.Background(), Command{Type: TypeAnimation, Name: name}, screen, rgbmatrix.Config{})
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -47,12 +48,58 @@ func TestPrepareRejectsCorruptImage(t *testing.T) {
 	t.Cleanup(func() { _ = os.Chdir(previous) })
 
 	screen := rgbmatrix.NewScreen(newPrepareMatrix(16, 16))
-	_, err = prepare(context.Background(), Command{Type: TypeImage, Name: "broken"}, screen)
+	_, err = prepare(context.Background(), Command{Type: TypeImage, Name: "broken"}, screen, rgbmatrix.Config{})
 	if !errors.Is(err, ErrInvalidAsset) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
 
+func TestPrepareDashboardPalette(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "brand.gpl"), []byte("GIMP Palette\n255 0 0\n0 0 255\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	screen := rgbmatrix.NewScreen(newPrepareMatrix(16, 16))
+	prepared, err := prepareDashboard(context.Background(), "clock", map[string]string{"palette": "brand"}, dir, screen, rgbmatrix.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clock := prepared.renderer.(*ClockRenderer); clock.palette == nil || clock.palette.Name != "brand" {
+		t.Fatalf("palette was not passed to the dashboard: %v", clock.palette)
+	}
+
+	for _, params := range []map[string]string{{"palette": "mud"}, {"speed": "2"}} {
+		_, err := prepareDashboard(context.Background(), "clock", params, dir, screen, rgbmatrix.Config{})
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("%v: unexpected error %v", params, err)
+		}
+	}
+}
+
+func TestWithPalette(t *testing.T) {
+	plasma := Command{Type: TypeAnimation, Name: "plasma", Params: map[string]string{"speed": "2"}}
+	if got := withPalette(plasma, "ocean"); got.Params["palette"] != "ocean" || got.Params["speed"] != "2" {
+		t.Fatalf("global palette was not set: %v", got.Params)
+	}
+	if _, ok := plasma.Params["palette"]; ok {
+		t.Fatal("the params of the command were modified")
+	}
+
+	chosen := Command{Type: TypeDashboard, Name: "clock", Params: map[string]string{"palette": "fire"}}
+	if got := withPalette(chosen, "ocean"); got.Params["palette"] != "fire" {
+		t.Fatalf("palette of the command was replaced: %v", got.Params)
+	}
+	for _, cmd := range []Command{{Type: TypeImage, Name: "logo"}, {Type: TypeText, Name: "hello"}} {
+		if got := withPalette(cmd, "ocean"); got.Params != nil {
+			t.Errorf("%s got a palette: %v", cmd.Type, got.Params)
+		}
+	}
+	if got := withPalette(plasma, DefaultPalette); got.Params["palette"] != "" {
+		t.Fatalf("default palette was set: %v", got.Params)
+	}
+}
+
 type prepareMatrix struct {
 	width  int
 	height int
@@ -73,4 +120,15 @@ func (m *prepareMatrix) Apply(pixels []color.Color) error {
 	return nil
 }
 func (m *prepareMatrix) Render() error { return nil }
-func (m *prepareMatrix) Close() error  { return nil }
+func (m *prepareMatrix) Present(img *image.RGBA) error {
+	m.read(img)
+	return nil
+}
+func (m *prepareMatrix) Close() error { return nil }
+
+// read copies img into the drawing buffer.
+func (m *prepareMatrix) read(img *image.RGBA) {
+	for position := range m.pixels {
+		m.pixels[position] = img.At(position%m.width, position/m.width)
+	}
+}
@@ -0,0 +1,153 @@
+package renderers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+// Reload hands a reloaded config to the update loop.
+type Reload struct {
+	Config  rgbmatrix.Config
+	Changes rgbmatrix.ConfigChanges
+	// Result receives whether the shown renderer was restarted.
+	Result chan bool
+}
+
+// ReloadResult reports what a reload changed.
+type ReloadResult struct {
+	rgbmatrix.ConfigChanges
+	// Restarted is true if the shown renderer was restarted because its
+	// inputs changed.
+	Restarted bool
+}
+
+// Reloader reads the config file again and applies the changes that are
+// safe at runtime. It is safe for concurrent use.
+type Reloader struct {
+	path       string
+	brightness rgbmatrix.Dimmable
+	color      rgbmatrix.Correctable
+	reloads    chan Reload
+	load       func(path string) (rgbmatrix.Config, error)
+
+	mu      sync.Mutex
+	running rgbmatrix.Config
+}
+
+// NewReloader reloads the config file at path, which the server started
+// with as config. Brightness and color are changed on the given matrices,
+// which may be nil, while the update loop receives the rest from Reloads.
+func NewReloader(path string, config rgbmatrix.Config, brightness rgbmatrix.Dimmable, color rgbmatrix.Correctable) *Reloader {
+	return &Reloader{
+		path:       path,
+		brightness: brightness,
+		color:      color,
+		reloads:    make(chan Reload),
+		load:       rgbmatrix.LoadConfigFile,
+		running:    config,
+	}
+}
+
+// Reloads are received by the update loop, see LoopOptions.
+func (r *Reloader) Reloads() <-chan Reload {
+	return r.reloads
+}
+
+// Reload reads the config file and applies its changes. Nothing is applied
+// if the file is invalid. Changes that need a restart are only reported.
+func (r *Reloader) Reload(ctx context.Context) (ReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := r.load(r.path)
+	if err != nil {
+		return ReloadResult{}, err
+	}
+	running, changes := r.running.Reload(next)
+	result := ReloadResult{ConfigChanges: changes}
+
+	// Color correction and brightness are applied before the update loop
+	// takes the reload and rolled back if it fails, so that a reload either
+	// changes everything or nothing and is repeated in full by the next one.
+	if err := r.apply(running, changes); err != nil {
+		return ReloadResult{}, err
+	}
+	reload := Reload{Config: running, Changes: changes, Result: make(chan bool, 1)}
+	select {
+	case r.reloads <- reload:
+	case <-ctx.Done():
+		if err := r.apply(r.running, changes); err != nil {
+			log.Printf("config reload could not be rolled back: %v", err)
+		}
+		return ReloadResult{}, ctx.Err()
+	}
+	r.running = running
+
+	// The reload succeeded once the update loop took it. Whether the shown
+	// renderer restarted is only reported if it is known in time.
+	select {
+	case result.Restarted = <-reload.Result:
+	case <-ctx.Done():
+	}
+
+	log.Printf("config reloaded: applied=%s restart_required=%s restarted=%t",
+		strings.Join(changes.Applied, ","), strings.Join(changes.Restart, ","), result.Restarted)
+	return result, nil
+}
+
+// apply sets the color correction and the brightness of config on the
+// matrices, if they changed. The color correction is rolled back if the
+// brightness cannot be set.
+func (r *Reloader) apply(config rgbmatrix.Config, changes rgbmatrix.ConfigChanges) error {
+	colorChanged := r.color != nil && changes.Changed("color")
+	if colorChanged {
+		if err := r.color.SetColorCorrection(config.Color); err != nil {
+			return fmt.Errorf("apply color correction: %w", err)
+		}
+	}
+	if r.brightness != nil && changes.Changed("options.brightness") {
+		if err := r.brightness.SetBrightness(config.Options.Brightness); err != nil {
+			if colorChanged {
+				_ = r.color.SetColorCorrection(r.running.Color)
+			}
+			return fmt.Errorf("apply brightness: %w", err)
+		}
+	}
+	return nil
+}
+
+// reload takes over the settings of a reloaded config and restarts the
+// persistent renderer if it is shown and was prepared with changed keys.
+// Notifications keep running, and the persistent renderer picks up the
+// changes when it is restored.
+func (l *displayLoop) reload(reload Reload) {
+	l.options.Config = reload.Config
+	l.palette = displayPalette(reload.Config)
+
+	restarted := false
+	if l.active == nil && l.persistent != nil && preparedWith(l.persistent.Type, reload.Changes) {
+		log.Printf("renderer restarting after config reload: type=%s name=%q", l.persistent.Type, l.persistent.Name)
+		restarted = l.restore() == nil
+	}
+	reload.Result <- restarted
+}
+
+// preparedWith reports whether renderers of type t are prepared with a
+// changed config key.
+func preparedWith(t ScreenType, changes rgbmatrix.ConfigChanges) bool {
+	switch t {
+	case TypeDashboard, TypeLayout:
+		return changes.Changed("display.fps") || changes.Changed("display.palette") ||
+			changes.Changed("dashboards") || changes.Changed("shopify")
+	case TypeAnimation:
+		return changes.Changed("display.fps") || changes.Changed("display.palette")
+	case TypeText:
+		return changes.Changed("display.fps")
+	}
+	return false
+}
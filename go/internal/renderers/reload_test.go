@@ -0,0 +1,155 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestReloadRestartsRendererWithChangedInputs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var config rgbmatrix.Config
+	config.Display.FPS = 30
+	config.Color = rgbmatrix.DefaultColorCorrection
+	corrector, err := rgbmatrix.NewCorrector(rgbmatrix.NewMemory(16, 16), config.Color)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloader := NewReloader("config.toml", config, nil, corrector)
+	next := config
+	reloader.load = func(string) (rgbmatrix.Config, error) { return next, nil }
+
+	commands := make(chan Command)
+	state := NewState()
+	// Every renderer starts a second after the previous one.
+	clock := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	state.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	go updateLoop(ctx, commands, corrector, LoopOptions{State: state, Config: config, Reloads: reloader.Reloads()})
+	if err := sendCommand(commands, Command{Type: TypeAnimation, Name: "plasma"}); err != nil {
+		t.Fatal(err)
+	}
+
+	next.Dashboards.Font = "assets/fonts/6x10.bdf"
+	next.Color.Gamma = 2.2
+	started := state.Snapshot().StartedAt
+	result, err := reloader.Reload(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"dashboards.font", "color.gamma"}; !reflect.DeepEqual(result.Applied, want) {
+		t.Fatalf("unexpected applied keys: got %v, want %v", result.Applied, want)
+	}
+	if result.Restarted || !state.Snapshot().StartedAt.Equal(started) {
+		t.Fatalf("animation was restarted for a dashboard font")
+	}
+	if gamma := corrector.ColorCorrection().Gamma; gamma != 2.2 {
+		t.Fatalf("color correction was not applied: gamma %g", gamma)
+	}
+
+	next.Display.FPS = 60
+	next.Options.Rows = 64
+	result, err = reloader.Reload(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"options.rows"}; !reflect.DeepEqual(result.Restart, want) {
+		t.Fatalf("unexpected keys that need a restart: got %v, want %v", result.Restart, want)
+	}
+	if !result.Restarted || !state.Snapshot().StartedAt.After(started) {
+		t.Fatalf("animation was not restarted for a new frame rate")
+	}
+}
+
+func TestReloadThatTimedOutChangesNothing(t *testing.T) {
+	var config rgbmatrix.Config
+	config.Color = rgbmatrix.DefaultColorCorrection
+	corrector, err := rgbmatrix.NewCorrector(rgbmatrix.NewMemory(16, 16), config.Color)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloader := NewReloader("config.toml", config, nil, corrector)
+	next := config
+	next.Color.Gamma = 2.2
+	reloader.load = func(string) (rgbmatrix.Config, error) { return next, nil }
+
+	// No update loop takes the reload.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := reloader.Reload(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gamma := corrector.ColorCorrection().Gamma; gamma != config.Color.Gamma {
+		t.Fatalf("color correction of a reload that timed out was applied: gamma %g", gamma)
+	}
+
+	go func() {
+		reload := <-reloader.Reloads()
+		reload.Result <- false
+	}()
+	result, err := reloader.Reload(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"color.gamma"}; !reflect.DeepEqual(result.Applied, want) {
+		t.Fatalf("unexpected applied keys: got %v, want %v", result.Applied, want)
+	}
+	if gamma := corrector.ColorCorrection().Gamma; gamma != 2.2 {
+		t.Fatalf("color correction was not applied: gamma %g", gamma)
+	}
+}
+
+func TestReloadRollsBackColorCorrectionIfBrightnessFails(t *testing.T) {
+	var config rgbmatrix.Config
+	config.Color = rgbmatrix.DefaultColorCorrection
+	config.Options.Brightness = 100
+	corrector, err := rgbmatrix.NewCorrector(rgbmatrix.NewMemory(16, 16), config.Color)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloader := NewReloader("config.toml", config, failingDimmable{}, corrector)
+	next := config
+	next.Color.Gamma = 2.2
+	next.Options.Brightness = 40
+	reloader.load = func(string) (rgbmatrix.Config, error) { return next, nil }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	taken := make(chan Reload, 1)
+	go func() {
+		select {
+		case reload := <-reloader.Reloads():
+			taken <- reload
+			reload.Result <- false
+		case <-ctx.Done():
+		}
+	}()
+	if _, err := reloader.Reload(ctx); !errors.Is(err, errBrightness) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gamma := corrector.ColorCorrection().Gamma; gamma != config.Color.Gamma {
+		t.Fatalf("color correction of a failed reload was kept: gamma %g", gamma)
+	}
+	select {
+	case <-taken:
+		t.Fatal("update loop took a failed reload")
+	default:
+	}
+}
+
+var errBrightness = errors.New("brightness failed")
+
+// failingDimmable cannot change its brightness.
+type failingDimmable struct{}
+
+func (failingDimmable) Brightness() int { return 100 }
+
+func (failingDimmable) SetBrightness(int) error { return errBrightness }
@@ -7,6 +7,7 @@ import (
 
 var (
 	ErrInvalidAsset       = errors.New("invalid asset")
+	ErrInvalidParameter   = errors.New("invalid parameter")
 	ErrServiceUnavailable = errors.New("service unavailable")
 	ErrUnknownContent     = errors.New("unknown content")
 )
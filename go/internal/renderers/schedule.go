@@ -0,0 +1,253 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/cron"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+const (
+	// schedulePrepareTimeout bounds how long a schedule waits for its
+	// renderer to start.
+	schedulePrepareTimeout = 5 * time.Second
+	// maxScheduleWait bounds how long the runner sleeps, so that changes of
+	// the wall clock are noticed.
+	maxScheduleWait = time.Minute
+)
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// Schedule shows the content of its command whenever its expression matches
+// in its location.
+type Schedule struct {
+	ID         string
+	Expression cron.Expression
+	Location   *time.Location
+	Command    Command
+}
+
+// Next returns when the schedule runs next after the given time.
+func (s Schedule) Next(after time.Time) time.Time {
+	return s.Expression.Next(after.In(s.Location))
+}
+
+func (s Schedule) validate() error {
+	if !rgbmatrix.ScheduleIDPattern.MatchString(s.ID) {
+		return fmt.Errorf("%w: id %q may only contain letters, digits, '-' and '_'", ErrInvalidSchedule, s.ID)
+	}
+	if s.Location == nil {
+		return fmt.Errorf("%w: %q has no time zone", ErrInvalidSchedule, s.ID)
+	}
+	if s.Command.Name == "" && s.Command.Type != TypeBlank {
+		return fmt.Errorf("%w: %q has no name", ErrInvalidSchedule, s.ID)
+	}
+	if s.Command.Duration < 0 || (s.Command.Duration > 0 && !s.Command.IsTemporary) {
+		return fmt.Errorf("%w: %q cannot be shown for %s", ErrInvalidSchedule, s.ID, s.Command.Duration)
+	}
+	return nil
+}
+
+// ParseSchedule returns the schedule of a config entry.
+func ParseSchedule(id string, config rgbmatrix.Schedule) (Schedule, error) {
+	expression, err := cron.Parse(config.Cron)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	location, err := config.Location()
+	if err != nil {
+		return Schedule{}, fmt.Errorf("%w: timezone %q: %v", ErrInvalidSchedule, config.Timezone, err)
+	}
+	screenType, err := ParseScreenType(config.Type)
+	if err != nil {
+		return Schedule{}, err
+	}
+	return Schedule{
+		ID:         id,
+		Expression: expression,
+		Location:   location,
+		Command: Command{
+			Type:        screenType,
+			Name:        config.Name,
+			Params:      config.Params,
+			IsTemporary: config.Temporary || screenType == TypeGIFOnce,
+			Duration:    time.Duration(config.Duration),
+			Transition:  config.Transition,
+		},
+	}, nil
+}
+
+// Schedules stores schedules and sends their commands to the update loop
+// when they are due. Persistent content takes over from a running playlist,
+// like content chosen by hand.
+type Schedules struct {
+	commands  chan<- Command
+	playlists *Playlists
+	now       func() time.Time
+	// changed wakes Run when a schedule was saved or deleted.
+	changed chan struct{}
+
+	mu        sync.Mutex
+	schedules map[string]*scheduleRun
+}
+
+type scheduleRun struct {
+	schedule Schedule
+	next     time.Time
+}
+
+// NewSchedules returns an empty schedule store. Its schedules run once Run
+// was started. playlists may be nil.
+func NewSchedules(commands chan<- Command, playlists *Playlists) *Schedules {
+	return &Schedules{
+		commands:  commands,
+		playlists: playlists,
+		now:       time.Now,
+		changed:   make(chan struct{}, 1),
+		schedules: make(map[string]*scheduleRun),
+	}
+}
+
+// Load saves the schedules of config.
+func (s *Schedules) Load(config rgbmatrix.Config) error {
+	for _, id := range rgbmatrix.ScheduleIDs(config.Schedules) {
+		schedule, err := ParseSchedule(id, config.Schedules[id])
+		if err == nil {
+			_, err = s.Save(schedule)
+		}
+		if err != nil {
+			return fmt.Errorf("schedule %q: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// Save creates or replaces a schedule and reports whether it replaced one.
+func (s *Schedules) Save(schedule Schedule) (bool, error) {
+	if err := schedule.validate(); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	_, replaced := s.schedules[schedule.ID]
+	s.schedules[schedule.ID] = &scheduleRun{schedule: schedule, next: schedule.Next(s.now())}
+	s.mu.Unlock()
+	s.wake()
+	return replaced, nil
+}
+
+// Delete removes a schedule and reports whether it existed.
+func (s *Schedules) Delete(id string) bool {
+	s.mu.Lock()
+	_, ok := s.schedules[id]
+	delete(s.schedules, id)
+	s.mu.Unlock()
+	if ok {
+		s.wake()
+	}
+	return ok
+}
+
+// List returns the schedules sorted by ID.
+func (s *Schedules) List() []Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := make([]Schedule, 0, len(s.schedules))
+	for _, run := range s.schedules {
+		items = append(items, run.schedule)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
+
+// Run shows the content of the schedules when they are due until ctx is
+// done. Times that passed while the server was not running are not made up
+// for.
+func (s *Schedules) Run(ctx context.Context) {
+	for {
+		wait := maxScheduleWait
+		if next := s.upcoming(); !next.IsZero() {
+			wait = min(wait, next.Sub(s.now()))
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.changed:
+			timer.Stop()
+			continue
+		case <-timer.C:
+		}
+
+		for _, schedule := range s.due() {
+			s.show(ctx, schedule)
+		}
+	}
+}
+
+func (s *Schedules) wake() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// upcoming returns the earliest time a schedule runs next, or the zero time
+// if none does.
+func (s *Schedules) upcoming() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next time.Time
+	for _, run := range s.schedules {
+		if !run.next.IsZero() && (next.IsZero() || run.next.Before(next)) {
+			next = run.next
+		}
+	}
+	return next
+}
+
+// due returns the schedules whose time came, sorted by ID, and advances them
+// to their next run.
+func (s *Schedules) due() []Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	var due []Schedule
+	for _, run := range s.schedules {
+		if run.next.IsZero() || run.next.After(now) {
+			continue
+		}
+		due = append(due, run.schedule)
+		run.next = run.schedule.Next(now)
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	return due
+}
+
+func (s *Schedules) show(ctx context.Context, schedule Schedule) {
+	command := schedule.Command
+	if !command.IsTemporary && s.playlists != nil {
+		s.playlists.Stop()
+	}
+	if err := s.send(ctx, command); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("schedule failed: id=%q type=%s name=%q error=%v", schedule.ID, command.Type, command.Name, err)
+		}
+		return
+	}
+	log.Printf("schedule ran: id=%q type=%s name=%q", schedule.ID, command.Type, command.Name)
+}
+
+func (s *Schedules) send(ctx context.Context, command Command) error {
+	ctx, cancel := context.WithTimeout(ctx, schedulePrepareTimeout)
+	defer cancel()
+	return send(ctx, s.commands, command)
+}
@@ -0,0 +1,82 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/cron"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestScheduleShowsContentWhenDue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	commands := make(chan Command)
+	state := NewState()
+	go updateLoop(ctx, commands, rgbmatrix.NewMemory(16, 16), LoopOptions{State: state})
+	if err := sendCommand(commands, Command{Type: TypeAnimation, Name: "plasma"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The clock of the schedules starts shortly before 07:00 in New York.
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	start := time.Now()
+	base := time.Date(2024, time.June, 3, 6, 59, 59, 950_000_000, location)
+	schedules := NewSchedules(commands, nil)
+	schedules.now = func() time.Time { return base.Add(time.Since(start)) }
+	config := rgbmatrix.Config{Schedules: map[string]rgbmatrix.Schedule{
+		"morning": {Cron: "0 7 * * *", Timezone: "America/New_York", Type: "dashboard", Name: "clock"},
+		"night":   {Cron: "0 22 * * *", Timezone: "America/New_York", Type: "blank"},
+	}}
+	if err := schedules.Load(config); err != nil {
+		t.Fatal(err)
+	}
+	go schedules.Run(ctx)
+
+	waitForState(t, state, func(snapshot DisplayState) bool {
+		return snapshot.Type == TypeDashboard && snapshot.Name == "clock"
+	})
+
+	night := schedules.List()[1]
+	if want := time.Date(2024, time.June, 4, 2, 0, 0, 0, time.UTC); !night.Next(schedules.now()).Equal(want) {
+		t.Fatalf("unexpected next run: %s", night.Next(schedules.now()))
+	}
+}
+
+func TestScheduleSaveAndDelete(t *testing.T) {
+	schedules := NewSchedules(make(chan Command), nil)
+	expression, err := cron.Parse("@hourly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule := Schedule{ID: "hourly", Expression: expression, Location: time.UTC, Command: Command{Type: TypeAnimation, Name: "plasma"}}
+
+	if replaced, err := schedules.Save(schedule); err != nil || replaced {
+		t.Fatalf("unexpected save: replaced=%t error=%v", replaced, err)
+	}
+	if replaced, err := schedules.Save(schedule); err != nil || !replaced {
+		t.Fatalf("unexpected save: replaced=%t error=%v", replaced, err)
+	}
+	if items := schedules.List(); len(items) != 1 || items[0].ID != "hourly" {
+		t.Fatalf("unexpected schedules: %#v", items)
+	}
+	if !schedules.Delete("hourly") || schedules.Delete("hourly") {
+		t.Fatal("schedule was not deleted once")
+	}
+
+	for _, invalid := range []Schedule{
+		{ID: "no spaces", Expression: expression, Location: time.UTC, Command: Command{Type: TypeBlank}},
+		{ID: "nowhere", Expression: expression, Command: Command{Type: TypeBlank}},
+		{ID: "unnamed", Expression: expression, Location: time.UTC, Command: Command{Type: TypeImage}},
+		{ID: "persistent", Expression: expression, Location: time.UTC, Command: Command{Type: TypeBlank, Duration: time.Second}},
+	} {
+		if _, err := schedules.Save(invalid); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("unexpected error for %q: %v", invalid.ID, err)
+		}
+	}
+}
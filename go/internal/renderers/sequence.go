@@ -0,0 +1,210 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// sequencePrepareTimeout bounds how long a sequence waits for one step's
+// renderer to start before skipping it.
+const sequencePrepareTimeout = 5 * time.Second
+
+// MaxSequenceSteps bounds the steps of one sequence.
+const MaxSequenceSteps = 32
+
+var ErrInvalidSequence = errors.New("invalid sequence")
+
+// Sequence is content shown step by step. Temporary steps are queued as
+// notifications and shown back to back, and a persistent step is sent once
+// the temporary steps before it ended, so that it replaces the content only
+// in its turn. The sequence runs until its last step was sent and its
+// temporary steps ended.
+type Sequence struct {
+	ID    uint64
+	Steps []Command
+}
+
+func (s Sequence) validate() error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("%w: it has no steps", ErrInvalidSequence)
+	}
+	if len(s.Steps) > MaxSequenceSteps {
+		return fmt.Errorf("%w: it has more than %d steps", ErrInvalidSequence, MaxSequenceSteps)
+	}
+	for index, step := range s.Steps {
+		if step.Name == "" && step.Type != TypeBlank {
+			return fmt.Errorf("%w: step %d has no name", ErrInvalidSequence, index)
+		}
+		if step.Duration < 0 || (step.Duration > 0 && !step.IsTemporary) {
+			return fmt.Errorf("%w: step %d cannot be shown for %s", ErrInvalidSequence, index, step.Duration)
+		}
+	}
+	return nil
+}
+
+// Sequences runs sequences by sending their steps to the update loop as
+// regular commands. Several sequences may run at the same time; their
+// temporary steps share the notification queue.
+type Sequences struct {
+	ctx      context.Context
+	commands chan<- Command
+	// withdrawals remove the notification of a cancelled sequence.
+	withdrawals chan chan struct{}
+
+	mu      sync.Mutex
+	nextID  uint64
+	running map[uint64]*sequenceRun
+}
+
+type sequenceRun struct {
+	sequence Sequence
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewSequences returns a runner of sequences. The update loop must receive
+// its Withdrawals. Running sequences stop when ctx is cancelled.
+func NewSequences(ctx context.Context, commands chan<- Command) *Sequences {
+	return &Sequences{
+		ctx:         ctx,
+		commands:    commands,
+		withdrawals: make(chan chan struct{}),
+		running:     make(map[uint64]*sequenceRun),
+	}
+}
+
+// Withdrawals returns the channel that the update loop receives the
+// notifications of cancelled sequences from, see LoopOptions.
+func (s *Sequences) Withdrawals() <-chan chan struct{} {
+	return s.withdrawals
+}
+
+// Start starts a sequence of steps and returns it with its ID.
+func (s *Sequences) Start(steps []Command) (Sequence, error) {
+	sequence := Sequence{Steps: append([]Command(nil), steps...)}
+	if err := sequence.validate(); err != nil {
+		return Sequence{}, err
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.mu.Lock()
+	s.nextID++
+	sequence.ID = s.nextID
+	run := &sequenceRun{sequence: sequence, cancel: cancel, done: make(chan struct{})}
+	s.running[sequence.ID] = run
+	s.mu.Unlock()
+
+	log.Printf("sequence started: id=%d steps=%d", sequence.ID, len(sequence.Steps))
+	go func() {
+		defer close(run.done)
+		defer s.finish(run)
+		s.run(ctx, sequence)
+	}()
+	return sequence, nil
+}
+
+// List returns the running sequences sorted by ID.
+func (s *Sequences) List() []Sequence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := make([]Sequence, 0, len(s.running))
+	for _, run := range s.running {
+		items = append(items, run.sequence)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
+
+// Cancel stops a running sequence and withdraws its temporary step that is
+// shown or waiting. It reports whether the sequence was running.
+func (s *Sequences) Cancel(id uint64) bool {
+	s.mu.Lock()
+	run, ok := s.running[id]
+	delete(s.running, id)
+	s.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	run.cancel()
+	<-run.done
+	log.Printf("sequence cancelled: id=%d", id)
+	return true
+}
+
+func (s *Sequences) finish(run *sequenceRun) {
+	run.cancel()
+	s.mu.Lock()
+	finished := s.running[run.sequence.ID] == run
+	if finished {
+		delete(s.running, run.sequence.ID)
+	}
+	s.mu.Unlock()
+	if finished {
+		log.Printf("sequence finished: id=%d", run.sequence.ID)
+	}
+}
+
+func (s *Sequences) run(ctx context.Context, sequence Sequence) {
+	var pending []chan struct{}
+	for index, step := range sequence.Steps {
+		if !step.IsTemporary {
+			if !s.wait(ctx, pending) {
+				return
+			}
+			pending = nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		if step.IsTemporary {
+			step.Finished = make(chan struct{})
+		}
+		err := s.send(ctx, step)
+		// The update loop may have received a step whose result was
+		// cancelled, so that it is withdrawn as well.
+		if step.Finished != nil && (err == nil || ctx.Err() != nil) {
+			pending = append(pending, step.Finished)
+		}
+		if err != nil && ctx.Err() == nil {
+			log.Printf("sequence step skipped: id=%d step=%d type=%s name=%q error=%v",
+				sequence.ID, index, step.Type, step.Name, err)
+		}
+	}
+	s.wait(ctx, pending)
+}
+
+// wait waits until the pending temporary steps ended and reports whether
+// they did. If ctx is done first, the steps that did not end are withdrawn.
+func (s *Sequences) wait(ctx context.Context, pending []chan struct{}) bool {
+	for index, finished := range pending {
+		select {
+		case <-finished:
+			continue
+		case <-ctx.Done():
+		}
+		// Waiting steps are withdrawn before the shown one, so that none
+		// of them starts in between.
+		for i := len(pending) - 1; i >= index; i-- {
+			select {
+			case s.withdrawals <- pending[i]:
+			case <-s.ctx.Done():
+				return false
+			}
+		}
+		return false
+	}
+	return ctx.Err() == nil
+}
+
+func (s *Sequences) send(ctx context.Context, step Command) error {
+	ctx, cancel := context.WithTimeout(ctx, sequencePrepareTimeout)
+	defer cancel()
+	return send(ctx, s.commands, step)
+}
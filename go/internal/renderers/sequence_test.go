@@ -0,0 +1,111 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestSequenceShowsStepsInOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	commands := make(chan Command)
+	steps := make(chan Command)
+	sequences := NewSequences(ctx, steps)
+	state := NewState()
+	go updateLoop(ctx, commands, rgbmatrix.NewMemory(16, 16), LoopOptions{State: state, Withdrawals: sequences.Withdrawals()})
+	if err := sendCommand(commands, Command{Type: TypeAnimation, Name: "plasma"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The steps reach the update loop through sent, which records them in
+	// the order they were sent and whether the temporary steps before a
+	// persistent one had ended.
+	var sent []string
+	var temporary []chan struct{}
+	early := false
+	go func() {
+		for step := range steps {
+			sent = append(sent, step.Name)
+			if step.IsTemporary {
+				temporary = append(temporary, step.Finished)
+			}
+			for _, finished := range temporary {
+				select {
+				case <-finished:
+				default:
+					early = early || !step.IsTemporary
+				}
+			}
+			commands <- step
+		}
+	}()
+	_, err := sequences.Start([]Command{
+		{Type: TypeAnimation, Name: "ripple", IsTemporary: true, Duration: 10 * time.Millisecond},
+		{Type: TypeAnimation, Name: "spiral", IsTemporary: true, Duration: 10 * time.Millisecond},
+		{Type: TypeDashboard, Name: "clock"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitForState(t, state, func(snapshot DisplayState) bool { return snapshot.Name == "clock" && !snapshot.Temporary })
+	close(steps)
+	if !slices.Equal(sent, []string{"ripple", "spiral", "clock"}) {
+		t.Fatalf("unexpected order of steps: %q", sent)
+	}
+	if early {
+		t.Fatal("persistent step was sent before the temporary steps ended")
+	}
+}
+
+func TestSequenceCancelWithdrawsStep(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	commands := make(chan Command)
+	sequences := NewSequences(ctx, commands)
+	state := NewState()
+	go updateLoop(ctx, commands, rgbmatrix.NewMemory(16, 16), LoopOptions{State: state, Withdrawals: sequences.Withdrawals()})
+	if err := sendCommand(commands, Command{Type: TypeAnimation, Name: "plasma"}); err != nil {
+		t.Fatal(err)
+	}
+
+	sequence, err := sequences.Start([]Command{
+		{Type: TypeAnimation, Name: "ripple", IsTemporary: true, Duration: time.Minute},
+		{Type: TypeAnimation, Name: "spiral", IsTemporary: true, Duration: time.Minute},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForState(t, state, func(snapshot DisplayState) bool { return snapshot.Name == "ripple" })
+
+	if !sequences.Cancel(sequence.ID) {
+		t.Fatal("running sequence was not cancelled")
+	}
+	if sequences.Cancel(sequence.ID) {
+		t.Fatal("cancelled sequence was cancelled again")
+	}
+	if err := sendCommand(commands, Command{Type: TypeAnimation, Name: "plasma"}); err != nil {
+		t.Fatal(err)
+	}
+	if snapshot := state.Snapshot(); snapshot.Name != "plasma" || snapshot.Temporary || len(state.Notifications()) > 0 {
+		t.Fatalf("cancelled step is still shown: %#v %#v", snapshot, state.Notifications())
+	}
+}
+
+func TestSequenceValidation(t *testing.T) {
+	sequences := NewSequences(context.Background(), make(chan Command))
+	for _, steps := range [][]Command{
+		nil,
+		{{Type: TypeAnimation}},
+		{{Type: TypeAnimation, Name: "plasma", Duration: time.Second}},
+	} {
+		if _, err := sequences.Start(steps); !errors.Is(err, ErrInvalidSequence) {
+			t.Errorf("unexpected error for %#v: %v", steps, err)
+		}
+	}
+}
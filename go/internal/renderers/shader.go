@@ -0,0 +1,63 @@
+package renderers
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+// Shader returns the color of the pixel at x, y at time t. Shaders run on
+// several goroutines at once and must not change shared state.
+type Shader func(x, y int, t float64) color.RGBA
+
+// shade evaluates shader for every pixel of frame. A worker per CPU takes
+// the next row that is not drawn yet, so that rows that are slower to shade
+// do not hold up the other workers.
+func shade(frame *image.RGBA, t float64, shader Shader) {
+	bounds := frame.Bounds()
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), bounds.Dy()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				y := bounds.Min.Y + int(next.Add(1)-1)
+				if y >= bounds.Max.Y {
+					return
+				}
+				row := frame.Pix[frame.PixOffset(bounds.Min.X, y):]
+				for x := bounds.Min.X; x < bounds.Max.X; x++ {
+					c := shader(x, y, t)
+					pix := row[4*(x-bounds.Min.X) : 4*(x-bounds.Min.X)+4 : 4*(x-bounds.Min.X)+4]
+					pix[0], pix[1], pix[2], pix[3] = c.R, c.G, c.B, c.A
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// runShader draws the frames of an animation with shader, which gets the
+// animation time in seconds.
+func (a *animated) runShader(ctx context.Context, screen *rgbmatrix.Screen, shader Shader) error {
+	return a.run(ctx, func(elapsed time.Duration) error {
+		shade(screen.Frame(), a.seconds(elapsed), shader)
+		return screen.Present()
+	})
+}
+
+// rgb returns the opaque color of channels from 0 to 1.
+func rgb(r, g, b float64) color.RGBA {
+	channel := func(value float64) uint8 {
+		return uint8(math.Max(0, math.Min(value, 1)) * 255)
+	}
+	return color.RGBA{R: channel(r), G: channel(g), B: channel(b), A: 255}
+}
@@ -0,0 +1,80 @@
+package renderers
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestShadeDrawsEveryPixel(t *testing.T) {
+	whole := image.NewRGBA(image.Rect(0, 0, 7, 5))
+	frame := whole.SubImage(image.Rect(1, 1, 6, 4)).(*image.RGBA)
+	shade(frame, 0.5, func(x, y int, t float64) color.RGBA {
+		return color.RGBA{R: uint8(x), G: uint8(y), B: uint8(t * 10), A: 255}
+	})
+
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 7; x++ {
+			want := color.RGBA{}
+			if (image.Point{X: x, Y: y}).In(frame.Rect) {
+				want = color.RGBA{R: uint8(x), G: uint8(y), B: 5, A: 255}
+			}
+			if got := whole.RGBAAt(x, y); got != want {
+				t.Fatalf("pixel %d,%d = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestRGBClampsChannels(t *testing.T) {
+	if got, want := rgb(-1, 0.5, 2), (color.RGBA{R: 0, G: 127, B: 255, A: 255}); got != want {
+		t.Fatalf("rgb = %v, want %v", got, want)
+	}
+}
+
+func TestSoftBloomRingsIsOpaque(t *testing.T) {
+	frame := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	shade(frame, 0.25, softBloomRings(32, 32))
+
+	unlit := 0
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			pixel := frame.RGBAAt(x, y)
+			if pixel.A != 255 {
+				t.Fatalf("pixel %d,%d is not opaque: %v", x, y, pixel)
+			}
+			if pixel == (color.RGBA{A: 255}) {
+				unlit++
+			}
+		}
+	}
+	if unlit == 0 {
+		t.Fatal("no pixel between the rings is black")
+	}
+}
+
+// BenchmarkShade compares shading a frame on one goroutine with shading it
+// on the worker pool.
+func BenchmarkShade(b *testing.B) {
+	frame := image.NewRGBA(image.Rect(0, 0, 192, 96))
+	shader := func(px, py int, t float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		v := math.Sin(x/16+t) + math.Sin(y/8+t) + math.Sin((x+y)/16+t) + math.Sin(math.Hypot(x, y)/8+t)
+		return rgb(math.Sin(v), math.Sin(v+2), math.Sin(v+4))
+	}
+	b.Run("Serial", func(b *testing.B) {
+		for range b.N {
+			for y := 0; y < 96; y++ {
+				for x := 0; x < 192; x++ {
+					frame.SetRGBA(x, y, shader(x, y, 0.5))
+				}
+			}
+		}
+	})
+	b.Run("Parallel", func(b *testing.B) {
+		for range b.N {
+			shade(frame, 0.5, shader)
+		}
+	})
+}
@@ -10,11 +10,10 @@ import (
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
 )
 
-const solarSystemFrameInterval = time.Second / 30
-
 type SolarSystemRenderer struct {
 	screen *rgbmatrix.Screen
-	stars  []solarSystemStar
+	animated
+	stars []solarSystemStar
 }
 
 type solarSystemStar struct {
@@ -61,21 +60,10 @@ func SolarSystem(screen *rgbmatrix.Screen) *SolarSystemRenderer {
 }
 
 func (r *SolarSystemRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	start := time.Now()
-	ticker := time.NewTicker(solarSystemFrameInterval)
-	defer ticker.Stop()
-
-	for {
-		if err := r.drawFrame(ctx, dc, time.Since(start).Seconds()); err != nil {
-			return err
-		}
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-		}
-	}
+	dc := gg.NewContextForRGBA(r.screen.Frame())
+	return r.run(ctx, func(elapsed time.Duration) error {
+		return r.drawFrame(ctx, dc, r.seconds(elapsed))
+	})
 }
 
 func (r *SolarSystemRenderer) drawFrame(ctx context.Context, dc *gg.Context, elapsed float64) error {
@@ -96,7 +84,8 @@ func (r *SolarSystemRenderer) drawFrame(ctx context.Context, dc *gg.Context, ela
 		dc.Stroke()
 	}
 
-	for _, planet := range solarSystemPlanets {
+	for i, planet := range solarSystemPlanets {
+		planet.red, planet.green, planet.blue = r.rgbAt(float64(i)/float64(len(solarSystemPlanets)), planet.red, planet.green, planet.blue)
 		angle := elapsed*planet.speed + planet.phase
 		x := centerX + math.Cos(angle)*maxOrbitX*planet.orbit
 		y := centerY + math.Sin(angle)*maxOrbitY*planet.orbit
@@ -104,7 +93,7 @@ func (r *SolarSystemRenderer) drawFrame(ctx context.Context, dc *gg.Context, ela
 	}
 
 	r.drawSun(dc, centerX, centerY, math.Max(2.2, math.Min(width, height)*0.055), elapsed)
-	return r.screen.ShowImage(ctx, dc.Image())
+	return r.screen.Present()
 }
 
 func (r *SolarSystemRenderer) drawStars(dc *gg.Context, elapsed float64) {
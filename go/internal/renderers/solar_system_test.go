@@ -16,7 +16,7 @@ func TestSolarSystemDrawsAFrame(t *testing.T) {
 	}
 	screen := rgbmatrix.NewScreen(matrix)
 	renderer := SolarSystem(screen)
-	dc := gg.NewContextForImage(screen.Canvas)
+	dc := gg.NewContextForRGBA(screen.Frame())
 
 	if err := renderer.drawFrame(context.Background(), dc, 1.25); err != nil {
 		t.Fatal(err)
@@ -0,0 +1,191 @@
+package renderers
+
+import (
+	"sync"
+	"time"
+)
+
+// Content identifies what a renderer shows.
+type Content struct {
+	Type ScreenType
+	Name string
+}
+
+// maxRendererErrors bounds the renderer errors kept by the state.
+const maxRendererErrors = 20
+
+// RendererError records a renderer that could not be prepared, failed to
+// start or stopped with an error.
+type RendererError struct {
+	Content
+	Message string
+	At      time.Time
+}
+
+// DisplayState is a snapshot of what the update loop shows.
+type DisplayState struct {
+	Content
+	Temporary bool
+	// StartedAt is zero until the first renderer started.
+	StartedAt time.Time
+	// RevertTo is the content that is restored when temporary content
+	// finishes. It is nil for non-temporary content.
+	RevertTo *Content
+	// Failed is set when the shown renderer stopped with an error, which
+	// leaves its last frame on the display.
+	Failed    bool
+	LastError *RendererError
+}
+
+// State is the display state owned by the update loop. It is safe for
+// concurrent use.
+type State struct {
+	mu            sync.RWMutex
+	current       DisplayState
+	notifications []Notification
+	errors        []RendererError
+	now           func() time.Time
+	// changed is closed and replaced on every change.
+	changed chan struct{}
+}
+
+func NewState() *State {
+	return &State{now: time.Now, changed: make(chan struct{})}
+}
+
+// Changed returns a channel that is closed on the next change of the state.
+func (s *State) Changed() <-chan struct{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.changed
+}
+
+// changedLocked wakes up the receivers of Changed.
+func (s *State) changedLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Snapshot returns a copy of the current display state.
+func (s *State) Snapshot() DisplayState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snapshot := s.current
+	if snapshot.RevertTo != nil {
+		revertTo := *snapshot.RevertTo
+		snapshot.RevertTo = &revertTo
+	}
+	if snapshot.LastError != nil {
+		lastError := *snapshot.LastError
+		snapshot.LastError = &lastError
+	}
+	return snapshot
+}
+
+// Notifications returns the active notification followed by the waiting
+// ones in the order they will be shown.
+func (s *State) Notifications() []Notification {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	// The update loop drops expired notifications only when it gets to
+	// them, so they are left out here already.
+	now := s.now()
+	notifications := make([]Notification, 0, len(s.notifications))
+	for _, n := range s.notifications {
+		if !n.expiresAt.IsZero() && now.After(n.expiresAt) {
+			continue
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications
+}
+
+// Errors returns the recent renderer errors, newest first.
+func (s *State) Errors() []RendererError {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	recent := make([]RendererError, len(s.errors))
+	for i, err := range s.errors {
+		recent[len(s.errors)-1-i] = err
+	}
+	return recent
+}
+
+func (s *State) started(cmd Command, revertTo *Content) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.changedLocked()
+	s.current.Content = Content{Type: cmd.Type, Name: cmd.Name}
+	s.current.Temporary = cmd.IsTemporary
+	s.current.StartedAt = s.now()
+	s.current.RevertTo = nil
+	s.current.Failed = false
+	if cmd.IsTemporary && revertTo != nil {
+		content := *revertTo
+		s.current.RevertTo = &content
+	}
+}
+
+func (s *State) failed(cmd Command, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.changedLocked()
+	rendererError := RendererError{
+		Content: Content{Type: cmd.Type, Name: cmd.Name},
+		Message: err.Error(),
+		At:      s.now(),
+	}
+	s.current.LastError = &rendererError
+	s.errors = append(s.errors, rendererError)
+	if len(s.errors) > maxRendererErrors {
+		s.errors = append([]RendererError{}, s.errors[len(s.errors)-maxRendererErrors:]...)
+	}
+}
+
+// stopped records that the shown renderer stopped with an error.
+func (s *State) stopped() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.changedLocked()
+	s.current.Failed = true
+}
+
+// reverting records the persistent content that replaced the revert target
+// while temporary content is shown.
+func (s *State) reverting(to Content) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.changedLocked()
+	if s.current.Temporary {
+		s.current.RevertTo = &to
+	}
+}
+
+// idle records that nothing is shown.
+func (s *State) idle() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.changedLocked()
+	s.current = DisplayState{LastError: s.current.LastError}
+}
+
+func (s *State) setNotifications(active *queuedNotification, queue notificationQueue) {
+	notifications := make([]Notification, 0, len(queue)+1)
+	if active != nil {
+		notification := active.Notification
+		notification.Active = true
+		notifications = append(notifications, notification)
+	}
+	for _, n := range queue {
+		notification := n.Notification
+		if !n.shown && n.TTL > 0 {
+			notification.expiresAt = n.EnqueuedAt.Add(n.TTL)
+		}
+		notifications = append(notifications, notification)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.changedLocked()
+	s.notifications = notifications
+}
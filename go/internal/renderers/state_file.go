@@ -0,0 +1,248 @@
+package renderers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+// bootTimeout bounds how long restoring one piece of content may take
+// before the next fallback is tried.
+const bootTimeout = 10 * time.Second
+
+// StateFile keeps the display state across restarts: the last persistent
+// command, the brightness and the running playlist. It is rewritten on every
+// change. A nil *StateFile saves nothing. It is safe for concurrent use.
+type StateFile struct {
+	path string
+
+	mu    sync.Mutex
+	saved savedState
+	// unwritable is set when Writable failed, after which the state is
+	// only kept in memory.
+	unwritable bool
+}
+
+type savedState struct {
+	Display    *savedCommand  `json:"display,omitempty"`
+	Brightness *int           `json:"brightness,omitempty"`
+	Playlist   *savedPlaylist `json:"playlist,omitempty"`
+}
+
+type savedCommand struct {
+	Type   string            `json:"type"`
+	Name   string            `json:"name"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+type savedPlaylist struct {
+	Name    string       `json:"name"`
+	Entries []savedEntry `json:"entries"`
+	Shuffle bool         `json:"shuffle"`
+	Repeat  bool         `json:"repeat"`
+}
+
+type savedEntry struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Duration string `json:"duration"`
+}
+
+// LoadStateFile reads the state saved at path. A missing file is an empty
+// state. A file that cannot be read is returned with the error and starts
+// over with an empty state.
+func LoadStateFile(path string) (*StateFile, error) {
+	f := &StateFile{path: path}
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return f, fmt.Errorf("read display state: %w", err)
+	}
+	if err := json.Unmarshal(bytes, &f.saved); err != nil {
+		f.saved = savedState{}
+		return f, fmt.Errorf("parse display state %s: %w", path, err)
+	}
+	return f, nil
+}
+
+func (f *StateFile) saveDisplay(cmd Command) {
+	f.update(func(saved *savedState) {
+		saved.Display = &savedCommand{Type: cmd.Type.String(), Name: cmd.Name, Params: cmd.Params}
+	})
+}
+
+func (f *StateFile) saveBrightness(brightness int) {
+	f.update(func(saved *savedState) { saved.Brightness = &brightness })
+}
+
+// savePlaylist saves the running playlist, or that none runs if it is nil.
+func (f *StateFile) savePlaylist(playlist *Playlist) {
+	f.update(func(saved *savedState) {
+		saved.Playlist = nil
+		if playlist == nil {
+			return
+		}
+		saved.Playlist = &savedPlaylist{Name: playlist.Name, Shuffle: playlist.Shuffle, Repeat: playlist.Repeat}
+		for _, entry := range playlist.Entries {
+			saved.Playlist.Entries = append(saved.Playlist.Entries, savedEntry{
+				Type: entry.Type.String(), Name: entry.Name, Duration: entry.Duration.String(),
+			})
+		}
+	})
+}
+
+// update changes the saved state and writes it to a temporary file that
+// replaces the state file, so that a crash never leaves half a file behind.
+func (f *StateFile) update(change func(*savedState)) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	change(&f.saved)
+	if f.unwritable {
+		return
+	}
+
+	bytes, err := json.MarshalIndent(f.saved, "", "  ")
+	if err == nil {
+		if err = os.WriteFile(f.temporary(), append(bytes, '\n'), 0o600); err == nil {
+			err = os.Rename(f.temporary(), f.path)
+		}
+	}
+	if err != nil {
+		log.Printf("save display state: %v", err)
+	}
+}
+
+// Writable reports an error if the state file cannot be written, such as
+// when its directory does not exist or belongs to another user. The servers
+// check it once the privileges were dropped. A state file that is not
+// writable is not saved any more, so that the problem is reported once when
+// they start instead of on every change.
+func (f *StateFile) Writable() error {
+	if f == nil {
+		return nil
+	}
+	file, err := os.OpenFile(f.temporary(), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		f.mu.Lock()
+		f.unwritable = true
+		f.mu.Unlock()
+		return fmt.Errorf("display state cannot be saved: %w", err)
+	}
+	file.Close()
+	return os.Remove(f.temporary())
+}
+
+// temporary is the file that a new state is written to before it replaces
+// the state file.
+func (f *StateFile) temporary() string {
+	return filepath.Join(filepath.Dir(f.path), "."+filepath.Base(f.path)+".tmp")
+}
+
+// Dimmable returns matrix with brightness changes that are saved.
+func (f *StateFile) Dimmable(matrix rgbmatrix.Dimmable) rgbmatrix.Dimmable {
+	return savingDimmable{Dimmable: matrix, file: f}
+}
+
+type savingDimmable struct {
+	rgbmatrix.Dimmable
+	file *StateFile
+}
+
+func (d savingDimmable) SetBrightness(brightness int) error {
+	if err := d.Dimmable.SetBrightness(brightness); err != nil {
+		return err
+	}
+	d.file.saveBrightness(brightness)
+	return nil
+}
+
+// Restore brings back the saved state through the update loop, which must
+// be running. The saved brightness is set on matrix, if it is not nil, and
+// takes precedence over options.brightness of the config, which only
+// applies until the brightness is first changed. The
+// saved playlist is started again, or else the saved command is shown. When
+// nothing was saved or it cannot be shown any more, boot is shown instead.
+func (f *StateFile) Restore(ctx context.Context, commands chan<- Command, playlists *Playlists, matrix rgbmatrix.Dimmable, boot Command) {
+	f.mu.Lock()
+	saved := f.saved
+	f.mu.Unlock()
+
+	if saved.Brightness != nil && matrix != nil {
+		if err := matrix.SetBrightness(*saved.Brightness); err != nil {
+			log.Printf("saved brightness is ignored: %v", err)
+		}
+	}
+	if saved.Playlist != nil && playlists != nil {
+		err := restorePlaylist(playlists, *saved.Playlist)
+		if err == nil {
+			return
+		}
+		log.Printf("saved playlist is ignored: playlist=%q error=%v", saved.Playlist.Name, err)
+	}
+	if saved.Display != nil {
+		err := restoreCommand(ctx, commands, *saved.Display)
+		if err == nil {
+			return
+		}
+		log.Printf("saved display is ignored: type=%s name=%q error=%v", saved.Display.Type, saved.Display.Name, err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, bootTimeout)
+	defer cancel()
+	if err := send(ctx, commands, boot); err != nil {
+		log.Printf("boot content could not be shown: type=%s name=%q error=%v", boot.Type, boot.Name, err)
+	}
+}
+
+func restorePlaylist(playlists *Playlists, saved savedPlaylist) error {
+	playlist := Playlist{Name: saved.Name, Shuffle: saved.Shuffle, Repeat: saved.Repeat}
+	for _, entry := range saved.Entries {
+		screenType, err := ParseScreenType(entry.Type)
+		if err != nil {
+			return err
+		}
+		duration, err := time.ParseDuration(entry.Duration)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPlaylist, err)
+		}
+		playlist.Entries = append(playlist.Entries, PlaylistEntry{Type: screenType, Name: entry.Name, Duration: duration})
+	}
+	if err := playlists.Save(playlist); err != nil {
+		return err
+	}
+	return playlists.Start(playlist.Name)
+}
+
+func restoreCommand(ctx context.Context, commands chan<- Command, saved savedCommand) error {
+	screenType, err := ParseScreenType(saved.Type)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, bootTimeout)
+	defer cancel()
+	return send(ctx, commands, Command{Type: screenType, Name: saved.Name, Params: saved.Params})
+}
+
+// BootCommand returns the command that shows the boot content of config.
+func BootCommand(config rgbmatrix.Config) (Command, error) {
+	screenType, err := ParseScreenType(config.Boot.Type)
+	if err != nil {
+		return Command{}, err
+	}
+	if screenType == TypeGIFOnce {
+		return Command{}, fmt.Errorf("%w: boot content cannot be temporary", ErrInvalidParameter)
+	}
+	return Command{Type: screenType, Name: config.Boot.Name, Params: config.Boot.Params}, nil
+}
@@ -0,0 +1,219 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/display"
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestStateFileSavesAndRestoresDisplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	file, err := LoadStateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	commands := make(chan Command)
+	go updateLoop(ctx, commands, rgbmatrix.NewMemory(16, 16), LoopOptions{StateFile: file})
+	plasma := Command{Type: TypeAnimation, Name: "plasma", Params: map[string]string{"speed": "0.5"}}
+	for _, command := range []Command{
+		plasma,
+		{Type: TypeAnimation, Name: "ripple", IsTemporary: true, Duration: time.Minute},
+	} {
+		if err := sendCommand(commands, command); err != nil {
+			t.Fatal(err)
+		}
+	}
+	matrix := rgbmatrix.NewObservable(rgbmatrix.NewMemory(16, 16), display.NewHub().Publish)
+	if err := file.Dimmable(matrix).SetBrightness(40); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	file, err = LoadStateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	commands = make(chan Command)
+	state := NewState()
+	go updateLoop(ctx, commands, rgbmatrix.NewMemory(16, 16), LoopOptions{State: state})
+	matrix = rgbmatrix.NewObservable(rgbmatrix.NewMemory(16, 16), display.NewHub().Publish)
+	file.Restore(ctx, commands, nil, matrix, Command{Type: TypeDashboard, Name: "clock"})
+
+	if snapshot := state.Snapshot(); snapshot.Type != TypeAnimation || snapshot.Name != "plasma" || snapshot.Temporary {
+		t.Fatalf("saved display was not restored: %#v", snapshot)
+	}
+	if brightness := matrix.Brightness(); brightness != 40 {
+		t.Fatalf("saved brightness was not restored: %d", brightness)
+	}
+	if saved := file.saved.Display; !reflect.DeepEqual(saved.Params, plasma.Params) {
+		t.Fatalf("parameters were not saved: %#v", saved)
+	}
+}
+
+func TestStateFileFallsBackToBootContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte(`{"display":{"type":"animation","name":"missing"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	file, err := LoadStateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	commands := make(chan Command)
+	state := NewState()
+	go updateLoop(ctx, commands, rgbmatrix.NewMemory(16, 16), LoopOptions{State: state})
+	file.Restore(ctx, commands, nil, nil, Command{Type: TypeDashboard, Name: "clock"})
+
+	if snapshot := state.Snapshot(); snapshot.Type != TypeDashboard || snapshot.Name != "clock" {
+		t.Fatalf("boot content was not shown: %#v", snapshot)
+	}
+}
+
+func TestStateFileSavesRunningPlaylist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	file, err := LoadStateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	commands := make(chan Command)
+	go func() {
+		for command := range commands {
+			command.Result <- nil
+		}
+	}()
+	playlists := NewPlaylists(ctx, commands)
+	playlists.SaveTo(file)
+	playlist := Playlist{Name: "office", Repeat: true, Entries: []PlaylistEntry{
+		{Type: TypeDashboard, Name: "clock", Duration: time.Minute},
+	}}
+	if err := playlists.Save(playlist); err != nil {
+		t.Fatal(err)
+	}
+	if err := playlists.Start("office"); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := LoadStateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &savedPlaylist{Name: "office", Repeat: true, Entries: []savedEntry{{Type: "dashboard", Name: "clock", Duration: "1m0s"}}}
+	if !reflect.DeepEqual(saved.saved.Playlist, want) {
+		t.Fatalf("unexpected saved playlist: got %#v, want %#v", saved.saved.Playlist, want)
+	}
+
+	playlists.Stop()
+	saved, err = LoadStateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.saved.Playlist != nil {
+		t.Fatalf("stopped playlist is still saved: %#v", saved.saved.Playlist)
+	}
+}
+
+func TestStateFileRestoresPlaylistAfterShutdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	file, err := LoadStateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commands := make(chan Command)
+	go func() {
+		for command := range commands {
+			command.Result <- nil
+		}
+	}()
+	defer close(commands)
+	playlist := Playlist{Name: "office", Repeat: true, Entries: []PlaylistEntry{
+		{Type: TypeDashboard, Name: "clock", Duration: time.Minute},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	playlists := NewPlaylists(ctx, commands)
+	playlists.SaveTo(file)
+	if err := playlists.Save(playlist); err != nil {
+		t.Fatal(err)
+	}
+	if err := playlists.Start("office"); err != nil {
+		t.Fatal(err)
+	}
+	playlists.mu.Lock()
+	done := playlists.active.done
+	playlists.mu.Unlock()
+	cancel()
+	<-done
+
+	file, err = LoadStateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	restarted := NewPlaylists(ctx, commands)
+	file.Restore(ctx, commands, restarted, nil, Command{Type: TypeBlank})
+	if name, ok := restarted.Active(); !ok || name != "office" {
+		t.Fatalf("playlist was not restored after the shutdown: %q", name)
+	}
+	restarted.Stop()
+}
+
+func TestStateFileWritable(t *testing.T) {
+	dir := t.TempDir()
+	file, err := LoadStateFile(filepath.Join(dir, "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Writable(); err != nil {
+		t.Fatal(err)
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 0 {
+		t.Fatalf("check left files behind: %v %v", entries, err)
+	}
+
+	file, err = LoadStateFile(filepath.Join(dir, "missing", "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Writable(); err == nil {
+		t.Fatal("state file in a missing directory is writable")
+	}
+	// The directory appears later, but the state is not saved any more.
+	if err := os.Mkdir(filepath.Join(dir, "missing"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	file.saveBrightness(40)
+	if _, err := os.Stat(filepath.Join(dir, "missing", "state.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("unwritable state file was saved: %v", err)
+	}
+}
+
+func TestBootCommand(t *testing.T) {
+	var config rgbmatrix.Config
+	config.Boot.Type = "blank"
+	boot, err := BootCommand(config)
+	if err != nil || boot.Type != TypeBlank || boot.Name != "" {
+		t.Fatalf("unexpected boot command: %#v %v", boot, err)
+	}
+
+	config.Boot.Type, config.Boot.Name = "gif-once", "party"
+	if _, err := BootCommand(config); !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
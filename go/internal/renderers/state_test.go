@@ -0,0 +1,156 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestStateTracksTemporaryContentAndErrors(t *testing.T) {
+	state := NewState()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	state.now = func() time.Time { return now }
+
+	if snapshot := state.Snapshot(); !snapshot.StartedAt.IsZero() || snapshot.RevertTo != nil {
+		t.Fatalf("new state is not empty: %#v", snapshot)
+	}
+
+	state.started(Command{Type: TypeGIFOnce, Name: "success", IsTemporary: true}, &Content{Type: TypeAnimation, Name: "plasma"})
+	state.failed(Command{Type: TypeImage, Name: "broken"}, ErrInvalidAsset)
+
+	snapshot := state.Snapshot()
+	if snapshot.Content != (Content{Type: TypeGIFOnce, Name: "success"}) || !snapshot.Temporary || !snapshot.StartedAt.Equal(now) {
+		t.Fatalf("unexpected content: %#v", snapshot)
+	}
+	if snapshot.RevertTo == nil || *snapshot.RevertTo != (Content{Type: TypeAnimation, Name: "plasma"}) {
+		t.Fatalf("unexpected revert target: %#v", snapshot.RevertTo)
+	}
+	if snapshot.LastError == nil || snapshot.LastError.Name != "broken" || snapshot.LastError.Message != ErrInvalidAsset.Error() {
+		t.Fatalf("unexpected last error: %#v", snapshot.LastError)
+	}
+
+	state.started(Command{Type: TypeAnimation, Name: "plasma"}, &Content{Type: TypeAnimation, Name: "plasma"})
+	if snapshot := state.Snapshot(); snapshot.Temporary || snapshot.RevertTo != nil || snapshot.LastError == nil {
+		t.Fatalf("unexpected state after revert: %#v", snapshot)
+	}
+}
+
+func TestStateKeepsRecentErrors(t *testing.T) {
+	state := NewState()
+	for i := range maxRendererErrors + 5 {
+		state.failed(Command{Type: TypeAnimation, Name: string(rune('a' + i))}, ErrUnknownContent)
+	}
+	state.stopped()
+
+	recent := state.Errors()
+	if len(recent) != maxRendererErrors || recent[0].Name != string(rune('a'+maxRendererErrors+4)) {
+		t.Fatalf("unexpected errors: %#v", recent)
+	}
+	if !state.Snapshot().Failed {
+		t.Fatal("stopped renderer is not reported as failed")
+	}
+	state.started(Command{Type: TypeAnimation, Name: "plasma"}, nil)
+	if state.Snapshot().Failed {
+		t.Fatal("started renderer is still reported as failed")
+	}
+}
+
+func TestUpdateLoopPublishesState(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	commands := make(chan Command)
+	state := NewState()
+	go updateLoop(ctx, commands, rgbmatrix.NewMemory(16, 16), LoopOptions{State: state})
+
+	for _, command := range []Command{
+		{Type: TypeAnimation, Name: "plasma"},
+		{Type: TypeAnimation, Name: "ripple", IsTemporary: true},
+	} {
+		if err := sendCommand(commands, command); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshot := state.Snapshot()
+	if snapshot.Content != (Content{Type: TypeAnimation, Name: "ripple"}) || !snapshot.Temporary {
+		t.Fatalf("unexpected content: %#v", snapshot)
+	}
+	if snapshot.RevertTo == nil || snapshot.RevertTo.Name != "plasma" {
+		t.Fatalf("unexpected revert target: %#v", snapshot.RevertTo)
+	}
+
+	if err := sendCommand(commands, Command{Type: TypeAnimation, Name: "unknown"}); !errors.Is(err, ErrUnknownContent) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot := state.Snapshot(); snapshot.LastError == nil || snapshot.Name != "ripple" {
+		t.Fatalf("rejected content changed the state: %#v", snapshot)
+	}
+}
+
+func sendCommand(commands chan Command, command Command) error {
+	command.Result = make(chan error, 1)
+	commands <- command
+	return <-command.Result
+}
+
+// waitForState waits until the display state matches or fails the test
+// after a generous timeout.
+func waitForState(t *testing.T, state *State, matches func(DisplayState) bool) DisplayState {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		changed := state.Changed()
+		if snapshot := state.Snapshot(); matches(snapshot) {
+			return snapshot
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			t.Fatalf("display state did not change as expected: %#v", state.Snapshot())
+		}
+	}
+}
+
+func TestStateChanged(t *testing.T) {
+	state := NewState()
+	changed := state.Changed()
+	select {
+	case <-changed:
+		t.Fatal("state changed without a change")
+	default:
+	}
+	state.started(Command{Type: TypeAnimation, Name: "plasma"}, nil)
+	select {
+	case <-changed:
+	default:
+		t.Fatal("change was not signalled")
+	}
+	if state.Changed() == changed {
+		t.Fatal("signalled channel was not replaced")
+	}
+}
+
+func TestStateLeavesOutExpiredNotifications(t *testing.T) {
+	state := NewState()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	state.now = func() time.Time { return now }
+
+	active := &queuedNotification{Notification: Notification{ID: 1, TTL: time.Second, EnqueuedAt: now}, shown: true}
+	var queue notificationQueue
+	queue.push(&queuedNotification{Notification: Notification{ID: 2, Priority: 1, TTL: time.Minute, EnqueuedAt: now}})
+	queue.push(&queuedNotification{Notification: Notification{ID: 3, EnqueuedAt: now}})
+	queue.push(&queuedNotification{Notification: Notification{ID: 4, TTL: time.Minute, EnqueuedAt: now}, shown: true})
+	state.setNotifications(active, queue)
+
+	now = now.Add(2 * time.Minute)
+	var ids []uint64
+	for _, n := range state.Notifications() {
+		ids = append(ids, n.ID)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 3 || ids[2] != 4 {
+		t.Fatalf("unexpected notifications: %v", ids)
+	}
+}
@@ -0,0 +1,97 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+)
+
+// ErrRendererPanicked is returned for renderers that panicked.
+var ErrRendererPanicked = errors.New("renderer panicked")
+
+// rendererStopTimeout bounds how long a stopped renderer may take to return.
+const rendererStopTimeout = 2 * time.Second
+
+// supervisor runs one renderer at a time. It stops the running renderer and
+// waits until it returned before it starts the next one, so that two
+// renderers never draw at the same time.
+type supervisor struct {
+	// exits receives asynchronous renderers that returned by themselves,
+	// because they finished or failed.
+	exits   chan rendererExit
+	timeout time.Duration
+
+	// run counts the started renderers and identifies the running one.
+	run    uint64
+	cancel context.CancelFunc
+	done   <-chan struct{}
+}
+
+// rendererExit reports an asynchronous renderer that returned.
+type rendererExit struct {
+	run uint64
+	cmd Command
+	err error
+}
+
+func newSupervisor() *supervisor {
+	return &supervisor{exits: make(chan rendererExit), timeout: rendererStopTimeout}
+}
+
+// start stops the running renderer and starts the renderer of cmd. Errors
+// of synchronous renderers are returned.
+func (s *supervisor) start(ctx context.Context, cmd Command, prepared preparedRenderer) error {
+	s.stop()
+	s.run++
+	run := s.run
+	ctx, s.cancel = context.WithCancel(ctx)
+	exited := func(err error) {
+		// Renderers that were stopped are not reported, and nobody waits
+		// for their report any more.
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case s.exits <- rendererExit{run: run, cmd: cmd, err: err}:
+		case <-ctx.Done():
+		}
+	}
+	done, err := prepared.start(ctx, exited)
+	s.done = done
+	return err
+}
+
+// stop stops the running renderer and waits until it returned, or gives up
+// after the timeout.
+func (s *supervisor) stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	select {
+	case <-s.done:
+	case <-time.After(s.timeout):
+		log.Printf("renderer did not stop within %s", s.timeout)
+	}
+	s.cancel, s.done = nil, nil
+}
+
+// running reports whether exit is of the running renderer.
+func (s *supervisor) running(exit rendererExit) bool {
+	return s.cancel != nil && exit.run == s.run
+}
+
+// render runs the renderer and turns a panic into an error, so that a
+// broken renderer cannot take the server down.
+func (p preparedRenderer) render(ctx context.Context) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("renderer panicked: %v\n%s", recovered, debug.Stack())
+			err = fmt.Errorf("%w: %v", ErrRendererPanicked, recovered)
+		}
+	}()
+	return p.renderer.Render(ctx)
+}
@@ -0,0 +1,64 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSupervisorWaitsForStoppedRenderer(t *testing.T) {
+	supervisor := newSupervisor()
+	var stopped atomic.Bool
+	slow := renderFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		stopped.Store(true)
+		return nil
+	})
+	if err := supervisor.start(context.Background(), Command{Name: "slow"}, preparedRenderer{renderer: slow, async: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	next := renderFunc(func(ctx context.Context) error {
+		if !stopped.Load() {
+			t.Error("next renderer started before the previous one stopped")
+		}
+		<-ctx.Done()
+		return nil
+	})
+	if err := supervisor.start(context.Background(), Command{Name: "next"}, preparedRenderer{renderer: next, async: true}); err != nil {
+		t.Fatal(err)
+	}
+	supervisor.stop()
+}
+
+func TestSupervisorRecoversPanics(t *testing.T) {
+	supervisor := newSupervisor()
+	panicking := renderFunc(func(context.Context) error { panic("broken frame") })
+
+	err := supervisor.start(context.Background(), Command{Name: "sync"}, preparedRenderer{renderer: panicking})
+	if !errors.Is(err, ErrRendererPanicked) {
+		t.Fatalf("unexpected error of synchronous renderer: %v", err)
+	}
+
+	if err := supervisor.start(context.Background(), Command{Name: "async"}, preparedRenderer{renderer: panicking, async: true}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case exit := <-supervisor.exits:
+		if !errors.Is(exit.err, ErrRendererPanicked) || exit.cmd.Name != "async" || !supervisor.running(exit) {
+			t.Fatalf("unexpected exit: %#v", exit)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("panicking renderer was not reported")
+	}
+}
+
+// renderFunc is a renderer that calls itself.
+type renderFunc func(ctx context.Context) error
+
+func (f renderFunc) Render(ctx context.Context, _ ...AfterRenderFunc) error {
+	return f(ctx)
+}
@@ -0,0 +1,175 @@
+package renderers
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"image/color"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+// FontsDir contains the BDF fonts that text content can use.
+const FontsDir = "assets/fonts"
+
+type TextAlign int
+
+const (
+	AlignCenter TextAlign = iota
+	AlignLeft
+	AlignRight
+)
+
+type TextMode int
+
+const (
+	TextStatic TextMode = iota
+	TextMarquee
+)
+
+// TextOptions configure how a text message is drawn. Speed is the marquee
+// scroll speed in pixels per second.
+type TextOptions struct {
+	Font       string
+	Color      color.RGBA
+	Background color.RGBA
+	Align      TextAlign
+	Mode       TextMode
+	Speed      float64
+}
+
+func defaultTextOptions() TextOptions {
+	return TextOptions{
+		Font:       "7x14",
+		Color:      color.RGBA{200, 200, 200, 255},
+		Background: color.RGBA{0, 0, 0, 255},
+		Align:      AlignCenter,
+		Mode:       TextStatic,
+		Speed:      30,
+	}
+}
+
+// ParseTextOptions reads text options from command parameters. Missing
+// parameters use their defaults; the font is the name of a BDF file in
+// FontsDir without its extension.
+func ParseTextOptions(params map[string]string) (TextOptions, error) {
+	options := defaultTextOptions()
+	for key, value := range params {
+		var err error
+		switch key {
+		case "font":
+			if value == "" || value != filepath.Base(value) {
+				err = fmt.Errorf("invalid font name")
+			}
+			options.Font = value
+		case "color":
+			options.Color, err = parseHexColor(value)
+		case "background":
+			options.Background, err = parseHexColor(value)
+		case "align":
+			options.Align, err = parseTextAlign(value)
+		case "mode":
+			options.Mode, err = parseTextMode(value)
+		case "speed":
+			options.Speed, err = strconv.ParseFloat(value, 64)
+			if err == nil && (options.Speed < 1 || options.Speed > 500) {
+				err = fmt.Errorf("must be between 1 and 500 pixels per second")
+			}
+		default:
+			return TextOptions{}, fmt.Errorf("%w: unknown text parameter %q", ErrInvalidParameter, key)
+		}
+		if err != nil {
+			return TextOptions{}, fmt.Errorf("%w: text parameter %q: %v", ErrInvalidParameter, key, err)
+		}
+	}
+	return options, nil
+}
+
+func parseHexColor(value string) (color.RGBA, error) {
+	value = strings.TrimPrefix(value, "#")
+	bytes, err := hex.DecodeString(value)
+	if err != nil || len(bytes) != 3 {
+		return color.RGBA{}, fmt.Errorf("%q is not a hex color such as ff8800", value)
+	}
+	return color.RGBA{R: bytes[0], G: bytes[1], B: bytes[2], A: 255}, nil
+}
+
+func parseTextAlign(value string) (TextAlign, error) {
+	switch value {
+	case "center":
+		return AlignCenter, nil
+	case "left":
+		return AlignLeft, nil
+	case "right":
+		return AlignRight, nil
+	default:
+		return 0, fmt.Errorf("%q is not one of left, center or right", value)
+	}
+}
+
+func parseTextMode(value string) (TextMode, error) {
+	switch value {
+	case "static":
+		return TextStatic, nil
+	case "marquee":
+		return TextMarquee, nil
+	default:
+		return 0, fmt.Errorf("%q is not one of static or marquee", value)
+	}
+}
+
+type TextRenderer struct {
+	screen  *rgbmatrix.Screen
+	font    *rgbmatrix.BDFFont
+	message string
+	options TextOptions
+	paced
+}
+
+func Text(screen *rgbmatrix.Screen, message string, options TextOptions) (*TextRenderer, error) {
+	font, err := rgbmatrix.LoadBDF(filepath.Join(FontsDir, options.Font+".bdf"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: load font %q: %v", ErrInvalidAsset, options.Font, err)
+	}
+	return &TextRenderer{screen: screen, font: font, message: message, options: options}, nil
+}
+
+func (r *TextRenderer) Render(ctx context.Context, _ ...AfterRenderFunc) error {
+	if r.options.Mode == TextStatic {
+		return r.draw(r.alignedX())
+	}
+
+	width := float64(r.screen.Canvas.Bounds().Dx())
+	textWidth := float64(r.font.TextWidth(r.message))
+	return r.run(ctx, func(t time.Duration) error {
+		// The message enters from the right edge and leaves on the left
+		// before it starts over.
+		distance := t.Seconds() * r.options.Speed
+		x := width - math.Mod(distance, width+textWidth)
+		return r.draw(int(x))
+	})
+}
+
+func (r *TextRenderer) alignedX() int {
+	free := r.screen.Canvas.Bounds().Dx() - r.font.TextWidth(r.message)
+	switch r.options.Align {
+	case AlignLeft:
+		return 0
+	case AlignRight:
+		return free
+	default:
+		return free / 2
+	}
+}
+
+func (r *TextRenderer) draw(x int) error {
+	y := (r.screen.Canvas.Bounds().Dy() - r.font.Height()) / 2
+	r.screen.Fill(r.options.Background)
+	r.screen.DrawText(r.font, r.message, x, y, r.options.Color)
+	return r.screen.Canvas.Render()
+}
@@ -0,0 +1,96 @@
+package renderers
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestParseTextOptions(t *testing.T) {
+	options, err := ParseTextOptions(map[string]string{
+		"font": "5x7", "color": "#ff8800", "align": "left", "mode": "marquee", "speed": "60",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if options.Font != "5x7" || options.Color != (color.RGBA{R: 255, G: 136, A: 255}) ||
+		options.Align != AlignLeft || options.Mode != TextMarquee || options.Speed != 60 {
+		t.Fatalf("unexpected options: %#v", options)
+	}
+	if options.Background != defaultTextOptions().Background {
+		t.Fatalf("background did not default: %v", options.Background)
+	}
+
+	for _, params := range []map[string]string{
+		{"color": "orange"},
+		{"align": "justify"},
+		{"mode": "blink"},
+		{"speed": "0"},
+		{"font": "../secret"},
+		{"size": "12"},
+	} {
+		if _, err := ParseTextOptions(params); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("%v: unexpected error: %v", params, err)
+		}
+	}
+}
+
+func TestStaticTextIsDrawnAligned(t *testing.T) {
+	font, err := rgbmatrix.LoadBDF("../../../assets/fonts/5x7.bdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrix := newRecordingMatrix(32, 8)
+	options := defaultTextOptions()
+	options.Align = AlignRight
+	renderer := &TextRenderer{screen: rgbmatrix.NewScreen(matrix), font: font, message: "Hi", options: options}
+
+	if err := renderer.Render(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	left, right := matrix.litColumns()
+	if left < 32-font.TextWidth("Hi") || right >= 32 {
+		t.Fatalf("text is not right aligned: columns %d..%d", left, right)
+	}
+}
+
+// recordingMatrix keeps the last rendered frame, like a physical matrix
+// keeps showing it.
+type recordingMatrix struct {
+	*prepareMatrix
+	rendered []color.Color
+}
+
+func newRecordingMatrix(width, height int) *recordingMatrix {
+	return &recordingMatrix{prepareMatrix: newPrepareMatrix(width, height)}
+}
+
+func (m *recordingMatrix) Render() error {
+	m.rendered = append(m.rendered[:0], m.pixels...)
+	clear(m.pixels)
+	return nil
+}
+
+func (m *recordingMatrix) Present(img *image.RGBA) error {
+	m.read(img)
+	return m.Render()
+}
+
+func (m *recordingMatrix) litColumns() (left, right int) {
+	left, right = m.width, -1
+	for position, pixel := range m.rendered {
+		if pixel == nil {
+			continue
+		}
+		if r, g, b, _ := pixel.RGBA(); r+g+b == 0 {
+			continue
+		}
+		x := position % m.width
+		left, right = min(left, x), max(right, x)
+	}
+	return left, right
+}
@@ -2,72 +2,362 @@ package renderers
 
 import (
 	"context"
+	"image/color"
 	"log"
 	"math"
 	"time"
 
-	"github.com/fogleman/gg"
-	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/display"
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
 )
 
-func updateLoop(ctx context.Context, commands chan Command, m rgbmatrix.Matrix, frames *display.Hub) {
-	if frames != nil {
-		m = rgbmatrix.NewObservable(m, frames.Publish)
+// LoopOptions configure the update loop.
+type LoopOptions struct {
+	// State receives what the loop shows. A private state is used when it
+	// is nil.
+	State *State
+	// Config is the loaded config. Dashboards take their font and service
+	// settings from it, and the loop takes the default transition, the
+	// palette, the frame rate and the layouts from it.
+	Config rgbmatrix.Config
+	// Reloads replace the config, see Reloader.
+	Reloads <-chan Reload
+	// Withdrawals remove the notification whose command has the received
+	// Finished channel, see Sequences.
+	Withdrawals <-chan chan struct{}
+	// StateFile saves every persistent command, if it is not nil.
+	StateFile *StateFile
+	// Overlays are closed on shutdown before the splash is shown, if they
+	// are not nil.
+	Overlays *Overlays
+}
+
+func updateLoop(ctx context.Context, commands chan Command, m rgbmatrix.Matrix, options LoopOptions) {
+	state := options.State
+	if state == nil {
+		state = NewState()
 	}
-	s := rgbmatrix.NewScreen(m)
-	defer s.Close()
+	transitions := rgbmatrix.NewTransitions(m)
+	s := rgbmatrix.NewScreen(transitions)
 	width, height := m.Geometry()
-	log.Printf("renderer loop started: matrix=%dx%d streaming=%t", width, height, frames != nil)
-
-	go func() { commands <- Command{Type: TypeImage, Name: "autodarts"} }()
-	//go func() { commands <- Command{Type: TypeDashboard, Name: dashboard.Shopify.String()} }()
-
-	renderCtx, cancel := context.WithCancel(ctx)
-	defer cancel()
+	log.Printf("renderer loop started: matrix=%dx%d", width, height)
 
-	var lastCommand Command
-	resetScreen := func() { commands <- lastCommand }
+	loop := &displayLoop{
+		ctx:         ctx,
+		screen:      s,
+		transitions: transitions,
+		options:     options,
+		palette:     displayPalette(options.Config),
+		state:       state,
+		renderer:    newSupervisor(),
+		now:         time.Now,
+	}
 
 	for {
 		select {
 		case <-ctx.Done():
 			log.Printf("renderer loop stopping")
-			cancel()
+			loop.renderer.stop()
+			loop.shutdown()
 			return
 		case cmd := <-commands:
-			log.Printf("renderer requested: type=%s name=%q temporary=%t", cmd.Type, cmd.Name, cmd.IsTemporary)
-			prepareCtx := cmd.Context
-			if prepareCtx == nil {
-				prepareCtx = ctx
-			}
-			prepared, err := prepare(prepareCtx, cmd, s)
-			if err != nil {
-				log.Printf("renderer rejected: type=%s name=%q error=%v", cmd.Type, cmd.Name, err)
-				respond(cmd, err)
-				continue
-			}
-
-			cancel()
-			renderCtx, cancel = context.WithCancel(ctx)
-			callbacks := []AfterRenderFunc(nil)
-			if cmd.IsTemporary {
-				callbacks = append(callbacks, resetScreen)
-			}
-			if err := prepared.start(renderCtx, callbacks...); err != nil {
-				log.Printf("renderer failed to start: type=%s name=%q error=%v", cmd.Type, cmd.Name, err)
-				respond(cmd, err)
-				continue
-			}
-			if !cmd.IsTemporary {
-				lastCommand = cmd
-				lastCommand.Context = nil
-				lastCommand.Result = nil
-			}
-			log.Printf("renderer started: type=%s name=%q temporary=%t", cmd.Type, cmd.Name, cmd.IsTemporary)
-			respond(cmd, nil)
+			loop.handle(cmd)
+		case reload := <-options.Reloads:
+			loop.reload(reload)
+		case finished := <-options.Withdrawals:
+			loop.withdraw(finished)
+		case exit := <-loop.renderer.exits:
+			loop.exited(exit)
+		case <-loop.timeout():
+			loop.finish(loop.active.ID)
+		}
+	}
+}
+
+// displayLoop is the state of the update loop. The persistent renderer is
+// shown whenever no notification is active.
+type displayLoop struct {
+	ctx         context.Context
+	screen      *rgbmatrix.Screen
+	transitions *rgbmatrix.Transitions
+	options     LoopOptions
+	palette     string
+	state       *State
+	renderer    *supervisor
+
+	persistent *Command
+	active     *queuedNotification
+	queue      notificationQueue
+	timer      *time.Timer
+	nextID     uint64
+	now        func() time.Time
+}
+
+func (l *displayLoop) handle(cmd Command) {
+	log.Printf("renderer requested: type=%s name=%q temporary=%t", cmd.Type, cmd.Name, cmd.IsTemporary)
+	prepareCtx := cmd.Context
+	if prepareCtx == nil {
+		prepareCtx = l.ctx
+	}
+	prepared, err := l.prepare(prepareCtx, cmd)
+	if err != nil {
+		log.Printf("renderer rejected: type=%s name=%q error=%v", cmd.Type, cmd.Name, err)
+		l.state.failed(cmd, err)
+		respond(cmd, err)
+		return
+	}
+
+	if cmd.IsTemporary {
+		respond(cmd, l.enqueue(cmd, prepared))
+		return
+	}
+
+	stored := cmd
+	stored.Context = nil
+	stored.Result = nil
+	if l.active != nil {
+		// The renderer is prepared again when the queue drained.
+		l.persistent = &stored
+		l.options.StateFile.saveDisplay(stored)
+		l.state.reverting(Content{Type: cmd.Type, Name: cmd.Name})
+		log.Printf("renderer deferred until notifications finished: type=%s name=%q", cmd.Type, cmd.Name)
+		respond(cmd, nil)
+		return
+	}
+	if err := l.start(cmd, prepared); err != nil {
+		respond(cmd, err)
+		return
+	}
+	l.persistent = &stored
+	l.options.StateFile.saveDisplay(stored)
+	respond(cmd, nil)
+}
+
+// enqueue queues a notification and shows it right away if nothing with the
+// same or a higher priority is active.
+func (l *displayLoop) enqueue(cmd Command, prepared preparedRenderer) error {
+	l.nextID++
+	n := newQueuedNotification(l.nextID, cmd, prepared, l.now())
+	if cmd.Queued != nil {
+		select {
+		case cmd.Queued <- n.ID:
+		default:
+		}
+	}
+	if l.active != nil && n.Priority <= l.active.Priority {
+		l.queue.push(n)
+		log.Printf("notification queued: id=%d type=%s name=%q priority=%d waiting=%d", n.ID, n.Type, n.Name, n.Priority, len(l.queue))
+		l.state.setNotifications(l.active, l.queue)
+		return nil
+	}
+
+	if l.active != nil {
+		l.preempt()
+	}
+	if err := l.show(n); err != nil {
+		n.end()
+		l.showNext()
+		return err
+	}
+	return nil
+}
+
+// preempt moves the active notification back into the queue with the
+// display time it has left.
+func (l *displayLoop) preempt() {
+	active := l.active
+	l.active = nil
+	l.stopTimer()
+	log.Printf("notification preempted: id=%d type=%s name=%q", active.ID, active.Type, active.Name)
+	if active.remaining > 0 {
+		active.remaining -= l.now().Sub(active.startedAt)
+		if active.remaining <= 0 {
+			active.end()
+			return
+		}
+	}
+	l.queue.push(active)
+}
+
+func (l *displayLoop) show(n *queuedNotification) error {
+	if err := l.start(n.cmd, n.prepared); err != nil {
+		return err
+	}
+	n.shown = true
+	n.startedAt = l.now()
+	l.active = n
+	if n.remaining > 0 {
+		l.timer = time.NewTimer(n.remaining)
+	}
+	l.state.setNotifications(l.active, l.queue)
+	return nil
+}
+
+// finish ends the notification with the given ID if it is still active.
+func (l *displayLoop) finish(id uint64) {
+	if l.active == nil || l.active.ID != id {
+		return
+	}
+	log.Printf("notification finished: id=%d type=%s name=%q", id, l.active.Type, l.active.Name)
+	l.active.end()
+	l.active = nil
+	l.stopTimer()
+	l.showNext()
+}
+
+// withdraw removes the notification that closes finished when it ended,
+// whether it is shown or waiting.
+func (l *displayLoop) withdraw(finished chan struct{}) {
+	if finished == nil {
+		return
+	}
+	if l.active != nil && l.active.cmd.Finished == finished {
+		log.Printf("notification withdrawn: id=%d type=%s name=%q", l.active.ID, l.active.Type, l.active.Name)
+		l.finish(l.active.ID)
+		return
+	}
+	if n := l.queue.remove(finished); n != nil {
+		log.Printf("notification withdrawn: id=%d type=%s name=%q", n.ID, n.Type, n.Name)
+		n.end()
+		l.state.setNotifications(l.active, l.queue)
+	}
+}
+
+// showNext shows the next queued notification or, when the queue drained,
+// restores the persistent renderer.
+func (l *displayLoop) showNext() {
+	for {
+		n, expired := l.queue.pop(l.now())
+		for _, e := range expired {
+			log.Printf("notification expired: id=%d type=%s name=%q", e.ID, e.Type, e.Name)
+			e.end()
+		}
+		if n == nil {
+			break
+		}
+		if l.show(n) == nil {
+			return
+		}
+		n.end()
+	}
+	l.state.setNotifications(nil, nil)
+	_ = l.restore()
+}
+
+func (l *displayLoop) restore() error {
+	if l.persistent == nil {
+		l.renderer.stop()
+		_ = l.screen.Clear()
+		l.state.idle()
+		return nil
+	}
+	cmd := *l.persistent
+	prepared, err := l.prepare(l.ctx, cmd)
+	if err != nil {
+		log.Printf("renderer could not be restored: type=%s name=%q error=%v", cmd.Type, cmd.Name, err)
+		l.state.failed(cmd, err)
+		return err
+	}
+	return l.start(cmd, prepared)
+}
+
+// prepare prepares the renderer of a command. Layouts draw into the
+// transitions matrix through a compositor instead of using the screen.
+// Every other renderer gets a screen of its own, so that a renderer that is
+// still finishing its last frame does not draw into the back buffer of the
+// next one.
+func (l *displayLoop) prepare(ctx context.Context, cmd Command) (preparedRenderer, error) {
+	if cmd.Type == TypeLayout {
+		return prepareLayout(ctx, cmd, l.transitions, l.options.Config, l.palette)
+	}
+	prepared, err := prepare(ctx, withPalette(cmd, l.palette), rgbmatrix.NewScreen(l.transitions), l.options.Config)
+	if err != nil {
+		return preparedRenderer{}, err
+	}
+	setFPS(prepared.renderer, l.options.Config.Display.FPS)
+	return prepared, nil
+}
+
+// start replaces the running renderer, blending its first frames with the
+// frame that is shown.
+func (l *displayLoop) start(cmd Command, prepared preparedRenderer) error {
+	l.renderer.stop()
+	style := cmd.Transition
+	if style == rgbmatrix.TransitionDefault {
+		style = l.options.Config.Display.Transition
+	}
+	l.transitions.Begin(style, time.Duration(l.options.Config.Display.TransitionDuration))
+
+	if err := l.renderer.start(l.ctx, cmd, prepared); err != nil {
+		log.Printf("renderer failed to start: type=%s name=%q error=%v", cmd.Type, cmd.Name, err)
+		l.state.failed(cmd, err)
+		return err
+	}
+
+	var revertTo *Content
+	if l.persistent != nil {
+		revertTo = &Content{Type: l.persistent.Type, Name: l.persistent.Name}
+	}
+	l.state.started(cmd, revertTo)
+	log.Printf("renderer started: type=%s name=%q temporary=%t", cmd.Type, cmd.Name, cmd.IsTemporary)
+	return nil
+}
+
+// exited handles an asynchronous renderer that returned by itself. Its
+// error is recorded. A notification whose renderer returned is finished,
+// while a persistent renderer that failed leaves its last frame and is
+// reported as failed.
+func (l *displayLoop) exited(exit rendererExit) {
+	if exit.err != nil {
+		log.Printf("renderer stopped with error: type=%s name=%q error=%v", exit.cmd.Type, exit.cmd.Name, exit.err)
+		l.state.failed(exit.cmd, exit.err)
+	}
+	if !l.renderer.running(exit) {
+		return
+	}
+	if l.active != nil {
+		l.finish(l.active.ID)
+		return
+	}
+	if exit.err != nil {
+		l.state.stopped()
+	}
+}
+
+// shutdown removes the overlays, shows the shutdown splash of the config
+// for its duration and then blanks the matrix. The matrix is left open for
+// the caller to close.
+func (l *displayLoop) shutdown() {
+	if err := l.options.Overlays.Close(); err != nil {
+		log.Printf("overlays could not be removed: %v", err)
+	}
+	shutdown := l.options.Config.Shutdown
+	if shutdown.Splash != "" {
+		l.transitions.Begin(rgbmatrix.TransitionNone, 0)
+		renderer, err := Image(rgbmatrix.NewScreen(l.transitions), shutdown.Splash)
+		if err == nil {
+			err = renderer.Render(context.Background())
+		}
+		if err != nil {
+			log.Printf("shutdown splash is not shown: %v", err)
+		} else {
+			time.Sleep(time.Duration(shutdown.SplashDuration))
 		}
 	}
+	_ = l.screen.Clear()
+}
+
+func (l *displayLoop) timeout() <-chan time.Time {
+	if l.timer == nil {
+		return nil
+	}
+	return l.timer.C
+}
+
+func (l *displayLoop) stopTimer() {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
 }
 
 func respond(cmd Command, err error) {
@@ -80,12 +370,13 @@ func respond(cmd Command, err error) {
 	}
 }
 
-func UpdateLoopWithMatrix(ctx context.Context, commands chan Command, matrix rgbmatrix.Matrix, frames *display.Hub) {
-	updateLoop(ctx, commands, matrix, frames)
+func UpdateLoopWithMatrix(ctx context.Context, commands chan Command, matrix rgbmatrix.Matrix, options LoopOptions) {
+	updateLoop(ctx, commands, matrix, options)
 }
 
 type SoftBloomRingsRenderer struct {
 	screen *rgbmatrix.Screen
+	paced
 }
 
 func SoftBloomRings(screen *rgbmatrix.Screen) *SoftBloomRingsRenderer {
@@ -93,50 +384,42 @@ func SoftBloomRings(screen *rgbmatrix.Screen) *SoftBloomRingsRenderer {
 }
 
 func (r *SoftBloomRingsRenderer) Render(ctx context.Context, cb ...AfterRenderFunc) error {
-	dc := gg.NewContextForImage(r.screen.Canvas)
-	w := float64(dc.Width())
-	h := float64(dc.Height())
-	cx, cy := w/2, h/2
-	start := time.Now()
+	frame := r.screen.Frame()
+	shader := softBloomRings(frame.Bounds().Dx(), frame.Bounds().Dy())
+	return r.run(ctx, func(elapsed time.Duration) error {
+		shade(frame, elapsed.Seconds(), shader)
+		return r.screen.Present()
+	})
+}
+
+// softBloomRings draws rings that grow from the center of a frame of the
+// given size on black.
+func softBloomRings(width, height int) Shader {
+	cx, cy := float64(width)/2, float64(height)/2
 
-	const maxRings = 5
 	const ringSpacing = 10.0
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			t := time.Since(start).Seconds()
-			dc.Clear()
-
-			for y := 0.0; y < h; y++ {
-				for x := 0.0; x < w; x++ {
-					dx, dy := x-cx, y-cy
-					dist := math.Hypot(dx, dy)
-
-					// Create multiple rings using modulus
-					progress := dist - t*20
-					ringPhase := math.Mod(progress, ringSpacing)
-
-					if ringPhase < 2.5 { // threshold for thickness
-						hue := math.Mod(0.6+dist*0.01+t*0.1, 1.0)
-						brightness := 1.0 - (ringPhase / 2.5)
-						brightness *= 0.8
-
-						r, g, b := hsvToRGB(hue, 1.0, brightness)
-						r = math.Max(r, 0.15)
-						g = math.Max(g, 0.15)
-						b = math.Max(b, 0.15)
-
-						dc.SetRGB(r, g, b)
-						dc.SetPixel(int(x), int(y))
-					}
-				}
-			}
-
-			r.screen.ShowImage(ctx, dc.Image())
-			time.Sleep(30 * time.Millisecond)
+	return func(px, py int, t float64) color.RGBA {
+		x, y := float64(px), float64(py)
+		dx, dy := x-cx, y-cy
+		dist := math.Hypot(dx, dy)
+
+		// Create multiple rings using modulus
+		progress := dist - t*20
+		ringPhase := math.Mod(progress, ringSpacing)
+
+		if ringPhase >= 2.5 { // threshold for thickness
+			return color.RGBA{A: 255}
 		}
+		hue := math.Mod(0.6+dist*0.01+t*0.1, 1.0)
+		brightness := 1.0 - (ringPhase / 2.5)
+		brightness *= 0.8
+
+		red, green, blue := hsvToRGB(hue, 1.0, brightness)
+		red = math.Max(red, 0.15)
+		green = math.Max(green, 0.15)
+		blue = math.Max(blue, 0.15)
+
+		return rgb(red, green, blue)
 	}
 }
@@ -0,0 +1,100 @@
+package renderers
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/rgbmatrix"
+)
+
+func TestUpdateLoopStopsRendererAndBlanksMatrix(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	commands := make(chan Command)
+	matrix := &signallingMatrix{Matrix: rgbmatrix.NewMemory(16, 16), presented: make(chan struct{}, 1)}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		updateLoop(ctx, commands, matrix, LoopOptions{})
+	}()
+	if err := sendCommand(commands, Command{Type: TypeAnimation, Name: "plasma"}); err != nil {
+		t.Fatal(err)
+	}
+	<-matrix.presented
+
+	// The update loop returns once the supervisor waited for the renderer
+	// to return, so that the blank frame is the last one.
+	cancel()
+	<-stopped
+	for position := range 16 * 16 {
+		if r, g, b, _ := matrix.At(position).RGBA(); r != 0 || g != 0 || b != 0 {
+			t.Fatalf("pixel %d is not blank: %v", position, matrix.At(position))
+		}
+	}
+	if matrix.closed.Load() {
+		t.Fatal("update loop closed the matrix")
+	}
+}
+
+func TestUpdateLoopRemovesOverlaysOnShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	layers := rgbmatrix.NewLayers(rgbmatrix.NewMemory(32, 8))
+	overlays := NewOverlays(layers, t.TempDir(), repositoryFonts)
+	if _, err := overlays.Set(Overlay{ID: "live", Type: OverlayText, Content: "LIVE", Params: map[string]string{"color": "ff0000"}}); err != nil {
+		t.Fatal(err)
+	}
+	ran := make(chan struct{})
+	go func() {
+		defer close(ran)
+		overlays.Run(ctx)
+	}()
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		updateLoop(ctx, make(chan Command), layers, LoopOptions{Overlays: overlays})
+	}()
+
+	cancel()
+	<-stopped
+	<-ran
+	for position := range 32 * 8 {
+		if r, g, b, _ := layers.At(position).RGBA(); r != 0 || g != 0 || b != 0 {
+			t.Fatalf("pixel %d is not blank: %v", position, layers.At(position))
+		}
+	}
+	if _, err := overlays.Set(Overlay{ID: "late", Type: OverlayText, Content: "LATE"}); err == nil {
+		t.Fatal("overlay was set after the shutdown")
+	}
+}
+
+// signallingMatrix signals the frames presented to a matrix on presented,
+// if it is not nil.
+type signallingMatrix struct {
+	rgbmatrix.Matrix
+	presented chan struct{}
+	closed    atomic.Bool
+}
+
+func (m *signallingMatrix) Present(img *image.RGBA) error {
+	m.signal()
+	return m.Matrix.Present(img)
+}
+
+func (m *signallingMatrix) Set(position int, c color.Color) {
+	m.signal()
+	m.Matrix.Set(position, c)
+}
+
+func (m *signallingMatrix) signal() {
+	select {
+	case m.presented <- struct{}{}:
+	default:
+	}
+}
+
+func (m *signallingMatrix) Close() error {
+	m.closed.Store(true)
+	return m.Matrix.Close()
+}
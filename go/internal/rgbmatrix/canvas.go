@@ -7,10 +7,12 @@ import (
 )
 
 // Canvas is a image.Image representation of a WS281x matrix, it implements
-// image.Image interface and can be used with draw.Draw for example
+// image.Image interface and can be used with draw.Draw for example. Drawing
+// goes into an RGBA back buffer that Render presents to the matrix at once.
 type Canvas struct {
 	w, h   int
 	m      Matrix
+	frame  *image.RGBA
 	closed bool
 }
 
@@ -19,15 +21,23 @@ type Canvas struct {
 func NewCanvas(m Matrix) *Canvas {
 	w, h := m.Geometry()
 	return &Canvas{
-		w: w,
-		h: h,
-		m: m,
+		w:     w,
+		h:     h,
+		m:     m,
+		frame: image.NewRGBA(image.Rect(0, 0, w, h)),
 	}
 }
 
-// Render update the display with the data from the LED buffer
+// Image returns the back buffer of the canvas. Drawing into it directly is
+// the same as drawing into the canvas, only faster.
+func (c *Canvas) Image() *image.RGBA {
+	return c.frame
+}
+
+// Render presents the back buffer on the matrix and clears it
 func (c *Canvas) Render() error {
-	return c.m.Render()
+	defer clear(c.frame.Pix)
+	return c.m.Present(c.frame)
 }
 
 // ColorModel returns the canvas' color model, always color.RGBAModel
@@ -42,22 +52,18 @@ func (c *Canvas) Bounds() image.Rectangle {
 
 // At returns the color of the pixel at (x, y)
 func (c *Canvas) At(x, y int) color.Color {
-	return c.m.At(c.position(x, y))
+	return c.frame.At(x, y)
 }
 
 // Set LED at position x,y to the provided 24-bit color value
 func (c *Canvas) Set(x, y int, color color.Color) {
-	c.m.Set(c.position(x, y), color)
-}
-
-func (c *Canvas) position(x, y int) int {
-	return x + (y * c.w)
+	c.frame.Set(x, y, color)
 }
 
 // Clear set all the leds on the matrix with color.Black
 func (c *Canvas) Clear() error {
-	draw.Draw(c, c.Bounds(), &image.Uniform{color.Black}, image.Point{}, draw.Src)
-	return c.m.Render()
+	draw.Draw(c.frame, c.Bounds(), &image.Uniform{color.Black}, image.Point{}, draw.Src)
+	return c.Render()
 }
 
 // Close clears the matrix and close the matrix
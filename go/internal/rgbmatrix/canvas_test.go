@@ -1,6 +1,7 @@
 package rgbmatrix
 
 import (
+	"image"
 	"image/color"
 	"testing"
 
@@ -22,10 +23,13 @@ func (s *CanvasSuite) TestNewCanvas(c *C) {
 
 func (s *CanvasSuite) TestRender(c *C) {
 	m := NewMatrixMock()
-	canvas := &Canvas{m: m}
+	canvas := NewCanvas(m)
+	canvas.Set(5, 15, color.White)
 	canvas.Render()
 
-	c.Assert(m.called["Render"], Equals, true)
+	c.Assert(m.called["Present"], Equals, true)
+	c.Assert(m.colors[5+15*64], Equals, color.Color(color.RGBA{255, 255, 255, 255}))
+	c.Assert(canvas.At(5, 15), Equals, color.Color(color.RGBA{}))
 }
 
 func (s *CanvasSuite) TestColorModel(c *C) {
@@ -47,46 +51,48 @@ func (s *CanvasSuite) TestBounds(c *C) {
 
 func (s *CanvasSuite) TestAt(c *C) {
 	m := NewMatrixMock()
-	canvas := &Canvas{w: 10, h: 20, m: m}
-	canvas.At(5, 15)
+	canvas := NewCanvas(m)
+	canvas.Set(5, 15, color.White)
 
-	c.Assert(m.called["At"], Equals, 155)
+	c.Assert(canvas.At(5, 15), Equals, color.Color(color.RGBA{255, 255, 255, 255}))
+	c.Assert(m.called["At"], IsNil)
 }
 
 func (s *CanvasSuite) TestSet(c *C) {
 	m := NewMatrixMock()
-	canvas := &Canvas{w: 10, h: 20, m: m}
+	canvas := NewCanvas(m)
 	canvas.Set(5, 15, color.White)
 
-	c.Assert(m.called["Set"], Equals, 155)
-	c.Assert(m.colors[155], Equals, color.White)
+	c.Assert(m.called["Set"], IsNil)
+	c.Assert(canvas.Image().RGBAAt(5, 15), Equals, color.RGBA{255, 255, 255, 255})
 }
 
 func (s *CanvasSuite) TestClear(c *C) {
 	m := NewMatrixMock()
 
-	canvas := &Canvas{w: 10, h: 20, m: m}
+	canvas := NewCanvas(m)
 	err := canvas.Clear()
 	c.Assert(err, IsNil)
 
 	for _, px := range m.colors {
-		c.Assert(px, Equals, color.Black)
+		c.Assert(px, Equals, color.Color(color.RGBA{0, 0, 0, 255}))
 	}
 
-	c.Assert(m.called["Render"], Equals, true)
+	c.Assert(m.called["Present"], Equals, true)
 }
 
 func (s *CanvasSuite) TestClose(c *C) {
 	m := NewMatrixMock()
-	canvas := &Canvas{w: 10, h: 20, m: m}
+	canvas := NewCanvas(m)
 	err := canvas.Close()
 	c.Assert(err, IsNil)
 
 	for _, px := range m.colors {
-		c.Assert(px, Equals, color.Black)
+		c.Assert(px, Equals, color.Color(color.RGBA{0, 0, 0, 255}))
 	}
 
-	c.Assert(m.called["Render"], Equals, true)
+	c.Assert(m.called["Present"], Equals, true)
+	c.Assert(m.called["Close"], Equals, true)
 }
 
 type MatrixMock struct {
@@ -97,7 +103,7 @@ type MatrixMock struct {
 func NewMatrixMock() *MatrixMock {
 	return &MatrixMock{
 		called: make(map[string]interface{}, 0),
-		colors: make([]color.Color, 200),
+		colors: make([]color.Color, 64*32),
 	}
 }
 
@@ -133,6 +139,14 @@ func (m *MatrixMock) Render() error {
 	return nil
 }
 
+func (m *MatrixMock) Present(img *image.RGBA) error {
+	m.called["Present"] = true
+	for position := range m.colors {
+		m.colors[position] = img.At(position%64, position/64)
+	}
+	return nil
+}
+
 func (m *MatrixMock) Close() error {
 	m.called["Close"] = true
 	return nil
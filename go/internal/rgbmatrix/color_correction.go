@@ -0,0 +1,414 @@
+package rgbmatrix
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LUTsDir contains the .cube files that color corrections can use.
+const LUTsDir = "assets/luts"
+
+var ErrInvalidColorCorrection = errors.New("invalid color correction")
+
+// Channels holds a value for each color channel.
+type Channels struct {
+	Red   float64 `toml:"red" json:"red"`
+	Green float64 `toml:"green" json:"green"`
+	Blue  float64 `toml:"blue" json:"blue"`
+}
+
+// ColorCorrection calibrates the colors sent to the panels. Colors are
+// looked up in the LUT first, then the gamma curve, the color temperature,
+// the gain and the offset are applied to each channel.
+type ColorCorrection struct {
+	// Gamma raises channels from 0 to 1 to its power, so values above 1
+	// darken the mid tones that look washed out on LED panels.
+	Gamma float64 `toml:"gamma" json:"gamma"`
+	// Gain scales each channel, from 0 to 4.
+	Gain Channels `toml:"gain" json:"gain"`
+	// Offset is added to each channel, from -1 to 1 of the full range.
+	Offset Channels `toml:"offset" json:"offset"`
+	// Temperature is the white point in kelvin, from 1000 to 40000. 6500 is
+	// neutral, lower values are warmer and higher values cooler.
+	Temperature int `toml:"temperature" json:"temperature"`
+	// LUT is the name of a 3D LUT in the .cube format in LUTsDir.
+	LUT string `toml:"lut" json:"lut"`
+}
+
+// DefaultColorCorrection leaves colors unchanged.
+var DefaultColorCorrection = ColorCorrection{
+	Gamma:       1,
+	Gain:        Channels{Red: 1, Green: 1, Blue: 1},
+	Temperature: 6500,
+}
+
+// Validate checks that the values are within their ranges. It does not load
+// the LUT.
+func (c ColorCorrection) Validate() error {
+	if c.Gamma < 0.1 || c.Gamma > 5 {
+		return fmt.Errorf("%w: gamma %g is not between 0.1 and 5", ErrInvalidColorCorrection, c.Gamma)
+	}
+	for _, channel := range []struct {
+		name         string
+		gain, offset float64
+	}{
+		{"red", c.Gain.Red, c.Offset.Red},
+		{"green", c.Gain.Green, c.Offset.Green},
+		{"blue", c.Gain.Blue, c.Offset.Blue},
+	} {
+		if channel.gain < 0 || channel.gain > 4 {
+			return fmt.Errorf("%w: %s gain %g is not between 0 and 4", ErrInvalidColorCorrection, channel.name, channel.gain)
+		}
+		if channel.offset < -1 || channel.offset > 1 {
+			return fmt.Errorf("%w: %s offset %g is not between -1 and 1", ErrInvalidColorCorrection, channel.name, channel.offset)
+		}
+	}
+	if c.Temperature < 1000 || c.Temperature > 40000 {
+		return fmt.Errorf("%w: temperature %d is not between 1000 and 40000", ErrInvalidColorCorrection, c.Temperature)
+	}
+	if c.LUT != "" && (filepath.Base(c.LUT) != c.LUT || strings.HasPrefix(c.LUT, ".")) {
+		return fmt.Errorf("%w: lut %q is not a file name", ErrInvalidColorCorrection, c.LUT)
+	}
+	return nil
+}
+
+// curves returns the lookup table of every channel for everything but the
+// LUT.
+func (c ColorCorrection) curves() [3][256]uint8 {
+	white := whitePoint(float64(c.Temperature))
+	neutral := whitePoint(6500)
+	gains := [3]float64{
+		c.Gain.Red * white[0] / neutral[0],
+		c.Gain.Green * white[1] / neutral[1],
+		c.Gain.Blue * white[2] / neutral[2],
+	}
+	offsets := [3]float64{c.Offset.Red, c.Offset.Green, c.Offset.Blue}
+
+	var curves [3][256]uint8
+	for channel := range curves {
+		for value := range curves[channel] {
+			v := math.Pow(float64(value)/255, c.Gamma)*gains[channel] + offsets[channel]
+			curves[channel][value] = uint8(math.Round(math.Max(0, math.Min(v, 1)) * 255))
+		}
+	}
+	return curves
+}
+
+// whitePoint approximates the color of a black body at the temperature in
+// kelvin, with channels from 0 to 1.
+func whitePoint(kelvin float64) [3]float64 {
+	t := kelvin / 100
+	var r, g, b float64
+	if t <= 66 {
+		r = 255
+		g = 99.4708025861*math.Log(t) - 161.1195681661
+	} else {
+		r = 329.698727446 * math.Pow(t-60, -0.1332047592)
+		g = 288.1221695283 * math.Pow(t-60, -0.0755148492)
+	}
+	switch {
+	case t >= 66:
+		b = 255
+	case t <= 19:
+		b = 0
+	default:
+		b = 138.5177312231*math.Log(t-10) - 305.0447927307
+	}
+	channel := func(value float64) float64 {
+		return math.Max(0, math.Min(value, 255)) / 255
+	}
+	return [3]float64{channel(r), channel(g), channel(b)}
+}
+
+// LUT is a 3D color lookup table.
+type LUT struct {
+	size int
+	// table holds size³ colors with red changing fastest, then green.
+	table [][3]float64
+}
+
+// LoadLUT reads a 3D LUT in the .cube format.
+func LoadLUT(path string) (*LUT, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	lut := &LUT{}
+	domainMin, domainMax := [3]float64{0, 0, 0}, [3]float64{1, 1, 1}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case "TITLE":
+			continue
+		case "LUT_1D_SIZE":
+			return nil, fmt.Errorf("%s: 1D LUTs are not supported", path)
+		case "LUT_3D_SIZE":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("%s:%d: LUT_3D_SIZE needs a size", path, line)
+			}
+			lut.size, err = strconv.Atoi(fields[1])
+			if err != nil || lut.size < 2 || lut.size > 256 {
+				return nil, fmt.Errorf("%s:%d: LUT_3D_SIZE %q is not between 2 and 256", path, line, fields[1])
+			}
+			continue
+		case "DOMAIN_MIN", "DOMAIN_MAX":
+			values, err := parseCubeTriple(fields[1:])
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %s: %w", path, line, fields[0], err)
+			}
+			if fields[0] == "DOMAIN_MIN" {
+				domainMin = values
+			} else {
+				domainMax = values
+			}
+			continue
+		}
+
+		values, err := parseCubeTriple(fields)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		for channel := range values {
+			if domainMax[channel] <= domainMin[channel] {
+				return nil, fmt.Errorf("%s: domain of channel %d is empty", path, channel)
+			}
+			values[channel] = (values[channel] - domainMin[channel]) / (domainMax[channel] - domainMin[channel])
+		}
+		lut.table = append(lut.table, values)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if lut.size == 0 {
+		return nil, fmt.Errorf("%s: LUT_3D_SIZE is missing", path)
+	}
+	if len(lut.table) != lut.size*lut.size*lut.size {
+		return nil, fmt.Errorf("%s: has %d colors instead of %d", path, len(lut.table), lut.size*lut.size*lut.size)
+	}
+	return lut, nil
+}
+
+func parseCubeTriple(fields []string) ([3]float64, error) {
+	var values [3]float64
+	if len(fields) != 3 {
+		return values, fmt.Errorf("expected 3 values, got %d", len(fields))
+	}
+	for channel, field := range fields {
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return values, fmt.Errorf("invalid value %q", field)
+		}
+		values[channel] = value
+	}
+	return values, nil
+}
+
+// At returns the color for the channels r, g and b with trilinear
+// interpolation between the colors of the table.
+func (l *LUT) At(r, g, b uint8) (uint8, uint8, uint8) {
+	scale := float64(l.size-1) / 255
+	var index, next [3]int
+	var fraction [3]float64
+	for channel, value := range [3]uint8{r, g, b} {
+		position := float64(value) * scale
+		index[channel] = min(int(position), l.size-2)
+		next[channel] = index[channel] + 1
+		fraction[channel] = position - float64(index[channel])
+	}
+
+	var out [3]float64
+	for corner := range 8 {
+		weight := 1.0
+		var at [3]int
+		for channel := range 3 {
+			if corner&(1<<channel) != 0 {
+				at[channel] = next[channel]
+				weight *= fraction[channel]
+			} else {
+				at[channel] = index[channel]
+				weight *= 1 - fraction[channel]
+			}
+		}
+		if weight == 0 {
+			continue
+		}
+		value := l.table[at[0]+l.size*(at[1]+l.size*at[2])]
+		for channel := range out {
+			out[channel] += weight * value[channel]
+		}
+	}
+	channel := func(value float64) uint8 {
+		return uint8(math.Round(math.Max(0, math.Min(value, 1)) * 255))
+	}
+	return channel(out[0]), channel(out[1]), channel(out[2])
+}
+
+// Correctable is implemented by matrices whose colors can be calibrated
+// while they are running. Changing the correction takes effect immediately,
+// also for content that is not rendered again.
+type Correctable interface {
+	ColorCorrection() ColorCorrection
+	SetColorCorrection(correction ColorCorrection) error
+}
+
+// Corrector is a Matrix that applies a ColorCorrection to the frames it
+// passes on to another matrix.
+type Corrector struct {
+	matrix Matrix
+	width  int
+	height int
+	// dir contains the LUTs.
+	dir string
+
+	mu         sync.Mutex
+	correction ColorCorrection
+	curves     [3][256]uint8
+	lut        *LUT
+	pixels     []color.RGBA
+	// shown is the last frame before correction, kept to present it again
+	// when the correction changes. frame is the corrected frame.
+	shown *image.RGBA
+	frame *image.RGBA
+}
+
+// NewCorrector corrects the colors of matrix with correction, loading its
+// LUT from LUTsDir.
+func NewCorrector(matrix Matrix, correction ColorCorrection) (*Corrector, error) {
+	width, height := matrix.Geometry()
+	c := &Corrector{
+		matrix: matrix,
+		width:  width,
+		height: height,
+		dir:    LUTsDir,
+		pixels: make([]color.RGBA, width*height),
+		shown:  image.NewRGBA(image.Rect(0, 0, width, height)),
+		frame:  image.NewRGBA(image.Rect(0, 0, width, height)),
+	}
+	if err := c.setLocked(correction); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Corrector) Geometry() (width, height int) {
+	return c.width, c.height
+}
+
+func (c *Corrector) At(position int) color.Color {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pixels[position]
+}
+
+func (c *Corrector) Set(position int, pixel color.Color) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pixels[position] = rgba(pixel)
+}
+
+func (c *Corrector) Apply(pixels []color.Color) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for position := range c.pixels {
+		pixel := color.RGBA{}
+		if position < len(pixels) {
+			pixel = rgba(pixels[position])
+		}
+		c.pixels[position] = pixel
+	}
+	return c.renderLocked()
+}
+
+func (c *Corrector) Render() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.renderLocked()
+}
+
+func (c *Corrector) renderLocked() error {
+	writeFrame(c.shown, c.pixels)
+	clear(c.pixels)
+	return c.presentLocked()
+}
+
+// Present corrects img and passes it on to the wrapped matrix.
+func (c *Corrector) Present(img *image.RGBA) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if img.Rect != c.shown.Rect {
+		clear(c.shown.Pix)
+	}
+	frameRows(img, c.width, c.height, func(position int, pix []uint8) {
+		copy(c.shown.Pix[4*position:], pix)
+	})
+	clear(c.pixels)
+	return c.presentLocked()
+}
+
+func (c *Corrector) presentLocked() error {
+	for offset := 0; offset < len(c.shown.Pix); offset += 4 {
+		in := c.shown.Pix[offset : offset+4 : offset+4]
+		out := c.frame.Pix[offset : offset+4 : offset+4]
+		r, g, b := in[0], in[1], in[2]
+		if c.lut != nil {
+			r, g, b = c.lut.At(r, g, b)
+		}
+		out[0], out[1], out[2], out[3] = c.curves[0][r], c.curves[1][g], c.curves[2][b], in[3]
+	}
+	return c.matrix.Present(c.frame)
+}
+
+// ColorCorrection returns the current correction.
+func (c *Corrector) ColorCorrection() ColorCorrection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.correction
+}
+
+// SetColorCorrection changes the correction and presents the last frame
+// again with it.
+func (c *Corrector) SetColorCorrection(correction ColorCorrection) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.setLocked(correction); err != nil {
+		return err
+	}
+	return c.presentLocked()
+}
+
+func (c *Corrector) setLocked(correction ColorCorrection) error {
+	if err := correction.Validate(); err != nil {
+		return err
+	}
+	var lut *LUT
+	if correction.LUT != "" {
+		var err error
+		lut, err = LoadLUT(filepath.Join(c.dir, correction.LUT))
+		if err != nil {
+			return fmt.Errorf("%w: load lut: %v", ErrInvalidColorCorrection, err)
+		}
+	}
+	c.correction = correction
+	c.curves = correction.curves()
+	c.lut = lut
+	return nil
+}
+
+func (c *Corrector) Close() error {
+	return c.matrix.Close()
+}
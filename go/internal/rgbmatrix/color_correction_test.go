@@ -0,0 +1,143 @@
+package rgbmatrix
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/display"
+)
+
+func TestColorCorrectionCurves(t *testing.T) {
+	identity := DefaultColorCorrection.curves()
+	for channel := range identity {
+		for value := range identity[channel] {
+			if identity[channel][value] != uint8(value) {
+				t.Fatalf("default curve of channel %d maps %d to %d", channel, value, identity[channel][value])
+			}
+		}
+	}
+
+	correction := DefaultColorCorrection
+	correction.Gamma = 2
+	correction.Gain.Red = 0.5
+	correction.Offset.Blue = 0.1
+	curves := correction.curves()
+	if got := curves[1][128]; got != 64 {
+		t.Errorf("gamma 2 maps 128 to %d, want 64", got)
+	}
+	if got := curves[0][255]; got != 128 {
+		t.Errorf("red gain 0.5 maps 255 to %d, want 128", got)
+	}
+	if got := curves[2][0]; got != 26 {
+		t.Errorf("blue offset 0.1 maps 0 to %d, want 26", got)
+	}
+
+	correction = DefaultColorCorrection
+	correction.Temperature = 3000
+	warm := correction.curves()
+	if warm[0][255] != 255 || warm[2][255] >= warm[1][255] || warm[1][255] >= 255 {
+		t.Errorf("3000K white is %d,%d,%d, want warmer than neutral", warm[0][255], warm[1][255], warm[2][255])
+	}
+}
+
+// writeLUT writes a .cube file of the given size whose colors are computed
+// by f from the input channels from 0 to 1.
+func writeLUT(t *testing.T, dir, name string, size int, f func(r, g, b float64) (float64, float64, float64)) {
+	t.Helper()
+	var cube strings.Builder
+	fmt.Fprintf(&cube, "# test LUT\nTITLE \"test\"\nLUT_3D_SIZE %d\n", size)
+	step := 1 / float64(size-1)
+	for b := range size {
+		for g := range size {
+			for r := range size {
+				red, green, blue := f(float64(r)*step, float64(g)*step, float64(b)*step)
+				fmt.Fprintf(&cube, "%f %f %f\n", red, green, blue)
+			}
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(cube.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadLUT(t *testing.T) {
+	dir := t.TempDir()
+	writeLUT(t, dir, "swap.cube", 5, func(r, g, b float64) (float64, float64, float64) { return b, g, r })
+
+	lut, err := LoadLUT(filepath.Join(dir, "swap.cube"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range [][3]uint8{{0, 0, 0}, {255, 0, 0}, {10, 128, 200}, {255, 255, 255}} {
+		r, g, b := lut.At(c[0], c[1], c[2])
+		if r != c[2] || g != c[1] || b != c[0] {
+			t.Errorf("At(%v) = %d,%d,%d, want channels swapped", c, r, g, b)
+		}
+	}
+
+	for name, cube := range map[string]string{
+		"missing size": "0 0 0\n",
+		"short":        "LUT_3D_SIZE 2\n0 0 0\n",
+		"1d":           "LUT_1D_SIZE 4\n",
+		"values":       "LUT_3D_SIZE 2\n0 zero 0\n",
+	} {
+		path := filepath.Join(dir, "broken.cube")
+		if err := os.WriteFile(path, []byte(cube), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadLUT(path); err == nil {
+			t.Errorf("%s: LoadLUT succeeded", name)
+		}
+	}
+}
+
+func TestCorrectorPresentsCorrectedFrames(t *testing.T) {
+	var frame display.Frame
+	observable := NewObservable(NewMemory(2, 1), func(published display.Frame) { frame = published })
+	corrector, err := NewCorrector(observable, DefaultColorCorrection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrector.dir = t.TempDir()
+	writeLUT(t, corrector.dir, "invert.cube", 3, func(r, g, b float64) (float64, float64, float64) { return 1 - r, 1 - g, 1 - b })
+
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.SetRGBA(0, 0, color.RGBA{R: 255, G: 128, A: 255})
+	if err := corrector.Present(img); err != nil {
+		t.Fatal(err)
+	}
+	if got := frame.Pixels[:3]; got[0] != 255 || got[1] != 128 || got[2] != 0 {
+		t.Fatalf("default correction changed the frame: %v", got)
+	}
+
+	correction := DefaultColorCorrection
+	correction.LUT = "invert.cube"
+	correction.Gain.Green = 0.5
+	if err := corrector.SetColorCorrection(correction); err != nil {
+		t.Fatal(err)
+	}
+	if got := frame.Pixels; got[0] != 0 || got[1] != 64 || got[2] != 255 || got[3] != 255 {
+		t.Fatalf("last frame was not presented again with the correction: %v", got)
+	}
+
+	corrector.Set(1, color.RGBA{B: 255, A: 255})
+	if err := corrector.Render(); err != nil {
+		t.Fatal(err)
+	}
+	if got := frame.Pixels; got[0] != 255 || got[3] != 255 || got[5] != 0 {
+		t.Fatalf("rendered frame was not corrected: %v", got)
+	}
+
+	correction.LUT = "missing.cube"
+	if err := corrector.SetColorCorrection(correction); err == nil {
+		t.Fatal("missing LUT was accepted")
+	}
+	if corrector.ColorCorrection().LUT != "invert.cube" {
+		t.Fatal("failed change replaced the color correction")
+	}
+}
@@ -0,0 +1,144 @@
+package rgbmatrix
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"sync"
+	"time"
+)
+
+const compositorFrameInterval = time.Second / 60
+
+// Compositor shares a matrix between renderers that each draw into a region
+// of it. Regions are Matrix values of their own; rendering a region only
+// commits its pixels, and Run renders the combined frame at most once per
+// frame interval.
+type Compositor struct {
+	matrix Matrix
+
+	mu    sync.Mutex
+	frame *image.RGBA
+	dirty bool
+}
+
+func NewCompositor(matrix Matrix) *Compositor {
+	width, height := matrix.Geometry()
+	return &Compositor{
+		matrix: matrix,
+		frame:  image.NewRGBA(image.Rect(0, 0, width, height)),
+	}
+}
+
+// Region returns a matrix that draws into the given rectangle, which must lie
+// within the compositor's matrix.
+func (c *Compositor) Region(bounds image.Rectangle) Matrix {
+	return &regionMatrix{
+		compositor: c,
+		bounds:     bounds,
+		pixels:     make([]color.RGBA, bounds.Dx()*bounds.Dy()),
+	}
+}
+
+// Run renders the combined frame whenever a region committed new pixels,
+// until ctx is done.
+func (c *Compositor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(compositorFrameInterval)
+	defer ticker.Stop()
+	for {
+		if err := c.render(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Compositor) render() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	c.dirty = false
+	return c.matrix.Present(c.frame)
+}
+
+func (c *Compositor) commit(bounds image.Rectangle, pixels []color.RGBA) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	width := bounds.Dx()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := c.frame.Pix[c.frame.PixOffset(bounds.Min.X, y):]
+		for x, pixel := range pixels[(y-bounds.Min.Y)*width : (y-bounds.Min.Y+1)*width] {
+			row[4*x], row[4*x+1], row[4*x+2], row[4*x+3] = pixel.R, pixel.G, pixel.B, pixel.A
+		}
+	}
+	c.dirty = true
+}
+
+// regionMatrix is a Matrix backed by a rectangle of a Compositor. Like the
+// other matrices, its drawing buffer is cleared after every render, while
+// the committed pixels stay visible until the region renders again.
+type regionMatrix struct {
+	compositor *Compositor
+	bounds     image.Rectangle
+
+	mu     sync.Mutex
+	pixels []color.RGBA
+}
+
+func (r *regionMatrix) Geometry() (width, height int) {
+	return r.bounds.Dx(), r.bounds.Dy()
+}
+
+func (r *regionMatrix) At(position int) color.Color {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.pixels[position]
+}
+
+func (r *regionMatrix) Set(position int, c color.Color) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pixels[position] = rgba(c)
+}
+
+func (r *regionMatrix) Apply(pixels []color.Color) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for position := range r.pixels {
+		pixel := color.RGBA{}
+		if position < len(pixels) {
+			pixel = rgba(pixels[position])
+		}
+		r.pixels[position] = pixel
+	}
+	return r.renderLocked()
+}
+
+func (r *regionMatrix) Render() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.renderLocked()
+}
+
+func (r *regionMatrix) Present(img *image.RGBA) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	readFrame(r.pixels, r.bounds.Dx(), r.bounds.Dy(), img)
+	return r.renderLocked()
+}
+
+func (r *regionMatrix) renderLocked() error {
+	r.compositor.commit(r.bounds, r.pixels)
+	clear(r.pixels)
+	return nil
+}
+
+func (r *regionMatrix) Close() error {
+	return nil
+}
@@ -0,0 +1,89 @@
+package rgbmatrix
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+type countingMatrix struct {
+	observableMatrix
+	renders int
+}
+
+func (m *countingMatrix) Render() error {
+	m.renders++
+	return nil
+}
+
+func (m *countingMatrix) Present(img *image.RGBA) error {
+	m.observableMatrix.Present(img)
+	return m.Render()
+}
+
+func TestCompositorCombinesRegionsInOneRender(t *testing.T) {
+	matrix := &countingMatrix{observableMatrix: observableMatrix{width: 3, height: 2, pixels: make([]color.Color, 6)}}
+	compositor := NewCompositor(matrix)
+	left := compositor.Region(image.Rect(0, 0, 1, 2))
+	right := compositor.Region(image.Rect(1, 0, 3, 2))
+
+	if width, height := right.Geometry(); width != 2 || height != 2 {
+		t.Fatalf("unexpected region geometry: %dx%d", width, height)
+	}
+	red, blue := color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255}
+	left.Set(1, red)
+	right.Set(3, blue)
+	if err := left.Render(); err != nil {
+		t.Fatal(err)
+	}
+	if err := right.Render(); err != nil {
+		t.Fatal(err)
+	}
+	if matrix.renders != 0 {
+		t.Fatal("a region rendered the matrix")
+	}
+
+	if err := compositor.render(); err != nil {
+		t.Fatal(err)
+	}
+	if err := compositor.render(); err != nil {
+		t.Fatal(err)
+	}
+	if matrix.renders != 1 {
+		t.Fatalf("unexpected number of renders: %d", matrix.renders)
+	}
+	want := []color.Color{
+		color.RGBA{}, color.RGBA{}, color.RGBA{},
+		red, color.RGBA{}, blue,
+	}
+	for position, pixel := range matrix.pixels {
+		if pixel != want[position] {
+			t.Fatalf("unexpected pixel %d: got %v, want %v", position, pixel, want[position])
+		}
+	}
+	if left.At(1) != (color.RGBA{}) {
+		t.Fatal("region drawing buffer was not cleared after rendering")
+	}
+}
+
+func TestLayoutValidate(t *testing.T) {
+	valid := Layout{Regions: []Region{
+		{Name: "left", Width: 64, Height: 96},
+		{Name: "right", X: 64, Width: 128, Height: 96},
+	}}
+	if err := valid.Validate(192, 96); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, layout := range map[string]Layout{
+		"empty":        {},
+		"outside":      {Regions: []Region{{Name: "wide", Width: 200, Height: 96}}},
+		"overlap":      {Regions: []Region{{Name: "a", Width: 100, Height: 96}, {Name: "b", X: 64, Width: 64, Height: 96}}},
+		"duplicate":    {Regions: []Region{{Name: "a", Width: 10, Height: 10}, {Name: "a", X: 10, Width: 10, Height: 10}}},
+		"invalid name": {Regions: []Region{{Name: "Left Side", Width: 10, Height: 10}}},
+	} {
+		if err := layout.Validate(192, 96); err == nil {
+			t.Errorf("%s: layout was accepted", name)
+		}
+	}
+}
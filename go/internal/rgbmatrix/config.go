@@ -1,13 +1,23 @@
 package rgbmatrix
 
 import (
+	"errors"
 	"fmt"
-	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/pelletier/go-toml/v2"
 )
 
+const (
+	// DefaultFPS is the frame rate of renderers without one.
+	DefaultFPS = 30
+	// MaxFPS bounds the frame rate, which the matrix cannot refresh much
+	// faster anyway.
+	MaxFPS = 120
+)
+
 type Config struct {
 	Auth struct {
 		ClientID     string `toml:"client_id"`
@@ -23,52 +33,247 @@ type Config struct {
 	Dashboards struct {
 		Font string `toml:"font"`
 	} `toml:"dashboards"`
+	Display struct {
+		// Transition is the default style when content changes.
+		Transition         TransitionStyle `toml:"transition"`
+		TransitionDuration Duration        `toml:"transition_duration"`
+		// Palette is sampled by animations and dashboards that do not choose
+		// one. It is a built-in palette or a palette file.
+		Palette string `toml:"palette"`
+		// FPS is the frame rate of animations and dashboards.
+		FPS int `toml:"fps"`
+	} `toml:"display"`
+	// Boot is shown on startup when no display state was saved or it
+	// cannot be restored.
+	Boot struct {
+		// Type and Name are the renderer and content, like in the API.
+		// The blank type turns the panels off and has no name.
+		Type   string            `toml:"type"`
+		Name   string            `toml:"name"`
+		Params map[string]string `toml:"params"`
+		// StateFile saves the display state across restarts.
+		StateFile string `toml:"state_file"`
+	} `toml:"boot"`
+	// Shutdown configures how the servers stop.
+	Shutdown struct {
+		// Timeout bounds how long requests in flight may take to finish.
+		Timeout Duration `toml:"timeout"`
+		// Splash is an image in images/pngs that is shown for
+		// SplashDuration before the panels are blanked.
+		Splash         string   `toml:"splash"`
+		SplashDuration Duration `toml:"splash_duration"`
+	} `toml:"shutdown"`
+	// Color calibrates the colors of the panels.
+	Color ColorCorrection `toml:"color"`
+	// Layouts are the named split-screen layouts.
+	Layouts map[string]Layout `toml:"layouts"`
+	// Schedules show content at the times of their cron expressions, keyed
+	// by ID.
+	Schedules map[string]Schedule `toml:"schedules"`
+	// Mapping maps the image onto panels that are rotated, mirrored or
+	// arranged differently than they are chained.
+	Mapping        PixelMapping   `toml:"mapping"`
 	Options        MatrixOptions  `toml:"options"`
 	RuntimeOptions RuntimeOptions `toml:"runtime_options"`
 }
 
-func LoadConfig() Config {
-	config, err := LoadConfigFile("./config.toml")
-	if err != nil {
-		log.Fatal(err)
-	}
-	return config
-}
+const (
+	// DefaultConfigPath is the config file the servers read without
+	// --config.
+	DefaultConfigPath = "./config.toml"
+	// DefaultStateFile is where the display state is saved without
+	// boot.state_file. Its directory must be writable by the user that the
+	// server drops its privileges to.
+	DefaultStateFile = "/var/lib/led/state.json"
+)
 
+// LoadConfigFile reads the config file at path, overrides its values with
+// the environment as described by ApplyEnv, fills in defaults and validates
+// the result. Problems with the values are reported together in a
+// *ValidationError.
 func LoadConfigFile(path string) (Config, error) {
 	bytes, err := os.ReadFile(path)
 	if err != nil {
 		return Config{}, fmt.Errorf("read config: %w", err)
 	}
 
-	var config Config
+	// Values left out of the color correction and the runtime options keep
+	// their defaults.
+	config := Config{Color: DefaultColorCorrection, RuntimeOptions: DefaultRuntimeOptions}
 	err = toml.Unmarshal(bytes, &config)
 	if err != nil {
 		return Config{}, fmt.Errorf("parse config: %w", err)
 	}
 
-	// Defaults
-	if config.Options.PWMBits == 0 {
-		config.Options.PWMBits = 11
+	var problems ValidationError
+	problems.add("", ApplyEnv(&config, os.LookupEnv))
+	config.setDefaults()
+	problems.add("", config.Validate())
+	if len(problems.Problems) > 0 {
+		return Config{}, &problems
+	}
+	return config, nil
+}
+
+func (c *Config) setDefaults() {
+	if c.Options.Rows == 0 {
+		c.Options.Rows = 32
+	}
+	if c.Options.Cols == 0 {
+		c.Options.Cols = 32
+	}
+	if c.Options.PWMBits == 0 {
+		c.Options.PWMBits = 11
+	}
+	if c.Options.PWMLSBNanoseconds == 0 {
+		c.Options.PWMLSBNanoseconds = 130
 	}
-	if config.Options.PWMLSBNanoseconds == 0 {
-		config.Options.PWMLSBNanoseconds = 130
+	if c.Options.ChainLength == 0 {
+		c.Options.ChainLength = 1
 	}
-	if config.Options.PWMDitherBits == 0 {
-		config.Options.PWMDitherBits = 0
+	if c.Options.Parallel == 0 {
+		c.Options.Parallel = 1
 	}
-	if config.Options.ScanMode == 0 {
-		config.Options.ScanMode = Progressive
+	if c.Options.Brightness == 0 {
+		c.Options.Brightness = 100
 	}
-	if config.Options.ChainLength == 0 {
-		config.Options.ChainLength = 1
+	if c.Options.HardwareMapping == "" {
+		c.Options.HardwareMapping = "regular"
 	}
-	if config.Options.Parallel == 0 {
-		config.Options.Parallel = 1
+	if c.Options.LEDRGBSequence == "" {
+		c.Options.LEDRGBSequence = "RGB"
 	}
-	if config.Dashboards.Font == "" {
-		config.Dashboards.Font = "assets/fonts/7x14.bdf"
+	if c.Display.Transition == TransitionDefault {
+		c.Display.Transition = TransitionNone
 	}
+	if c.Display.TransitionDuration == 0 {
+		c.Display.TransitionDuration = Duration(500 * time.Millisecond)
+	}
+	if c.Display.FPS == 0 {
+		c.Display.FPS = DefaultFPS
+	}
+	if c.Dashboards.Font == "" {
+		c.Dashboards.Font = "assets/fonts/7x14.bdf"
+	}
+	if c.Boot.Type == "" && c.Boot.Name == "" {
+		c.Boot.Type, c.Boot.Name = "image", "autodarts"
+	}
+	if c.Boot.StateFile == "" {
+		c.Boot.StateFile = DefaultStateFile
+	}
+	if c.Shutdown.Timeout == 0 {
+		c.Shutdown.Timeout = Duration(5 * time.Second)
+	}
+	if c.Shutdown.SplashDuration == 0 {
+		c.Shutdown.SplashDuration = Duration(2 * time.Second)
+	}
+}
 
-	return config, nil
+// Validate checks the whole config and returns a *ValidationError with
+// every problem, or nil.
+func (c *Config) Validate() error {
+	var problems ValidationError
+	if c.Display.FPS < 1 || c.Display.FPS > MaxFPS {
+		problems.add("display", fmt.Errorf("fps %d is not between 1 and %d", c.Display.FPS, MaxFPS))
+	}
+	if c.Boot.Type == "" || (c.Boot.Name == "" && c.Boot.Type != "blank") {
+		problems.add("boot", fmt.Errorf("type and name are both required"))
+	}
+	problems.add("color", c.Color.Validate())
+	for _, id := range ScheduleIDs(c.Schedules) {
+		if !ScheduleIDPattern.MatchString(id) {
+			problems.add("schedules."+id, fmt.Errorf("the id may only contain letters, digits, '-' and '_'"))
+			continue
+		}
+		problems.add("schedules."+id, c.Schedules[id].Validate())
+	}
+	problems.add("runtime_options", c.RuntimeOptions.Validate())
+	if err := c.Options.Validate(); err != nil {
+		// The mapping and the layouts depend on the size of the matrix.
+		problems.add("options", err)
+		return problems.err()
+	}
+
+	if c.Options.PixelMapperConfig != "" && !c.Mapping.isZero() {
+		problems.add("mapping", fmt.Errorf("cannot be combined with options.pixel_mapper_config"))
+	}
+	problems.add("mapping", c.Mapping.Validate(c.Options))
+	width, height := c.Mapping.Geometry(c.Options)
+	for _, name := range LayoutNames(c.Layouts) {
+		problems.add("layouts."+name, c.Layouts[name].Validate(width, height))
+	}
+	return problems.err()
+}
+
+// ValidationError lists every problem of a config.
+type ValidationError struct {
+	Problems []Problem
+}
+
+// Problem is a problem with the value of a config key.
+type Problem struct {
+	// Key is the section or key of the config, or the environment variable
+	// that set it.
+	Key     string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		lines = append(lines, problem.Key+": "+problem.Message)
+	}
+	return "invalid config:\n  " + strings.Join(lines, "\n  ")
+}
+
+// add adds the problems of err under key. Joined errors and validation
+// errors become a problem each.
+func (e *ValidationError) add(key string, err error) {
+	if err == nil {
+		return
+	}
+	var validation *ValidationError
+	if errors.As(err, &validation) {
+		for _, problem := range validation.Problems {
+			if key != "" {
+				problem.Key = key + "." + problem.Key
+			}
+			e.Problems = append(e.Problems, problem)
+		}
+		return
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			e.add(key, err)
+		}
+		return
+	}
+	e.Problems = append(e.Problems, Problem{Key: key, Message: err.Error()})
+}
+
+func (e *ValidationError) err() error {
+	if len(e.Problems) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Duration is a time.Duration that is written as a string such as "500ms"
+// in config files.
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	if duration < 0 {
+		return fmt.Errorf("duration %s is negative", duration)
+	}
+	*d = Duration(duration)
+	return nil
 }
@@ -0,0 +1,79 @@
+package rgbmatrix
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// EnvPrefix starts the environment variables that override config keys.
+const EnvPrefix = "LED_"
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// ApplyEnv overrides the keys of config with the environment variables that
+// lookup finds. A key is named by EnvPrefix and its path in upper case with
+// underscores, so LED_AUTH_CLIENT_SECRET sets client_secret in [auth] and
+// LED_COLOR_GAIN_BLUE the blue gain. Strings are taken as they are, other
+// values are written like in the config file, such as
+// LED_COLOR_GAIN='{ red = 1.0, green = 0.9, blue = 0.8 }'. Values that
+// cannot be parsed are returned as a *ValidationError.
+func ApplyEnv(config *Config, lookup func(key string) (string, bool)) error {
+	var problems ValidationError
+	applyEnv(reflect.ValueOf(config).Elem(), strings.TrimSuffix(EnvPrefix, "_"), lookup, &problems)
+	return problems.err()
+}
+
+func applyEnv(value reflect.Value, prefix string, lookup func(string) (string, bool), problems *ValidationError) {
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := prefix + "_" + strings.ToUpper(configKey(field))
+		target := value.Field(i)
+		if raw, ok := lookup(name); ok {
+			if err := setEnvValue(target, raw); err != nil {
+				problems.Problems = append(problems.Problems, Problem{Key: name, Message: err.Error()})
+			}
+		}
+		if target.Kind() == reflect.Struct && !reflect.PointerTo(target.Type()).Implements(textUnmarshalerType) {
+			applyEnv(target, name, lookup, problems)
+		}
+	}
+}
+
+// configKey returns the key of a field in the config file.
+func configKey(field reflect.StructField) string {
+	if key, _, _ := strings.Cut(field.Tag.Get("toml"), ","); key != "" {
+		return key
+	}
+	return field.Name
+}
+
+func setEnvValue(target reflect.Value, raw string) error {
+	if unmarshaler, ok := target.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(raw))
+	}
+	if target.Kind() == reflect.String {
+		target.SetString(raw)
+		return nil
+	}
+
+	// Other values are parsed as the value of a key in a TOML document,
+	// which keeps the fields of tables that are left out.
+	document := reflect.New(reflect.StructOf([]reflect.StructField{{
+		Name: "Value",
+		Type: target.Type(),
+		Tag:  `toml:"value"`,
+	}}))
+	document.Elem().Field(0).Set(target)
+	if err := toml.Unmarshal([]byte("value = "+raw), document.Interface()); err != nil {
+		return fmt.Errorf("invalid value %q: %v", raw, err)
+	}
+	target.Set(document.Elem().Field(0))
+	return nil
+}
@@ -0,0 +1,80 @@
+package rgbmatrix
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ConfigChanges lists the keys that differ between two configs, such as
+// "dashboards.font" or "color.gain.red".
+type ConfigChanges struct {
+	// Applied keys take effect while the server runs.
+	Applied []string
+	// Restart keys only take effect when the server restarts.
+	Restart []string
+}
+
+// Reload compares the running config c with next. It returns the config
+// that runs once the keys that are safe to change at runtime were taken
+// from next, and the keys that changed.
+func (c Config) Reload(next Config) (Config, ConfigChanges) {
+	running := c
+	var changes ConfigChanges
+	reloadFields(reflect.ValueOf(&running).Elem(), reflect.ValueOf(next), "", &changes)
+	return running, changes
+}
+
+// Changed reports whether key or a key below it changed and was applied.
+func (c ConfigChanges) Changed(key string) bool {
+	for _, changed := range c.Applied {
+		if changed == key || strings.HasPrefix(changed, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func reloadFields(running, next reflect.Value, prefix string, changes *ConfigChanges) {
+	for i := range running.NumField() {
+		field := running.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := prefix + strings.ToLower(configKey(field))
+		value, nextValue := running.Field(i), next.Field(i)
+		if value.Kind() == reflect.Struct && !reflect.PointerTo(value.Type()).Implements(textUnmarshalerType) {
+			reloadFields(value, nextValue, key+".", changes)
+			continue
+		}
+		if reflect.DeepEqual(value.Interface(), nextValue.Interface()) {
+			continue
+		}
+		if needsRestart(key) {
+			changes.Restart = append(changes.Restart, key)
+			continue
+		}
+		changes.Applied = append(changes.Applied, key)
+		value.Set(nextValue)
+	}
+}
+
+// needsRestart reports whether a key only takes effect when the server
+// restarts. The matrix is created with its options and mapping, the API
+// serves the layouts it started with, the Autodarts client keeps its
+// credentials, the boot content is only shown on startup, the schedules
+// are loaded on startup and the API server was created with its shutdown
+// timeout.
+func needsRestart(key string) bool {
+	switch key {
+	case "options.brightness":
+		return false
+	case "shutdown.timeout":
+		return true
+	}
+	for _, section := range []string{"auth", "boot", "layouts", "mapping", "options", "runtime_options", "schedules"} {
+		if key == section || strings.HasPrefix(key, section+".") {
+			return true
+		}
+	}
+	return false
+}
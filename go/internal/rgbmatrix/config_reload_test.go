@@ -0,0 +1,40 @@
+package rgbmatrix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigReload(t *testing.T) {
+	var running Config
+	running.Dashboards.Font = "assets/fonts/7x14.bdf"
+	running.Options.Rows = 32
+	running.Options.Brightness = 100
+	running.Color = DefaultColorCorrection
+
+	next := running
+	next.Dashboards.Font = "assets/fonts/6x10.bdf"
+	next.Shopify.TotalSales = "http://shop/total-sales"
+	next.Options.Rows = 64
+	next.Options.Brightness = 40
+	next.Color.Gain.Blue = 0.8
+	next.Auth.ClientSecret = "secret"
+	next.Layouts = map[string]Layout{"split": {}}
+
+	reloaded, changes := running.Reload(next)
+
+	assert.Equal(t, []string{"shopify.total_sales", "dashboards.font", "color.gain.blue", "options.brightness"}, changes.Applied)
+	assert.Equal(t, []string{"auth.client_secret", "layouts", "options.rows"}, changes.Restart)
+	assert.Equal(t, "assets/fonts/6x10.bdf", reloaded.Dashboards.Font)
+	assert.Equal(t, 0.8, reloaded.Color.Gain.Blue)
+	assert.Equal(t, 40, reloaded.Options.Brightness)
+	assert.Equal(t, 32, reloaded.Options.Rows)
+	assert.Empty(t, reloaded.Auth.ClientSecret)
+	assert.Nil(t, reloaded.Layouts)
+
+	assert.True(t, changes.Changed("color"))
+	assert.True(t, changes.Changed("dashboards.font"))
+	assert.False(t, changes.Changed("display"))
+	assert.False(t, changes.Changed("options.rows"))
+}
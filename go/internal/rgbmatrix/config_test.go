@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -19,3 +20,235 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, config.Options.Rows, 64)
 	assert.Equal(t, config.Options.ChainLength, 1)
 }
+
+func TestLoadConfigDisplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	err := os.WriteFile(path, []byte("[display]\ntransition = \"crossfade\"\ntransition_duration = \"750ms\"\npalette = \"sunset\"\nfps = 60\n"), 0o600)
+	assert.NoError(t, err)
+
+	config, err := LoadConfigFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, TransitionCrossfade, config.Display.Transition)
+	assert.Equal(t, Duration(750*time.Millisecond), config.Display.TransitionDuration)
+	assert.Equal(t, "sunset", config.Display.Palette)
+	assert.Equal(t, 60, config.Display.FPS)
+
+	err = os.WriteFile(path, []byte("[display]\ntransition = \"spin\"\n"), 0o600)
+	assert.NoError(t, err)
+	_, err = LoadConfigFile(path)
+	assert.ErrorContains(t, err, "invalid transition")
+
+	err = os.WriteFile(path, []byte("[display]\nfps = 500\n"), 0o600)
+	assert.NoError(t, err)
+	_, err = LoadConfigFile(path)
+	assert.ErrorContains(t, err, "display: fps 500 is not between 1 and 120")
+}
+
+func TestLoadConfigDisplayDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	assert.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	config, err := LoadConfigFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, TransitionNone, config.Display.Transition)
+	assert.Equal(t, Duration(500*time.Millisecond), config.Display.TransitionDuration)
+	assert.Equal(t, DefaultFPS, config.Display.FPS)
+}
+
+func TestLoadConfigBoot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	assert.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	config, err := LoadConfigFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "image", config.Boot.Type)
+	assert.Equal(t, "autodarts", config.Boot.Name)
+	assert.Equal(t, DefaultStateFile, config.Boot.StateFile)
+
+	err = os.WriteFile(path, []byte("[boot]\ntype = \"dashboard\"\nname = \"clock\"\nparams = { palette = \"sunset\" }\nstate_file = \"/srv/led/state.json\"\n"), 0o600)
+	assert.NoError(t, err)
+	config, err = LoadConfigFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "clock", config.Boot.Name)
+	assert.Equal(t, map[string]string{"palette": "sunset"}, config.Boot.Params)
+	assert.Equal(t, "/srv/led/state.json", config.Boot.StateFile)
+
+	err = os.WriteFile(path, []byte("[boot]\ntype = \"dashboard\"\n"), 0o600)
+	assert.NoError(t, err)
+	_, err = LoadConfigFile(path)
+	assert.ErrorContains(t, err, "boot: type and name are both required")
+
+	err = os.WriteFile(path, []byte("[boot]\ntype = \"blank\"\n"), 0o600)
+	assert.NoError(t, err)
+	config, err = LoadConfigFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "blank", config.Boot.Type)
+	assert.Empty(t, config.Boot.Name)
+}
+
+func TestLoadConfigLayouts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	layouts := "[options]\nrows = 32\ncols = 64\n" +
+		"[[layouts.split.regions]]\nname = \"left\"\nwidth = 32\nheight = 32\n" +
+		"[[layouts.split.regions]]\nname = \"right\"\nx = 32\nwidth = 32\nheight = 32\n"
+	assert.NoError(t, os.WriteFile(path, []byte(layouts), 0o600))
+
+	config, err := LoadConfigFile(path)
+
+	assert.NoError(t, err)
+	region, ok := config.Layouts["split"].Region("right")
+	assert.True(t, ok)
+	assert.Equal(t, Region{Name: "right", X: 32, Width: 32, Height: 32}, region)
+
+	overflowing := "[options]\nrows = 32\ncols = 64\n[[layouts.wide.regions]]\nname = \"all\"\nwidth = 128\nheight = 32\n"
+	assert.NoError(t, os.WriteFile(path, []byte(overflowing), 0o600))
+	_, err = LoadConfigFile(path)
+	assert.ErrorContains(t, err, "layouts.wide: region \"all\"")
+}
+
+func TestLoadConfigSchedules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	schedules := "[schedules.morning]\ncron = \"0 7 * * *\"\ntimezone = \"UTC\"\ntype = \"dashboard\"\nname = \"clock\"\n" +
+		"[schedules.night]\ncron = \"0 22 * * *\"\ntype = \"blank\"\n"
+	assert.NoError(t, os.WriteFile(path, []byte(schedules), 0o600))
+
+	config, err := LoadConfigFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Schedule{Cron: "0 7 * * *", Timezone: "UTC", Type: "dashboard", Name: "clock"}, config.Schedules["morning"])
+	location, err := config.Schedules["night"].Location()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Local, location)
+
+	invalid := "[schedules.often]\ncron = \"*/0 * * * *\"\ntype = \"blank\"\n" +
+		"[schedules.away]\ncron = \"@daily\"\ntimezone = \"Mars/Olympus\"\ntype = \"blank\"\n" +
+		"[schedules.unnamed]\ncron = \"@daily\"\ntype = \"image\"\n" +
+		"[schedules.\"business hours\"]\ncron = \"0 9 * * 1-5\"\ntype = \"blank\"\n"
+	assert.NoError(t, os.WriteFile(path, []byte(invalid), 0o600))
+	_, err = LoadConfigFile(path)
+	assert.ErrorContains(t, err, "schedules.often: cron expression")
+	assert.ErrorContains(t, err, "schedules.away: timezone \"Mars/Olympus\"")
+	assert.ErrorContains(t, err, "schedules.unnamed: type and name are both required")
+	assert.ErrorContains(t, err, "schedules.business hours: the id may only contain")
+}
+
+func TestLoadConfigMapping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	mapping := "[options]\nrows = 32\ncols = 64\nchain_length = 2\n" +
+		"[mapping]\nrotate = 90\n" +
+		"[[mapping.panels]]\nindex = 1\n" +
+		"[[mapping.panels]]\nindex = 0\ny = 32\nrotate = 180\n" +
+		"[[layouts.split.regions]]\nname = \"top\"\nwidth = 32\nheight = 64\n"
+	assert.NoError(t, os.WriteFile(path, []byte(mapping), 0o600))
+
+	config, err := LoadConfigFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 90, config.Mapping.Rotate)
+	assert.Equal(t, PanelPlacement{Index: 0, Y: 32, Rotate: 180}, config.Mapping.Panels[1])
+	width, height := config.Mapping.Geometry(config.Options)
+	assert.Equal(t, 64, width)
+	assert.Equal(t, 64, height)
+
+	combined := "[options]\npixel_mapper_config = \"Rotate:90\"\n[mapping]\nmirror = \"horizontal\"\n"
+	assert.NoError(t, os.WriteFile(path, []byte(combined), 0o600))
+	_, err = LoadConfigFile(path)
+	assert.ErrorContains(t, err, "pixel_mapper_config")
+}
+
+func TestLoadConfigColor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	color := "[color]\ngamma = 2.2\ntemperature = 5000\ngain = { blue = 0.8 }\n"
+	assert.NoError(t, os.WriteFile(path, []byte(color), 0o600))
+
+	config, err := LoadConfigFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2.2, config.Color.Gamma)
+	assert.Equal(t, 5000, config.Color.Temperature)
+	assert.Equal(t, Channels{Red: 1, Green: 1, Blue: 0.8}, config.Color.Gain)
+
+	assert.NoError(t, os.WriteFile(path, nil, 0o600))
+	config, err = LoadConfigFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultColorCorrection, config.Color)
+
+	assert.NoError(t, os.WriteFile(path, []byte("[color]\ngamma = 9\n"), 0o600))
+	_, err = LoadConfigFile(path)
+	assert.ErrorContains(t, err, "gamma 9")
+}
+
+func TestLoadConfigOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	options := "[options]\nled_rgb_sequence = \"RBG\"\npanel_type = \"FM6126A\"\ndisable_busy_waiting = true\n" +
+		"[runtime_options]\ndrop_privileges = false\ngpio_slowdown = 4\n"
+	assert.NoError(t, os.WriteFile(path, []byte(options), 0o600))
+
+	config, err := LoadConfigFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "RBG", config.Options.LEDRGBSequence)
+	assert.Equal(t, "FM6126A", config.Options.PanelType)
+	assert.True(t, config.Options.DisableBusyWaiting)
+	assert.Equal(t, "regular", config.Options.HardwareMapping)
+	assert.Equal(t, 100, config.Options.Brightness)
+	assert.False(t, config.RuntimeOptions.DropPrivileges)
+	assert.Equal(t, "daemon", config.RuntimeOptions.DropPrivUser)
+	assert.True(t, config.RuntimeOptions.DoGPIOInit)
+
+	invalid := "[options]\nrows = 7\npwm_bits = 12\nled_rgb_sequence = \"RRB\"\n[runtime_options]\ndaemon = true\n"
+	assert.NoError(t, os.WriteFile(path, []byte(invalid), 0o600))
+	_, err = LoadConfigFile(path)
+	var problems *ValidationError
+	assert.ErrorAs(t, err, &problems)
+	assert.Contains(t, problems.Problems, Problem{Key: "options", Message: "rows 7 is not an even number between 8 and 64"})
+	assert.Contains(t, problems.Problems, Problem{Key: "options", Message: "pwm_bits 12 is not between 1 and 11"})
+	assert.Contains(t, problems.Problems, Problem{Key: "runtime_options", Message: "daemon cannot fork the server, run it as a service instead"})
+	assert.ErrorContains(t, err, "led_rgb_sequence \"RRB\"")
+}
+
+func TestLoadConfigEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	config := "[auth]\nclient_id = \"file\"\n[display]\nfps = 60\n[color]\ngain = { red = 0.5 }\n"
+	assert.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+	t.Setenv("LED_AUTH_CLIENT_SECRET", "s3cr=t \"quoted\"")
+	t.Setenv("LED_SHOPIFY_TOTAL_SALES", "https://shop.example/sales")
+	t.Setenv("LED_DISPLAY_FPS", "24")
+	t.Setenv("LED_DISPLAY_TRANSITION", "wipe")
+	t.Setenv("LED_DISPLAY_TRANSITION_DURATION", "1s")
+	t.Setenv("LED_COLOR_GAIN", "{ blue = 0.8 }")
+	t.Setenv("LED_COLOR_GAIN_GREEN", "0.9")
+	t.Setenv("LED_MAPPING_PANELS", "[{ index = 0, rotate = 180 }]")
+
+	loaded, err := LoadConfigFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "file", loaded.Auth.ClientID)
+	assert.Equal(t, "s3cr=t \"quoted\"", loaded.Auth.ClientSecret)
+	assert.Equal(t, "https://shop.example/sales", loaded.Shopify.TotalSales)
+	assert.Equal(t, 24, loaded.Display.FPS)
+	assert.Equal(t, TransitionWipe, loaded.Display.Transition)
+	assert.Equal(t, Duration(time.Second), loaded.Display.TransitionDuration)
+	assert.Equal(t, Channels{Red: 0.5, Green: 0.9, Blue: 0.8}, loaded.Color.Gain)
+	assert.Equal(t, []PanelPlacement{{Index: 0, Rotate: 180}}, loaded.Mapping.Panels)
+}
+
+func TestLoadConfigReportsAllProblems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	assert.NoError(t, os.WriteFile(path, []byte("[display]\nfps = 500\n[color]\ngamma = 9\n"), 0o600))
+	t.Setenv("LED_OPTIONS_ROWS", "many")
+	t.Setenv("LED_DISPLAY_TRANSITION", "spin")
+
+	_, err := LoadConfigFile(path)
+
+	var problems *ValidationError
+	assert.ErrorAs(t, err, &problems)
+	keys := make([]string, 0, len(problems.Problems))
+	for _, problem := range problems.Problems {
+		keys = append(keys, problem.Key)
+	}
+	assert.Equal(t, []string{"LED_DISPLAY_TRANSITION", "LED_OPTIONS_ROWS", "display", "color"}, keys)
+}
@@ -2,6 +2,10 @@ package rgbmatrix
 
 import (
 	"bufio"
+	"encoding/hex"
+	"image"
+	"image/color"
+	"image/draw"
 	"os"
 	"strconv"
 	"strings"
@@ -48,6 +52,51 @@ func (f *BDFFont) Width() int {
 	return f.width
 }
 
+// TextWidth returns the number of pixels DrawText advances for text.
+func (f *BDFFont) TextWidth(text string) int {
+	width := 0
+	for _, ch := range text {
+		glyph := f.Glyphs[ch]
+		if glyph == nil {
+			width += 6 // fallback spacing, as in DrawText
+			continue
+		}
+		width += glyph.DeviceWidth
+	}
+	return width
+}
+
+// DrawText draws text onto dst with its top left corner at x, y. Pixels
+// outside the bounds of dst are skipped.
+func DrawText(dst draw.Image, font *BDFFont, text string, x, y int, color color.Color) {
+	cursorX := x
+	for _, ch := range text {
+		glyph := font.Glyphs[ch]
+		if glyph == nil {
+			cursorX += 6 // fallback spacing
+			continue
+		}
+		for row := 0; row < len(glyph.Bitmap); row++ {
+			line := glyph.Bitmap[row]
+			// Parse hex row data into bytes
+			rowData, _ := hex.DecodeString(line)
+			for col := 0; col < glyph.Width; col++ {
+				byteIndex := col / 8
+				bitIndex := 7 - (col % 8) // BDF stores MSB first
+
+				if byteIndex < len(rowData) && (rowData[byteIndex]&(1<<bitIndex)) != 0 {
+					px := cursorX + glyph.XOffset + col
+					py := y + row // + glyph.YOffset
+					if image.Pt(px, py).In(dst.Bounds()) {
+						dst.Set(px, py, color)
+					}
+				}
+			}
+		}
+		cursorX += glyph.DeviceWidth
+	}
+}
+
 func LoadBDF(path string) (*BDFFont, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -0,0 +1,154 @@
+package rgbmatrix
+
+import (
+	"cmp"
+	"image"
+	"image/color"
+	"image/draw"
+	"slices"
+	"sync"
+)
+
+// Layer is an image drawn on top of every frame. Its alpha channel blends it
+// with the frame below, and layers with a higher Z are drawn above lower
+// ones.
+type Layer struct {
+	Image image.Image
+	At    image.Point
+	Z     int
+}
+
+type namedLayer struct {
+	id string
+	Layer
+}
+
+// Layers is a Matrix that composites layers over every rendered frame. The
+// last frame is kept, so that changing a layer shows up immediately even if
+// the renderer below does not draw again.
+type Layers struct {
+	matrix Matrix
+	width  int
+	height int
+
+	mu     sync.Mutex
+	pixels []color.RGBA
+	// base is the last frame rendered below the layers.
+	base   []color.RGBA
+	layers []namedLayer
+	frame  *image.RGBA
+}
+
+func NewLayers(matrix Matrix) *Layers {
+	width, height := matrix.Geometry()
+	return &Layers{
+		matrix: matrix,
+		width:  width,
+		height: height,
+		pixels: make([]color.RGBA, width*height),
+		base:   make([]color.RGBA, width*height),
+		frame:  image.NewRGBA(image.Rect(0, 0, width, height)),
+	}
+}
+
+// SetLayer adds or replaces the layer with the given ID and renders the
+// frame with it.
+func (l *Layers) SetLayer(id string, layer Layer) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.layers = slices.DeleteFunc(l.layers, func(other namedLayer) bool { return other.id == id })
+	l.layers = append(l.layers, namedLayer{id: id, Layer: layer})
+	slices.SortStableFunc(l.layers, func(a, b namedLayer) int {
+		return cmp.Or(cmp.Compare(a.Z, b.Z), cmp.Compare(a.id, b.id))
+	})
+	return l.composeLocked()
+}
+
+// RemoveLayer removes the layer with the given ID and reports whether it
+// existed.
+func (l *Layers) RemoveLayer(id string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	count := len(l.layers)
+	l.layers = slices.DeleteFunc(l.layers, func(other namedLayer) bool { return other.id == id })
+	if len(l.layers) == count {
+		return false, nil
+	}
+	return true, l.composeLocked()
+}
+
+func (l *Layers) Geometry() (width, height int) {
+	return l.width, l.height
+}
+
+func (l *Layers) At(position int) color.Color {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.pixels[position]
+}
+
+func (l *Layers) Set(position int, c color.Color) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pixels[position] = rgba(c)
+}
+
+func (l *Layers) Apply(pixels []color.Color) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for position := range l.pixels {
+		pixel := color.RGBA{}
+		if position < len(pixels) {
+			pixel = rgba(pixels[position])
+		}
+		l.pixels[position] = pixel
+	}
+	return l.renderLocked()
+}
+
+func (l *Layers) Render() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.renderLocked()
+}
+
+func (l *Layers) renderLocked() error {
+	copy(l.base, l.pixels)
+	clear(l.pixels)
+	return l.composeLocked()
+}
+
+// Present shows img as the base frame. Without layers it is passed on to the
+// matrix as is.
+func (l *Layers) Present(img *image.RGBA) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	readFrame(l.base, l.width, l.height, img)
+	clear(l.pixels)
+	if len(l.layers) == 0 {
+		return l.matrix.Present(img)
+	}
+	return l.composeLocked()
+}
+
+// composeLocked draws the layers over the base frame and renders the result.
+func (l *Layers) composeLocked() error {
+	writeFrame(l.frame, l.base)
+	if len(l.layers) == 0 {
+		return l.matrix.Present(l.frame)
+	}
+
+	for position := range l.base {
+		l.frame.Pix[4*position+3] = 255
+	}
+	for _, layer := range l.layers {
+		bounds := layer.Image.Bounds()
+		target := bounds.Sub(bounds.Min).Add(layer.At)
+		draw.Draw(l.frame, target, layer.Image, bounds.Min, draw.Over)
+	}
+	return l.matrix.Present(l.frame)
+}
+
+func (l *Layers) Close() error {
+	return l.matrix.Close()
+}
@@ -0,0 +1,57 @@
+package rgbmatrix
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestLayersCompositeOverFramesByZOrder(t *testing.T) {
+	matrix := &observableMatrix{width: 3, height: 1, pixels: make([]color.Color, 3)}
+	layers := NewLayers(matrix)
+	for position := range 3 {
+		layers.Set(position, transitionBlue)
+	}
+	if err := layers.Render(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A half transparent red layer over the first two pixels, below an
+	// opaque green one on the second pixel.
+	red := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	red.Set(0, 0, color.NRGBA{R: 255, A: 128})
+	red.Set(1, 0, color.NRGBA{R: 255, A: 128})
+	green := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	green.Set(0, 0, color.NRGBA{G: 255, A: 255})
+	if err := layers.SetLayer("green", Layer{Image: green, At: image.Pt(1, 0), Z: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := layers.SetLayer("red", Layer{Image: red, Z: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []color.Color{
+		color.RGBA{R: 128, B: 127, A: 255},
+		color.RGBA{G: 255, A: 255},
+		transitionBlue,
+	}
+	for position, pixel := range want {
+		if matrix.pixels[position] != pixel {
+			t.Fatalf("pixel %d: got %v, want %v", position, matrix.pixels[position], pixel)
+		}
+	}
+	if layers.At(0) != (color.RGBA{}) {
+		t.Fatal("drawing buffer was not cleared after rendering")
+	}
+
+	removed, err := layers.RemoveLayer("green")
+	if err != nil || !removed {
+		t.Fatalf("layer was not removed: %v", err)
+	}
+	if matrix.pixels[1] != want[0] {
+		t.Fatalf("frame was not composed again after removing a layer: %v", matrix.pixels[1])
+	}
+	if removed, _ := layers.RemoveLayer("green"); removed {
+		t.Fatal("missing layer was reported as removed")
+	}
+}
@@ -0,0 +1,76 @@
+package rgbmatrix
+
+import (
+	"fmt"
+	"image"
+	"regexp"
+	"sort"
+)
+
+var regionNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Layout splits the matrix into named regions that show content side by
+// side.
+type Layout struct {
+	Regions []Region `toml:"regions"`
+}
+
+// Region is a rectangle of the matrix in pixels.
+type Region struct {
+	Name   string `toml:"name"`
+	X      int    `toml:"x"`
+	Y      int    `toml:"y"`
+	Width  int    `toml:"width"`
+	Height int    `toml:"height"`
+}
+
+func (r Region) Bounds() image.Rectangle {
+	return image.Rect(r.X, r.Y, r.X+r.Width, r.Y+r.Height)
+}
+
+// Region returns the region with the given name.
+func (l Layout) Region(name string) (Region, bool) {
+	for _, region := range l.Regions {
+		if region.Name == name {
+			return region, true
+		}
+	}
+	return Region{}, false
+}
+
+// Validate checks that the regions have unique names and lie within a
+// matrix of the given size without overlapping.
+func (l Layout) Validate(width, height int) error {
+	if len(l.Regions) == 0 {
+		return fmt.Errorf("layout has no regions")
+	}
+	matrix := image.Rect(0, 0, width, height)
+	for index, region := range l.Regions {
+		if !regionNamePattern.MatchString(region.Name) {
+			return fmt.Errorf("region %d: name %q must be lower-case letters, digits, '-' or '_'", index, region.Name)
+		}
+		bounds := region.Bounds()
+		if region.Width <= 0 || region.Height <= 0 || !bounds.In(matrix) {
+			return fmt.Errorf("region %q: %v is not within the %dx%d matrix", region.Name, bounds, width, height)
+		}
+		for _, other := range l.Regions[:index] {
+			if other.Name == region.Name {
+				return fmt.Errorf("region %q is defined twice", region.Name)
+			}
+			if other.Bounds().Overlaps(bounds) {
+				return fmt.Errorf("region %q overlaps region %q", region.Name, other.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// LayoutNames returns the sorted names of the layouts.
+func LayoutNames(layouts map[string]Layout) []string {
+	names := make([]string, 0, len(layouts))
+	for name := range layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,305 @@
+package rgbmatrix
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+)
+
+const (
+	MirrorNone       = ""
+	MirrorHorizontal = "horizontal"
+	MirrorVertical   = "vertical"
+)
+
+// PixelMapping describes how the image that renderers draw is mapped onto
+// the panels, for panels that are mounted rotated, mirrored or in another
+// arrangement than they are chained. The panels are arranged first, then the
+// image is rotated and finally mirrored.
+type PixelMapping struct {
+	// Panels places every panel on the image. Panels that are not listed
+	// stay dark, and parts of the image without a panel are not shown.
+	Panels []PanelPlacement `toml:"panels"`
+	// UMapper folds each chain in the middle, so that its second half runs
+	// back below the first half. It cannot be combined with Panels.
+	UMapper bool `toml:"u_mapper"`
+	// Rotate turns the image clockwise by 0, 90, 180 or 270 degrees.
+	Rotate int `toml:"rotate"`
+	// Mirror flips the image "horizontal" or "vertical".
+	Mirror string `toml:"mirror"`
+}
+
+// PanelPlacement places one panel on the image.
+type PanelPlacement struct {
+	// Chain is the parallel chain of the panel and Index its position in the
+	// unmapped matrix from the left, both counted from 0.
+	Chain int `toml:"chain"`
+	Index int `toml:"index"`
+	// X and Y are the top left corner of the panel on the image.
+	X int `toml:"x"`
+	Y int `toml:"y"`
+	// Rotate turns the panel clockwise by 0, 90, 180 or 270 degrees.
+	Rotate int `toml:"rotate"`
+}
+
+func (m PixelMapping) isZero() bool {
+	return len(m.Panels) == 0 && !m.UMapper && m.Rotate == 0 && m.Mirror == MirrorNone
+}
+
+// Validate checks the mapping against the panels described by opts.
+func (m PixelMapping) Validate(opts MatrixOptions) error {
+	if err := validateRotation(m.Rotate); err != nil {
+		return err
+	}
+	if m.Mirror != MirrorNone && m.Mirror != MirrorHorizontal && m.Mirror != MirrorVertical {
+		return fmt.Errorf("mirror %q is not %q or %q", m.Mirror, MirrorHorizontal, MirrorVertical)
+	}
+	if m.UMapper {
+		if len(m.Panels) > 0 {
+			return fmt.Errorf("u_mapper cannot be combined with panels")
+		}
+		if opts.ChainLength < 2 || opts.ChainLength%2 != 0 {
+			return fmt.Errorf("u_mapper needs an even chain length, not %d", opts.ChainLength)
+		}
+	}
+	var placed []image.Rectangle
+	for index, panel := range m.Panels {
+		if panel.Chain < 0 || panel.Chain >= opts.Parallel || panel.Index < 0 || panel.Index >= opts.ChainLength {
+			return fmt.Errorf("panel %d: chain %d index %d is not one of the %dx%d panels", index, panel.Chain, panel.Index, opts.Parallel, opts.ChainLength)
+		}
+		if panel.X < 0 || panel.Y < 0 {
+			return fmt.Errorf("panel %d: position %d,%d is negative", index, panel.X, panel.Y)
+		}
+		if err := validateRotation(panel.Rotate); err != nil {
+			return fmt.Errorf("panel %d: %w", index, err)
+		}
+		bounds := panel.bounds(opts)
+		for other, previous := range m.Panels[:index] {
+			if previous.Chain == panel.Chain && previous.Index == panel.Index {
+				return fmt.Errorf("panel %d: chain %d index %d is placed twice", index, panel.Chain, panel.Index)
+			}
+			if placed[other].Overlaps(bounds) {
+				return fmt.Errorf("panel %d overlaps panel %d", index, other)
+			}
+		}
+		placed = append(placed, bounds)
+	}
+	return nil
+}
+
+func validateRotation(degrees int) error {
+	if degrees != 0 && degrees != 90 && degrees != 180 && degrees != 270 {
+		return fmt.Errorf("rotation %d is not 0, 90, 180 or 270", degrees)
+	}
+	return nil
+}
+
+// bounds returns the rectangle the panel covers on the image.
+func (p PanelPlacement) bounds(opts MatrixOptions) image.Rectangle {
+	width, height := rotated(p.Rotate, opts.Cols, opts.Rows)
+	return image.Rect(p.X, p.Y, p.X+width, p.Y+height)
+}
+
+// Geometry returns the size of the image that is mapped onto the panels
+// described by opts.
+func (m PixelMapping) Geometry(opts MatrixOptions) (width, height int) {
+	width, height, _ = m.mapping(opts)
+	return width, height
+}
+
+// mapping returns the size of the image and a function that maps its pixels
+// onto the unmapped matrix, which reports false for pixels without a panel.
+func (m PixelMapping) mapping(opts MatrixOptions) (width, height int, to func(x, y int) (int, int, bool)) {
+	width, height = opts.geometry()
+	to = func(x, y int) (int, int, bool) { return x, y, true }
+
+	switch {
+	case len(m.Panels) > 0:
+		width, height = 0, 0
+		for _, panel := range m.Panels {
+			bounds := panel.bounds(opts)
+			width, height = max(width, bounds.Max.X), max(height, bounds.Max.Y)
+		}
+		to = func(x, y int) (int, int, bool) {
+			point := image.Pt(x, y)
+			for _, panel := range m.Panels {
+				bounds := panel.bounds(opts)
+				if !point.In(bounds) {
+					continue
+				}
+				x, y := rotate(panel.Rotate, opts.Cols, opts.Rows, x-bounds.Min.X, y-bounds.Min.Y)
+				return panel.Index*opts.Cols + x, panel.Chain*opts.Rows + y, true
+			}
+			return 0, 0, false
+		}
+	case m.UMapper:
+		matrixWidth, rows := width, opts.Rows
+		width, height = width/2, 2*height
+		to = func(x, y int) (int, int, bool) {
+			// Each chain becomes a slab of two panel rows, the upper one is
+			// the second half of the chain and the lower one the first half
+			// turned upside down.
+			slab := y / (2 * rows) * rows
+			y %= 2 * rows
+			if y < rows {
+				return x + matrixWidth/2, slab + y, true
+			}
+			return width - x - 1, slab + 2*rows - y - 1, true
+		}
+	}
+
+	arranged, arrangedWidth, arrangedHeight := to, width, height
+	width, height = rotated(m.Rotate, width, height)
+	if m.Rotate != 0 {
+		to = func(x, y int) (int, int, bool) {
+			return arranged(rotate(m.Rotate, arrangedWidth, arrangedHeight, x, y))
+		}
+	}
+
+	unmirrored, mirroredWidth, mirroredHeight := to, width, height
+	switch m.Mirror {
+	case MirrorHorizontal:
+		to = func(x, y int) (int, int, bool) { return unmirrored(mirroredWidth-1-x, y) }
+	case MirrorVertical:
+		to = func(x, y int) (int, int, bool) { return unmirrored(x, mirroredHeight-1-y) }
+	}
+	return width, height, to
+}
+
+// rotated returns the size of a width by height image turned by degrees.
+func rotated(degrees, width, height int) (int, int) {
+	if degrees%180 != 0 {
+		return height, width
+	}
+	return width, height
+}
+
+// rotate maps the pixel x, y of an image that is a width by height matrix
+// turned clockwise by degrees back onto the matrix.
+func rotate(degrees, width, height, x, y int) (int, int) {
+	switch degrees {
+	case 90:
+		return width - y - 1, x
+	case 180:
+		return width - x - 1, height - y - 1
+	case 270:
+		return y, height - x - 1
+	}
+	return x, y
+}
+
+// Mapper is a Matrix that maps its pixels onto the pixels of another matrix
+// as described by a PixelMapping. Renderers draw on the mapped image while
+// the wrapped matrix gets the pixels in the order of its panels.
+type Mapper struct {
+	matrix Matrix
+	width  int
+	height int
+	// positions holds the matrix position of every pixel, or -1 for pixels
+	// without a panel.
+	positions []int
+	// frame is presented to the matrix. Pixels without a mapped pixel are
+	// never written and stay black.
+	frame *image.RGBA
+	mu    sync.Mutex
+}
+
+// NewMapper maps matrix, which has the panels described by opts, with
+// mapping. Without a mapping it returns matrix unchanged. The returned matrix
+// is Dimmable if matrix is.
+func NewMapper(matrix Matrix, mapping PixelMapping, opts MatrixOptions) (Matrix, error) {
+	if mapping.isZero() {
+		return matrix, nil
+	}
+	if err := mapping.Validate(opts); err != nil {
+		return nil, err
+	}
+	matrixWidth, matrixHeight := matrix.Geometry()
+	if width, height := opts.geometry(); width != matrixWidth || height != matrixHeight {
+		return nil, fmt.Errorf("pixel mapping for a %dx%d matrix cannot map a %dx%d matrix", width, height, matrixWidth, matrixHeight)
+	}
+
+	width, height, to := mapping.mapping(opts)
+	m := &Mapper{
+		matrix:    matrix,
+		width:     width,
+		height:    height,
+		positions: make([]int, width*height),
+		frame:     image.NewRGBA(image.Rect(0, 0, matrixWidth, matrixHeight)),
+	}
+	for position := range m.positions {
+		m.positions[position] = -1
+		x, y, ok := to(position%width, position/width)
+		if ok && x >= 0 && x < matrixWidth && y >= 0 && y < matrixHeight {
+			m.positions[position] = y*matrixWidth + x
+		}
+	}
+
+	if dimmable, ok := matrix.(Dimmable); ok {
+		return &dimmableMapper{Mapper: m, Dimmable: dimmable}, nil
+	}
+	return m, nil
+}
+
+// dimmableMapper passes brightness changes on to the mapped matrix.
+type dimmableMapper struct {
+	*Mapper
+	Dimmable
+}
+
+func (m *Mapper) Geometry() (width, height int) {
+	return m.width, m.height
+}
+
+func (m *Mapper) At(position int) color.Color {
+	if to := m.positions[position]; to >= 0 {
+		return m.matrix.At(to)
+	}
+	return color.RGBA{}
+}
+
+func (m *Mapper) Set(position int, c color.Color) {
+	if to := m.positions[position]; to >= 0 {
+		m.matrix.Set(to, c)
+	}
+}
+
+func (m *Mapper) Apply(pixels []color.Color) error {
+	width, height := m.matrix.Geometry()
+	mapped := make([]color.Color, width*height)
+	for position := range mapped {
+		mapped[position] = color.RGBA{}
+	}
+	for position, to := range m.positions {
+		if to >= 0 && position < len(pixels) {
+			mapped[to] = pixels[position]
+		}
+	}
+	return m.matrix.Apply(mapped)
+}
+
+func (m *Mapper) Render() error {
+	return m.matrix.Render()
+}
+
+// Present maps img onto the frame of the matrix and presents it.
+func (m *Mapper) Present(img *image.RGBA) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if img.Rect != image.Rect(0, 0, m.width, m.height) {
+		clear(m.frame.Pix)
+	}
+	frameRows(img, m.width, m.height, func(position int, pix []uint8) {
+		for x, to := range m.positions[position : position+len(pix)/4] {
+			if to >= 0 {
+				copy(m.frame.Pix[4*to:4*to+4], pix[4*x:4*x+4])
+			}
+		}
+	})
+	return m.matrix.Present(m.frame)
+}
+
+func (m *Mapper) Close() error {
+	return m.matrix.Close()
+}
@@ -0,0 +1,147 @@
+package rgbmatrix
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/display"
+)
+
+// mappedPositions presents an image whose red channel numbers its pixels
+// from 1 and returns the number shown by every pixel of the matrix, or 0 for
+// pixels that show nothing.
+func mappedPositions(t *testing.T, mapping PixelMapping, opts MatrixOptions) (width, height int, shown []int) {
+	t.Helper()
+	var frame display.Frame
+	matrixWidth, matrixHeight := opts.geometry()
+	observable := NewObservable(NewMemory(matrixWidth, matrixHeight), func(published display.Frame) { frame = published })
+	mapper, err := NewMapper(observable, mapping, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	width, height = mapper.Geometry()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for position := range width * height {
+		img.SetRGBA(position%width, position/width, color.RGBA{R: uint8(position + 1), A: 255})
+	}
+	if err := mapper.Present(img); err != nil {
+		t.Fatal(err)
+	}
+	for position := range matrixWidth * matrixHeight {
+		shown = append(shown, int(frame.Pixels[3*position]))
+	}
+	return width, height, shown
+}
+
+func TestMapperTransformsImage(t *testing.T) {
+	// Two chained 3x2 panels make a 6x2 matrix.
+	opts := MatrixOptions{Rows: 2, Cols: 3, ChainLength: 2, Parallel: 1}
+	tests := []struct {
+		name          string
+		mapping       PixelMapping
+		width, height int
+		shown         []int
+	}{
+		{"rotate 90", PixelMapping{Rotate: 90}, 2, 6, []int{
+			11, 9, 7, 5, 3, 1,
+			12, 10, 8, 6, 4, 2,
+		}},
+		{"rotate 180", PixelMapping{Rotate: 180}, 6, 2, []int{
+			12, 11, 10, 9, 8, 7,
+			6, 5, 4, 3, 2, 1,
+		}},
+		{"rotate 270", PixelMapping{Rotate: 270}, 2, 6, []int{
+			2, 4, 6, 8, 10, 12,
+			1, 3, 5, 7, 9, 11,
+		}},
+		{"mirror horizontal", PixelMapping{Mirror: MirrorHorizontal}, 6, 2, []int{
+			6, 5, 4, 3, 2, 1,
+			12, 11, 10, 9, 8, 7,
+		}},
+		{"mirror vertical", PixelMapping{Mirror: MirrorVertical}, 6, 2, []int{
+			7, 8, 9, 10, 11, 12,
+			1, 2, 3, 4, 5, 6,
+		}},
+		{"u-mapper", PixelMapping{UMapper: true}, 3, 4, []int{
+			12, 11, 10, 1, 2, 3,
+			9, 8, 7, 4, 5, 6,
+		}},
+		{"panels", PixelMapping{Panels: []PanelPlacement{
+			{Index: 1, X: 0, Y: 0},
+			{Index: 0, X: 3, Y: 0, Rotate: 180},
+		}}, 6, 2, []int{
+			12, 11, 10, 1, 2, 3,
+			6, 5, 4, 7, 8, 9,
+		}},
+		{"single panel", PixelMapping{Panels: []PanelPlacement{{Index: 1, Y: 1, Rotate: 90}}}, 2, 4, []int{
+			0, 0, 0, 7, 5, 3,
+			0, 0, 0, 8, 6, 4,
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			width, height, shown := mappedPositions(t, test.mapping, opts)
+			if width != test.width || height != test.height {
+				t.Fatalf("geometry = %dx%d, want %dx%d", width, height, test.width, test.height)
+			}
+			for position := range shown {
+				if shown[position] != test.shown[position] {
+					t.Fatalf("shown = %v, want %v", shown, test.shown)
+				}
+			}
+		})
+	}
+}
+
+func TestMapperSetsMappedPixels(t *testing.T) {
+	opts := MatrixOptions{Rows: 2, Cols: 3, ChainLength: 2, Parallel: 1}
+	var frame display.Frame
+	observable := NewObservable(NewMemory(6, 2), func(published display.Frame) { frame = published })
+	mapper, err := NewMapper(observable, PixelMapping{Rotate: 180}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapper.Set(1, color.RGBA{R: 9, A: 255})
+	if mapper.At(1) != (color.RGBA{R: 9, A: 255}) {
+		t.Fatalf("At(1) = %v", mapper.At(1))
+	}
+	if err := mapper.Render(); err != nil {
+		t.Fatal(err)
+	}
+	if frame.Pixels[3*10] != 9 {
+		t.Fatalf("pixel 1 is not shown at matrix position 10: %v", frame.Pixels)
+	}
+	if _, ok := mapper.(Dimmable); !ok {
+		t.Fatal("mapper of a Dimmable matrix is not Dimmable")
+	}
+}
+
+func TestPixelMappingValidate(t *testing.T) {
+	opts := MatrixOptions{Rows: 32, Cols: 64, ChainLength: 3, Parallel: 1}
+	tests := []struct {
+		mapping PixelMapping
+		err     string
+	}{
+		{PixelMapping{Rotate: 45}, "rotation 45"},
+		{PixelMapping{Mirror: "diagonal"}, "mirror \"diagonal\""},
+		{PixelMapping{UMapper: true}, "even chain length"},
+		{PixelMapping{Panels: []PanelPlacement{{Index: 3}}}, "not one of the 1x3 panels"},
+		{PixelMapping{Panels: []PanelPlacement{{Index: 0}, {Index: 0, Y: 32}}}, "placed twice"},
+		{PixelMapping{Panels: []PanelPlacement{{Index: 0}, {Index: 1, X: 32}}}, "overlaps panel 0"},
+		{PixelMapping{Panels: []PanelPlacement{{Index: 0, Rotate: 90}, {Index: 1, X: 32}}}, ""},
+	}
+	for _, test := range tests {
+		err := test.mapping.Validate(opts)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("Validate(%+v) = %v", test.mapping, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("Validate(%+v) = %v, want %q", test.mapping, err, test.err)
+		}
+	}
+}
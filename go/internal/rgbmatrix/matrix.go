@@ -1,6 +1,11 @@
 package rgbmatrix
 
-import "image/color"
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+)
 
 // Matrix is an interface that represent any RGB matrix, very useful for testing
 type Matrix interface {
@@ -9,5 +14,70 @@ type Matrix interface {
 	Set(position int, c color.Color)
 	Apply([]color.Color) error
 	Render() error
+	// Present shows a whole frame at once, like setting every pixel and
+	// rendering, and clears the drawing buffer. Pixels that img does not
+	// cover are black. The matrix does not keep img after Present returns.
+	Present(img *image.RGBA) error
 	Close() error
 }
+
+var ErrInvalidBrightness = errors.New("invalid brightness")
+
+// Dimmable is implemented by matrices whose brightness can change while they
+// are running. Brightness is a percentage from 0 (off) to 100. Changing it
+// takes effect immediately, also for content that is not rendered again.
+type Dimmable interface {
+	Brightness() int
+	SetBrightness(brightness int) error
+}
+
+func validateBrightness(brightness int) error {
+	if brightness < 0 || brightness > 100 {
+		return fmt.Errorf("%w: %d is not between 0 and 100", ErrInvalidBrightness, brightness)
+	}
+	return nil
+}
+
+// dim scales a color by a brightness percentage.
+func dim(c color.RGBA, brightness int) color.RGBA {
+	if brightness >= 100 {
+		return c
+	}
+	return color.RGBA{
+		R: uint8(int(c.R) * brightness / 100),
+		G: uint8(int(c.G) * brightness / 100),
+		B: uint8(int(c.B) * brightness / 100),
+		A: c.A,
+	}
+}
+
+// frameRows calls row for every row of img that lies within a width by
+// height matrix, with the position of its first pixel and its RGBA bytes.
+func frameRows(img *image.RGBA, width, height int, row func(position int, pix []uint8)) {
+	bounds := img.Rect.Intersect(image.Rect(0, 0, width, height))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		start := img.PixOffset(bounds.Min.X, y)
+		row(y*width+bounds.Min.X, img.Pix[start:start+4*bounds.Dx()])
+	}
+}
+
+// readFrame copies img into the pixels of a width by height matrix.
+func readFrame(pixels []color.RGBA, width, height int, img *image.RGBA) {
+	if img.Rect != image.Rect(0, 0, width, height) {
+		clear(pixels)
+	}
+	frameRows(img, width, height, func(position int, pix []uint8) {
+		row := pixels[position : position+len(pix)/4]
+		for x := range row {
+			row[x] = color.RGBA{R: pix[4*x], G: pix[4*x+1], B: pix[4*x+2], A: pix[4*x+3]}
+		}
+	})
+}
+
+// writeFrame copies the pixels of a matrix into img, which has its size.
+func writeFrame(img *image.RGBA, pixels []color.RGBA) {
+	for position, pixel := range pixels {
+		pix := img.Pix[4*position : 4*position+4 : 4*position+4]
+		pix[0], pix[1], pix[2], pix[3] = pixel.R, pixel.G, pixel.B, pixel.A
+	}
+}
@@ -26,8 +26,10 @@ void led_matrix_swap(struct RGBLedMatrix *matrix, struct LedCanvas *offscreen_ca
 import "C"
 import (
 	"fmt"
+	"image"
 	"image/color"
 	"os"
+	"sync"
 	"unsafe"
 )
 
@@ -40,6 +42,14 @@ type RGBLedMatrix struct {
 	matrix *C.struct_RGBLedMatrix
 	buffer *C.struct_LedCanvas
 	leds   []C.uint32_t
+
+	mu sync.Mutex
+	// shown is the last rendered frame. The library applies brightness while
+	// copying pixels to the canvas, so it is swapped in again when the
+	// brightness changes.
+	shown []C.uint32_t
+	// off is set for brightness 0, which the library clamps to 1%.
+	off bool
 }
 
 const MatrixEmulatorENV = "MATRIX_EMULATOR"
@@ -56,18 +66,23 @@ func NewRGBLedMatrix(opts *MatrixOptions, rOpts *RuntimeOptions) (c Matrix, err
 		}
 	}()
 
-	w, h := opts.geometry()
 	m := C.led_matrix_create_from_options_and_rt_options(opts.toC(), rOpts.toC())
+	if m == nil {
+		return nil, fmt.Errorf("unable to allocate memory")
+	}
 	b := C.led_matrix_create_offscreen_canvas(m)
+
+	// The pixel mapper of the library can change the geometry.
+	var cw, ch C.int
+	C.led_canvas_get_size(b, &cw, &ch)
+	w, h := int(cw), int(ch)
 	c = &RGBLedMatrix{
 		Config: opts,
 		width:  w, height: h,
 		matrix: m,
 		buffer: b,
 		leds:   make([]C.uint32_t, w*h),
-	}
-	if m == nil {
-		return nil, fmt.Errorf("unable to allocate memory")
+		shown:  make([]C.uint32_t, w*h),
 	}
 
 	return c, nil
@@ -103,30 +118,90 @@ func (c *RGBLedMatrix) Apply(leds []color.Color) error {
 
 // Render update the display with the data from the LED buffer
 func (c *RGBLedMatrix) Render() error {
-	w, h := c.Config.geometry()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.shown, c.leds = c.leds, c.shown
+	c.swapLocked()
+	clear(c.leds)
+	return nil
+}
+
+// Present converts img into the frame to show and swaps it in, without going
+// through Set for every pixel.
+func (c *RGBLedMatrix) Present(img *image.RGBA) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.shown)
+	frameRows(img, c.width, c.height, func(position int, pix []uint8) {
+		row := c.shown[position : position+len(pix)/4]
+		for x := range row {
+			row[x] = C.uint32_t(pix[4*x])<<16 | C.uint32_t(pix[4*x+1])<<8 | C.uint32_t(pix[4*x+2])
+		}
+	})
+	c.swapLocked()
+	clear(c.leds)
+	return nil
+}
+
+func (c *RGBLedMatrix) swapLocked() {
+	pixels := c.shown
+	if c.off {
+		pixels = make([]C.uint32_t, c.width*c.height)
+	}
 
 	C.led_matrix_swap(
 		c.matrix,
 		c.buffer,
-		C.int(w), C.int(h),
-		(*C.uint32_t)(unsafe.Pointer(&c.leds[0])),
+		C.int(c.width), C.int(c.height),
+		(*C.uint32_t)(unsafe.Pointer(&pixels[0])),
 	)
-
-	c.leds = make([]C.uint32_t, w*h)
-	return nil
 }
 
 // At return an Color which allows access to the LED display data as
 // if it were a sequence of 24-bit RGB values.
 func (c *RGBLedMatrix) At(position int) color.Color {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return uint32ToColor(c.leds[position])
 }
 
 // Set set LED at position x,y to the provided 24-bit color value.
 func (c *RGBLedMatrix) Set(position int, color color.Color) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.leds[position] = C.uint32_t(colorToUint32(color))
 }
 
+// Brightness returns the current brightness in percent.
+func (c *RGBLedMatrix) Brightness() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.off {
+		return 0
+	}
+	return int(C.led_matrix_get_brightness(c.matrix))
+}
+
+// SetBrightness changes the brightness of the panels and shows the last
+// rendered frame again with the new brightness.
+func (c *RGBLedMatrix) SetBrightness(brightness int) error {
+	if err := validateBrightness(brightness); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.off = brightness == 0
+	if !c.off {
+		C.led_matrix_set_brightness(c.matrix, C.uint8_t(brightness))
+		c.Config.Brightness = brightness
+	}
+	c.swapLocked()
+	return nil
+}
+
 // Close finalizes the ws281x interface
 func (c *RGBLedMatrix) Close() error {
 	C.led_matrix_delete(c.matrix)
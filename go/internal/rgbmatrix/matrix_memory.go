@@ -1,24 +1,28 @@
 package rgbmatrix
 
 import (
+	"image"
 	"image/color"
 	"sync"
 )
 
 // Memory is a headless matrix output. It accepts the same drawing operations
-// as a real matrix while leaving presentation to wrappers such as Observable.
+// as a real matrix while leaving presentation to wrappers such as Observable,
+// which also apply its brightness.
 type Memory struct {
-	width  int
-	height int
-	pixels []color.RGBA
-	mu     sync.Mutex
+	width      int
+	height     int
+	pixels     []color.RGBA
+	brightness int
+	mu         sync.Mutex
 }
 
 func NewMemory(width, height int) *Memory {
 	return &Memory{
-		width:  width,
-		height: height,
-		pixels: make([]color.RGBA, width*height),
+		width:      width,
+		height:     height,
+		pixels:     make([]color.RGBA, width*height),
+		brightness: 100,
 	}
 }
 
@@ -59,6 +63,28 @@ func (m *Memory) Render() error {
 	return nil
 }
 
+// Present discards img. Memory keeps no rendered frames, so presenting one
+// only clears the drawing buffer.
+func (m *Memory) Present(img *image.RGBA) error {
+	return m.Render()
+}
+
+func (m *Memory) Brightness() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.brightness
+}
+
+func (m *Memory) SetBrightness(brightness int) error {
+	if err := validateBrightness(brightness); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.brightness = brightness
+	return nil
+}
+
 func (m *Memory) Close() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -3,6 +3,7 @@ package rgbmatrix
 import (
 	"bytes"
 	"fmt"
+	"image"
 	"image/color"
 	"io"
 	"os"
@@ -23,9 +24,13 @@ type Terminal struct {
 	pixels []color.RGBA
 	out    io.Writer
 
-	mu      sync.Mutex
-	started bool
-	closed  bool
+	mu         sync.Mutex
+	started    bool
+	closed     bool
+	brightness int
+	// shown is the last rendered frame, kept to repaint it when the
+	// brightness changes.
+	shown []color.RGBA
 }
 
 func NewTerminal(width, height int) *Terminal {
@@ -34,10 +39,12 @@ func NewTerminal(width, height int) *Terminal {
 
 func NewTerminalWithWriter(width, height int, out io.Writer) *Terminal {
 	return &Terminal{
-		width:  width,
-		height: height,
-		pixels: make([]color.RGBA, width*height),
-		out:    out,
+		width:      width,
+		height:     height,
+		pixels:     make([]color.RGBA, width*height),
+		out:        out,
+		brightness: 100,
+		shown:      make([]color.RGBA, width*height),
 	}
 }
 
@@ -77,7 +84,23 @@ func (t *Terminal) Render() error {
 	return t.renderLocked()
 }
 
+func (t *Terminal) Present(img *image.RGBA) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	readFrame(t.pixels, t.width, t.height, img)
+	return t.renderLocked()
+}
+
 func (t *Terminal) renderLocked() error {
+	if err := t.writeLocked(t.pixels); err != nil {
+		return err
+	}
+	copy(t.shown, t.pixels)
+	clear(t.pixels)
+	return nil
+}
+
+func (t *Terminal) writeLocked(pixels []color.RGBA) error {
 	if t.closed {
 		return fmt.Errorf("terminal matrix is closed")
 	}
@@ -92,10 +115,10 @@ func (t *Terminal) renderLocked() error {
 
 	for y := 0; y < t.height; y += 2 {
 		for x := 0; x < t.width; x++ {
-			upper := t.pixels[x+y*t.width]
+			upper := dim(pixels[x+y*t.width], t.brightness)
 			lower := color.RGBA{}
 			if y+1 < t.height {
-				lower = t.pixels[x+(y+1)*t.width]
+				lower = dim(pixels[x+(y+1)*t.width], t.brightness)
 			}
 
 			fmt.Fprintf(
@@ -114,11 +137,30 @@ func (t *Terminal) renderLocked() error {
 	if _, err := t.out.Write(frame.Bytes()); err != nil {
 		return fmt.Errorf("render terminal matrix: %w", err)
 	}
-
-	clear(t.pixels)
 	return nil
 }
 
+func (t *Terminal) Brightness() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.brightness
+}
+
+// SetBrightness scales the colors written to the terminal and repaints the
+// last rendered frame.
+func (t *Terminal) SetBrightness(brightness int) error {
+	if err := validateBrightness(brightness); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.brightness = brightness
+	if !t.started || t.closed {
+		return nil
+	}
+	return t.writeLocked(t.shown)
+}
+
 func (t *Terminal) Close() error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -43,3 +43,20 @@ func TestTerminalGeometry(t *testing.T) {
 		t.Fatalf("unexpected geometry: %dx%d", width, height)
 	}
 }
+
+func TestTerminalBrightnessRepaintsLastFrame(t *testing.T) {
+	var output bytes.Buffer
+	matrix := NewTerminalWithWriter(1, 1, &output)
+	matrix.Set(0, color.RGBA{R: 200, A: 255})
+	if err := matrix.Render(); err != nil {
+		t.Fatal(err)
+	}
+
+	output.Reset()
+	if err := matrix.SetBrightness(50); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output.String(), "\x1b[38;2;100;0;0m") {
+		t.Fatalf("frame was not repainted dimmed: %q", output.String())
+	}
+}
@@ -0,0 +1,149 @@
+package rgbmatrix
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"io"
+	"testing"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/display"
+)
+
+// testFrame returns a width by height gradient.
+func testFrame(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(x * 7), G: uint8(y * 13), B: uint8(x ^ y), A: 255})
+		}
+	}
+	return img
+}
+
+// setFrame shows img the slow way, pixel by pixel.
+func setFrame(m Matrix, img *image.RGBA) error {
+	width, height := m.Geometry()
+	for position := range width * height {
+		m.Set(position, img.At(position%width, position/width))
+	}
+	return m.Render()
+}
+
+// displayStack wraps a matrix like the servers do and returns the frames it
+// published.
+func displayStack(m Matrix) (Matrix, *[]display.Frame) {
+	var frames []display.Frame
+	observable := NewObservable(m, func(frame display.Frame) { frames = append(frames, frame) })
+	return NewTransitions(NewLayers(observable)), &frames
+}
+
+func TestPresentMatchesSetAndRender(t *testing.T) {
+	img := testFrame(6, 4)
+
+	var set, presented bytes.Buffer
+	setTerminal, presentTerminal := NewTerminalWithWriter(6, 4, &set), NewTerminalWithWriter(6, 4, &presented)
+	if err := setFrame(setTerminal, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := presentTerminal.Present(img); err != nil {
+		t.Fatal(err)
+	}
+	if set.String() != presented.String() {
+		t.Fatal("terminal output of Present differs from Set and Render")
+	}
+
+	setStack, setFrames := displayStack(NewMemory(6, 4))
+	presentStack, presentedFrames := displayStack(NewMemory(6, 4))
+	if err := setFrame(setStack, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := presentStack.Present(img); err != nil {
+		t.Fatal(err)
+	}
+	if len(*setFrames) != 1 || len(*presentedFrames) != 1 || !bytes.Equal((*setFrames)[0].Pixels, (*presentedFrames)[0].Pixels) {
+		t.Fatalf("published frames differ: %v, %v", *setFrames, *presentedFrames)
+	}
+	if presentStack.At(0) != (color.RGBA{}) {
+		t.Fatal("drawing buffer was not cleared by Present")
+	}
+}
+
+func TestReadFrameBlanksUncoveredPixels(t *testing.T) {
+	pixels := []color.RGBA{{R: 9}, {R: 9}, {R: 9}, {R: 9}}
+	img := image.NewRGBA(image.Rect(1, 0, 3, 1))
+	img.SetRGBA(1, 0, color.RGBA{G: 1, A: 255})
+	img.SetRGBA(2, 0, color.RGBA{G: 2, A: 255})
+
+	readFrame(pixels, 2, 2, img)
+
+	want := []color.RGBA{{}, {G: 1, A: 255}, {}, {}}
+	for position := range want {
+		if pixels[position] != want[position] {
+			t.Fatalf("pixels = %v, want %v", pixels, want)
+		}
+	}
+}
+
+func BenchmarkFrame(b *testing.B) {
+	const width, height = 192, 96
+	img := testFrame(width, height)
+	matrices := []struct {
+		name   string
+		matrix func() Matrix
+	}{
+		{"Memory", func() Matrix { return NewMemory(width, height) }},
+		{"Terminal", func() Matrix { return NewTerminalWithWriter(width, height, io.Discard) }},
+		{"Observable", func() Matrix { return NewObservable(NewMemory(width, height), func(display.Frame) {}) }},
+		{"Stack", func() Matrix { stack, _ := displayStack(NewMemory(width, height)); return stack }},
+	}
+	for _, m := range matrices {
+		b.Run(m.name+"/Set", func(b *testing.B) {
+			matrix := m.matrix()
+			for range b.N {
+				if err := setFrame(matrix, img); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(m.name+"/Present", func(b *testing.B) {
+			matrix := m.matrix()
+			for range b.N {
+				if err := matrix.Present(img); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkScreen compares drawing a frame through the canvas with drawing it
+// into the back buffer of the screen.
+func BenchmarkScreen(b *testing.B) {
+	const width, height = 192, 96
+	img := testFrame(width, height)
+	b.Run("Canvas", func(b *testing.B) {
+		stack, _ := displayStack(NewMemory(width, height))
+		screen := NewScreen(stack)
+		for range b.N {
+			for y := 0; y < height; y++ {
+				for x := 0; x < width; x++ {
+					screen.Canvas.Set(x, y, img.At(x, y))
+				}
+			}
+			if err := screen.Canvas.Render(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Frame", func(b *testing.B) {
+		stack, _ := displayStack(NewMemory(width, height))
+		screen := NewScreen(stack)
+		for range b.N {
+			copy(screen.Frame().Pix, img.Pix)
+			if err := screen.Present(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package rgbmatrix
 
 import (
+	"image"
 	"image/color"
 	"sync"
 
@@ -9,7 +10,7 @@ import (
 
 // Observable mirrors matrix writes so that each successful render can be
 // published as a complete frame before the underlying matrix clears its
-// drawing buffer.
+// drawing buffer. Published frames are dimmed like the physical output.
 type Observable struct {
 	matrix  Matrix
 	publish func(display.Frame)
@@ -17,16 +18,23 @@ type Observable struct {
 	height  int
 	pixels  []color.RGBA
 	mu      sync.Mutex
+	// brightness is only used when the wrapped matrix is not Dimmable.
+	brightness int
+	// shown is the last rendered frame, kept to republish it when the
+	// brightness changes.
+	shown []color.RGBA
 }
 
 func NewObservable(matrix Matrix, publish func(display.Frame)) *Observable {
 	width, height := matrix.Geometry()
 	return &Observable{
-		matrix:  matrix,
-		publish: publish,
-		width:   width,
-		height:  height,
-		pixels:  make([]color.RGBA, width*height),
+		matrix:     matrix,
+		publish:    publish,
+		width:      width,
+		height:     height,
+		pixels:     make([]color.RGBA, width*height),
+		brightness: 100,
+		shown:      make([]color.RGBA, width*height),
 	}
 }
 
@@ -69,20 +77,71 @@ func (o *Observable) Render() error {
 	return o.renderLocked()
 }
 
+// Present passes img on to the wrapped matrix and publishes it.
+func (o *Observable) Present(img *image.RGBA) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if err := o.matrix.Present(img); err != nil {
+		return err
+	}
+	readFrame(o.shown, o.width, o.height, img)
+	o.publishLocked()
+	clear(o.pixels)
+	return nil
+}
+
 func (o *Observable) renderLocked() error {
-	pixels := make([]byte, len(o.pixels)*3)
-	for position, pixel := range o.pixels {
+	if err := o.matrix.Render(); err != nil {
+		return err
+	}
+	copy(o.shown, o.pixels)
+	o.publishLocked()
+	clear(o.pixels)
+	return nil
+}
+
+func (o *Observable) publishLocked() {
+	brightness := o.brightnessLocked()
+	pixels := make([]byte, len(o.shown)*3)
+	for position, pixel := range o.shown {
+		pixel = dim(pixel, brightness)
 		offset := position * 3
 		pixels[offset] = pixel.R
 		pixels[offset+1] = pixel.G
 		pixels[offset+2] = pixel.B
 	}
+	o.publish(display.Frame{Width: o.width, Height: o.height, Pixels: pixels})
+}
 
-	if err := o.matrix.Render(); err != nil {
+func (o *Observable) Brightness() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.brightnessLocked()
+}
+
+func (o *Observable) brightnessLocked() int {
+	if dimmable, ok := o.matrix.(Dimmable); ok {
+		return dimmable.Brightness()
+	}
+	return o.brightness
+}
+
+// SetBrightness dims the wrapped matrix, or applies the brightness in
+// software when it is not Dimmable, and republishes the last frame.
+func (o *Observable) SetBrightness(brightness int) error {
+	if err := validateBrightness(brightness); err != nil {
 		return err
 	}
-	o.publish(display.Frame{Width: o.width, Height: o.height, Pixels: pixels})
-	clear(o.pixels)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if dimmable, ok := o.matrix.(Dimmable); ok {
+		if err := dimmable.SetBrightness(brightness); err != nil {
+			return err
+		}
+	} else {
+		o.brightness = brightness
+	}
+	o.publishLocked()
 	return nil
 }
 
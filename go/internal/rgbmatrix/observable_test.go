@@ -2,6 +2,7 @@ package rgbmatrix
 
 import (
 	"errors"
+	"image"
 	"image/color"
 	"testing"
 
@@ -54,4 +55,45 @@ func (m *observableMatrix) Apply(pixels []color.Color) error {
 	return m.Render()
 }
 func (m *observableMatrix) Render() error { return m.renderErr }
-func (m *observableMatrix) Close() error  { return nil }
+func (m *observableMatrix) Present(img *image.RGBA) error {
+	for position := range m.pixels {
+		m.pixels[position] = img.At(position%m.width, position/m.width)
+	}
+	return m.Render()
+}
+func (m *observableMatrix) Close() error { return nil }
+
+func TestObservableDimsAndRepublishesWithoutDimmableMatrix(t *testing.T) {
+	matrix := &observableMatrix{width: 1, height: 1, pixels: make([]color.Color, 1)}
+	var published display.Frame
+	observable := NewObservable(matrix, func(frame display.Frame) { published = frame })
+	observable.Set(0, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+	if err := observable.Render(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := observable.SetBrightness(50); err != nil {
+		t.Fatal(err)
+	}
+	if got := observable.Brightness(); got != 50 {
+		t.Fatalf("unexpected brightness: %d", got)
+	}
+	want := []byte{100, 50, 25}
+	if string(published.Pixels) != string(want) {
+		t.Fatalf("last frame was not republished dimmed: got %v, want %v", published.Pixels, want)
+	}
+}
+
+func TestObservableDelegatesBrightnessToDimmableMatrix(t *testing.T) {
+	memory := NewMemory(1, 1)
+	observable := NewObservable(memory, func(display.Frame) {})
+	if err := observable.SetBrightness(30); err != nil {
+		t.Fatal(err)
+	}
+	if got := memory.Brightness(); got != 30 {
+		t.Fatalf("brightness was not delegated: %d", got)
+	}
+	if err := observable.SetBrightness(101); !errors.Is(err, ErrInvalidBrightness) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -1,5 +1,12 @@
 package rgbmatrix
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
 type ScanMode int8
 
 const (
@@ -8,8 +15,33 @@ const (
 )
 
 type RuntimeOptions struct {
-	// GPIOSlowdown is the slowdown factor for GPIO access.
+	// GPIOSlowdown is the slowdown factor for GPIO access. 0 uses the default
+	// of the library, which is 2 on a Pi 4 and 1 otherwise, and -1 a memory
+	// barrier where that is supported.
 	GPIOSlowdown int `toml:"gpio_slowdown"`
+
+	// Daemon makes the library fork into the background. A forked Go program
+	// stops working, so it is rejected; run the server as a service instead.
+	Daemon bool `toml:"daemon"`
+
+	// DropPrivileges drops the privileges from root to DropPrivUser and
+	// DropPrivGroup once the hardware is initialized. Both are names or IDs
+	// and default to "daemon".
+	DropPrivileges bool   `toml:"drop_privileges"`
+	DropPrivUser   string `toml:"drop_priv_user"`
+	DropPrivGroup  string `toml:"drop_priv_group"`
+
+	// DoGPIOInit initializes the GPIO. The library only takes options that
+	// are set, so it cannot be turned off through the C API.
+	DoGPIOInit bool `toml:"do_gpio_init"`
+}
+
+// DefaultRuntimeOptions are the runtime options of the library.
+var DefaultRuntimeOptions = RuntimeOptions{
+	DropPrivileges: true,
+	DropPrivUser:   "daemon",
+	DropPrivGroup:  "daemon",
+	DoGPIOInit:     true,
 }
 
 // DefaultConfig default WS281x configuration
@@ -24,6 +56,7 @@ var DefaultConfig = MatrixOptions{
 	PWMLSBNanoseconds: 130,
 	Brightness:        100,
 	ScanMode:          Progressive,
+	LEDRGBSequence:    "RGB",
 }
 
 // MatrixOptions rgb-led-matrix configuration
@@ -79,10 +112,84 @@ type MatrixOptions struct {
 	LimitRefreshRateHz int  `toml:"limit_refresh_rate_hz"`
 	InverseColors      bool `toml:"inverse_colors"`
 
+	// DisableBusyWaiting sleeps instead of busy waiting when the refresh rate
+	// is limited, which is less accurate but leaves the CPU to others.
+	DisableBusyWaiting bool `toml:"disable_busy_waiting"`
+
+	// LEDRGBSequence is the order of the colors on panels that mix them up,
+	// such as "RBG".
+	LEDRGBSequence string `toml:"led_rgb_sequence"`
+
+	// PanelType is empty for most panels, or "FM6126A" and "FM6127" for
+	// panels that need an initialization sequence.
+	PanelType string `toml:"panel_type"`
+
 	// Name of GPIO mapping used
 	HardwareMapping string `toml:"hardware_mapping"`
+
+	// PixelMapperConfig is passed to the library as is, for example
+	// "U-mapper;Rotate:90". It changes the geometry of the matrix, so it is
+	// not combined with the mapping of the config file.
+	PixelMapperConfig string `toml:"pixel_mapper_config"`
 }
 
 func (c *MatrixOptions) geometry() (width, height int) {
 	return c.Cols * c.ChainLength, c.Rows * c.Parallel
 }
+
+// hardwareMappings are the GPIO mappings of the library.
+var hardwareMappings = []string{"regular", "adafruit-hat", "adafruit-hat-pwm", "regular-pi1", "classic", "classic-pi1", "compute-module"}
+
+// multiplexings is the number of multiplexing types of the library besides
+// direct.
+const multiplexings = 20
+
+// Validate checks the options like the library does, and returns all
+// problems at once.
+func (c *MatrixOptions) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	check(c.Rows >= 8 && c.Rows <= 64 && c.Rows%2 == 0, "rows %d is not an even number between 8 and 64", c.Rows)
+	check(c.Cols >= 16, "cols %d is less than 16", c.Cols)
+	check(c.ChainLength >= 1, "chain_length %d is less than 1", c.ChainLength)
+	maxParallel := 3
+	if c.HardwareMapping == "compute-module" {
+		maxParallel = 6
+	}
+	check(c.Parallel >= 1 && c.Parallel <= maxParallel, "parallel %d is not between 1 and %d", c.Parallel, maxParallel)
+	check(c.PWMBits >= 1 && c.PWMBits <= 11, "pwm_bits %d is not between 1 and 11", c.PWMBits)
+	check(c.PWMLSBNanoseconds >= 50 && c.PWMLSBNanoseconds <= 3000, "pwm_lsb_nanoseconds %d is not between 50 and 3000", c.PWMLSBNanoseconds)
+	check(c.PWMDitherBits >= 0 && c.PWMDitherBits <= 2, "pwm_dither_bits %d is not between 0 and 2", c.PWMDitherBits)
+	check(c.Brightness >= 1 && c.Brightness <= 100, "brightness %d is not between 1 and 100", c.Brightness)
+	check(c.ScanMode == Progressive || c.ScanMode == Interlaced, "scan_mode %d is not 0 (progressive) or 1 (interlaced)", c.ScanMode)
+	check(c.RowAddressType >= 0 && c.RowAddressType <= 4, "row_address_type %d is not between 0 and 4", c.RowAddressType)
+	check(c.Multiplexing >= 0 && c.Multiplexing <= multiplexings, "multiplexing %d is not between 0 and %d", c.Multiplexing, multiplexings)
+	check(c.LimitRefreshRateHz >= 0, "limit_refresh_rate_hz %d is negative", c.LimitRefreshRateHz)
+	check(slices.Contains(hardwareMappings, c.HardwareMapping), "hardware_mapping %q is not one of %s", c.HardwareMapping, strings.Join(hardwareMappings, ", "))
+	sequence := strings.ToUpper(c.LEDRGBSequence)
+	check(len(sequence) == 3 && strings.Contains(sequence, "R") && strings.Contains(sequence, "G") && strings.Contains(sequence, "B"),
+		"led_rgb_sequence %q does not contain each of R, G and B once", c.LEDRGBSequence)
+	panelType := strings.ToUpper(c.PanelType)
+	check(panelType == "" || strings.HasPrefix(panelType, "FM6126") || strings.HasPrefix(panelType, "FM6127"),
+		"panel_type %q is not FM6126A or FM6127", c.PanelType)
+	return errors.Join(errs...)
+}
+
+// Validate checks the runtime options and returns all problems at once.
+func (r *RuntimeOptions) Validate() error {
+	var errs []error
+	if r.GPIOSlowdown < -1 || r.GPIOSlowdown > 5 {
+		errs = append(errs, fmt.Errorf("gpio_slowdown %d is not between -1 and 5", r.GPIOSlowdown))
+	}
+	if r.Daemon {
+		errs = append(errs, fmt.Errorf("daemon cannot fork the server, run it as a service instead"))
+	}
+	if !r.DoGPIOInit {
+		errs = append(errs, fmt.Errorf("do_gpio_init cannot be turned off through the C API of the library"))
+	}
+	return errors.Join(errs...)
+}
@@ -16,13 +16,55 @@ void set_disable_hardware_pulsing(struct RGBLedMatrixOptions *o, int disable_har
 void set_inverse_colors(struct RGBLedMatrixOptions *o, int inverse_colors) {
   o->inverse_colors = inverse_colors != 0 ? 1 : 0;
 }
+
+void set_disable_busy_waiting(struct RGBLedMatrixOptions *o, int disable_busy_waiting) {
+  o->disable_busy_waiting = disable_busy_waiting != 0 ? 1 : 0;
+}
+
+void set_do_gpio_init(struct RGBLedRuntimeOptions *o, int do_gpio_init) {
+  o->do_gpio_init = do_gpio_init != 0 ? 1 : 0;
+}
 */
 import "C"
 
+// cString returns s as a C string, or nil for an empty string so that the
+// library uses its default.
+func cString(s string) *C.char {
+	if s == "" {
+		return nil
+	}
+	return C.CString(s)
+}
+
 func (r *RuntimeOptions) toC() *C.struct_RGBLedRuntimeOptions {
-	return &C.struct_RGBLedRuntimeOptions{
-		gpio_slowdown: C.int(r.GPIOSlowdown),
+	o := &C.struct_RGBLedRuntimeOptions{
+		gpio_slowdown:   C.int(r.GPIOSlowdown),
+		drop_priv_user:  cString(r.DropPrivUser),
+		drop_priv_group: cString(r.DropPrivGroup),
 	}
+
+	// The library ignores options that are 0, so -1 keeps it from dropping
+	// privileges. A daemon of -1 would also keep it from starting the
+	// refresh thread.
+	if r.Daemon == true {
+		o.daemon = C.int(1)
+	} else {
+		o.daemon = C.int(0)
+	}
+
+	if r.DropPrivileges == true {
+		o.drop_privileges = C.int(1)
+	} else {
+		o.drop_privileges = C.int(-1)
+	}
+
+	if r.DoGPIOInit == true {
+		C.set_do_gpio_init(o, C.int(1))
+	} else {
+		C.set_do_gpio_init(o, C.int(0))
+	}
+
+	return o
 }
 
 func (c *MatrixOptions) toC() *C.struct_RGBLedMatrixOptions {
@@ -39,7 +81,10 @@ func (c *MatrixOptions) toC() *C.struct_RGBLedMatrixOptions {
 		row_address_type:      C.int(c.RowAddressType),
 		multiplexing:          C.int(c.Multiplexing),
 		limit_refresh_rate_hz: C.int(c.LimitRefreshRateHz),
-		hardware_mapping:      C.CString(c.HardwareMapping),
+		hardware_mapping:      cString(c.HardwareMapping),
+		led_rgb_sequence:      cString(c.LEDRGBSequence),
+		pixel_mapper_config:   cString(c.PixelMapperConfig),
+		panel_type:            cString(c.PanelType),
 	}
 
 	if c.ShowRefreshRate == true {
@@ -60,5 +105,11 @@ func (c *MatrixOptions) toC() *C.struct_RGBLedMatrixOptions {
 		C.set_inverse_colors(o, C.int(0))
 	}
 
+	if c.DisableBusyWaiting == true {
+		C.set_disable_busy_waiting(o, C.int(1))
+	} else {
+		C.set_disable_busy_waiting(o, C.int(0))
+	}
+
 	return o
 }
@@ -0,0 +1,56 @@
+package rgbmatrix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatrixOptionsValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig.Validate())
+
+	tests := []struct {
+		change func(*MatrixOptions)
+		err    string
+	}{
+		{func(o *MatrixOptions) { o.Rows = 33 }, "rows 33"},
+		{func(o *MatrixOptions) { o.Cols = 8 }, "cols 8"},
+		{func(o *MatrixOptions) { o.ChainLength = 0 }, "chain_length 0"},
+		{func(o *MatrixOptions) { o.Parallel = 4 }, "parallel 4 is not between 1 and 3"},
+		{func(o *MatrixOptions) { o.PWMBits = 0 }, "pwm_bits 0"},
+		{func(o *MatrixOptions) { o.PWMLSBNanoseconds = 10 }, "pwm_lsb_nanoseconds 10"},
+		{func(o *MatrixOptions) { o.PWMDitherBits = 3 }, "pwm_dither_bits 3"},
+		{func(o *MatrixOptions) { o.Brightness = 0 }, "brightness 0"},
+		{func(o *MatrixOptions) { o.ScanMode = 2 }, "scan_mode 2"},
+		{func(o *MatrixOptions) { o.RowAddressType = 5 }, "row_address_type 5"},
+		{func(o *MatrixOptions) { o.Multiplexing = 21 }, "multiplexing 21"},
+		{func(o *MatrixOptions) { o.LimitRefreshRateHz = -1 }, "limit_refresh_rate_hz -1"},
+		{func(o *MatrixOptions) { o.HardwareMapping = "hat" }, "hardware_mapping \"hat\""},
+		{func(o *MatrixOptions) { o.LEDRGBSequence = "RGBW" }, "led_rgb_sequence \"RGBW\""},
+		{func(o *MatrixOptions) { o.PanelType = "ICN2038" }, "panel_type \"ICN2038\""},
+	}
+	for _, test := range tests {
+		options := DefaultConfig
+		test.change(&options)
+		assert.ErrorContains(t, options.Validate(), test.err)
+	}
+
+	options := DefaultConfig
+	options.HardwareMapping = "compute-module"
+	options.Parallel = 6
+	options.LEDRGBSequence = "bgr"
+	assert.NoError(t, options.Validate())
+}
+
+func TestRuntimeOptionsValidate(t *testing.T) {
+	assert.NoError(t, DefaultRuntimeOptions.Validate())
+
+	options := DefaultRuntimeOptions
+	options.GPIOSlowdown = 6
+	options.Daemon = true
+	options.DoGPIOInit = false
+	err := options.Validate()
+	assert.ErrorContains(t, err, "gpio_slowdown 6")
+	assert.ErrorContains(t, err, "daemon")
+	assert.ErrorContains(t, err, "do_gpio_init")
+}
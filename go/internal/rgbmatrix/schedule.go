@@ -0,0 +1,69 @@
+package rgbmatrix
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/cron"
+)
+
+// ScheduleIDPattern matches the IDs of schedules, both in the config and in
+// the API.
+var ScheduleIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// Schedule shows content whenever its cron expression matches, for example
+// the clock at "0 7 * * *".
+type Schedule struct {
+	Cron string `toml:"cron"`
+	// Timezone is the IANA name of the zone that Cron is evaluated in, such
+	// as "Europe/Berlin". It defaults to the local time of the server.
+	Timezone string `toml:"timezone"`
+	// Type, Name and Params are the renderer and content, like in the API.
+	// The blank type turns the panels off and has no name.
+	Type   string            `toml:"type"`
+	Name   string            `toml:"name"`
+	Params map[string]string `toml:"params"`
+	// Temporary content is shown for Duration on top of the persistent
+	// content.
+	Temporary  bool            `toml:"temporary"`
+	Duration   Duration        `toml:"duration"`
+	Transition TransitionStyle `toml:"transition"`
+}
+
+// Location returns the time zone of the schedule.
+func (s Schedule) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+// Validate checks the expression and the time zone of the schedule. The
+// servers check its content against the catalog when they start.
+func (s Schedule) Validate() error {
+	if _, err := cron.Parse(s.Cron); err != nil {
+		return err
+	}
+	if _, err := s.Location(); err != nil {
+		return fmt.Errorf("timezone %q: %w", s.Timezone, err)
+	}
+	if s.Type == "" || (s.Name == "" && s.Type != "blank") {
+		return fmt.Errorf("type and name are both required")
+	}
+	if s.Duration < 0 || (s.Duration > 0 && !s.Temporary) {
+		return fmt.Errorf("only temporary content is shown for a duration")
+	}
+	return nil
+}
+
+// ScheduleIDs returns the sorted IDs of the schedules.
+func ScheduleIDs(schedules map[string]Schedule) []string {
+	ids := make([]string, 0, len(schedules))
+	for id := range schedules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -2,7 +2,6 @@ package rgbmatrix
 
 import (
 	"context"
-	"encoding/hex"
 	"image"
 	"image/color"
 	"image/draw"
@@ -37,8 +36,20 @@ func (s *Screen) WithTransform(f TransformFunc) *Screen {
 	return s
 }
 
+// Frame returns the back buffer of the screen. Renderers that draw every
+// frame draw into it and show it with Present.
+func (s *Screen) Frame() *image.RGBA {
+	return s.Canvas.Image()
+}
+
+// Present shows the back buffer. Unlike Canvas.Render it keeps the buffer,
+// so that the next frame can draw over the last one.
+func (s *Screen) Present() error {
+	return s.Canvas.m.Present(s.Frame())
+}
+
 func (s *Screen) Fill(color color.Color) {
-	draw.Draw(s.Canvas, s.Canvas.Bounds(), &image.Uniform{color}, image.Point{}, draw.Src)
+	draw.Draw(s.Frame(), s.Canvas.Bounds(), &image.Uniform{color}, image.Point{}, draw.Src)
 }
 
 func (s *Screen) ShowImage(ctx context.Context, i image.Image) error {
@@ -46,7 +57,7 @@ func (s *Screen) ShowImage(ctx context.Context, i image.Image) error {
 		i = s.Transform(i)
 	}
 
-	draw.Draw(s.Canvas, s.Canvas.Bounds(), i, image.Point{}, draw.Over)
+	draw.Draw(s.Frame(), s.Canvas.Bounds(), i, image.Point{}, draw.Over)
 
 	return s.Canvas.Render()
 }
@@ -56,7 +67,7 @@ func (s *Screen) ShowImageFor(ctx context.Context, i image.Image, d time.Duratio
 		i = s.Transform(i)
 	}
 
-	draw.Draw(s.Canvas, s.Canvas.Bounds(), i, image.Point{}, draw.Over)
+	draw.Draw(s.Frame(), s.Canvas.Bounds(), i, image.Point{}, draw.Over)
 	err := s.Canvas.Render()
 	if err != nil {
 		return err
@@ -116,32 +127,7 @@ func (s *Screen) PlayGIF(ctx context.Context, gif *gif.GIF) chan struct{} {
 
 // DrawText draws the given text onto the image at the specified coordinates with the given color.
 func (s *Screen) DrawText(font *BDFFont, text string, x, y int, color color.Color) {
-	cursorX := x
-	for _, ch := range text {
-		glyph := font.Glyphs[ch]
-		if glyph == nil {
-			cursorX += 6 // fallback spacing
-			continue
-		}
-		for row := 0; row < len(glyph.Bitmap); row++ {
-			line := glyph.Bitmap[row]
-			// Parse hex row data into bytes
-			rowData, _ := hex.DecodeString(line)
-			for col := 0; col < glyph.Width; col++ {
-				byteIndex := col / 8
-				bitIndex := 7 - (col % 8) // BDF stores MSB first
-
-				if byteIndex < len(rowData) && (rowData[byteIndex]&(1<<bitIndex)) != 0 {
-					px := cursorX + glyph.XOffset + col
-					py := y + row // + glyph.YOffset
-					if image.Pt(px, py).In(s.Canvas.Bounds()) {
-						s.Canvas.Set(px, py, color)
-					}
-				}
-			}
-		}
-		cursorX += glyph.DeviceWidth
-	}
+	DrawText(s.Frame(), font, text, x, y, color)
 }
 
 func fitCenter(img image.Image, width, height int, filter imaging.ResampleFilter) *image.NRGBA {
@@ -0,0 +1,310 @@
+package rgbmatrix
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+	"time"
+)
+
+var ErrInvalidTransition = errors.New("invalid transition")
+
+// TransitionStyle is how the frame that is shown blends into the frames of
+// the next renderer.
+type TransitionStyle int
+
+const (
+	// TransitionDefault uses the default style of the server.
+	TransitionDefault TransitionStyle = iota
+	TransitionNone
+	TransitionCrossfade
+	TransitionWipe
+	TransitionSlide
+	TransitionDissolve
+)
+
+func (s TransitionStyle) String() string {
+	switch s {
+	case TransitionDefault:
+		return "default"
+	case TransitionNone:
+		return "none"
+	case TransitionCrossfade:
+		return "crossfade"
+	case TransitionWipe:
+		return "wipe"
+	case TransitionSlide:
+		return "slide"
+	case TransitionDissolve:
+		return "dissolve"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseTransitionStyle returns the style with the given String form. An
+// empty name is TransitionDefault.
+func ParseTransitionStyle(name string) (TransitionStyle, error) {
+	if name == "" {
+		return TransitionDefault, nil
+	}
+	for s := TransitionDefault; s.String() != "unknown"; s++ {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q is not one of none, crossfade, wipe, slide or dissolve", ErrInvalidTransition, name)
+}
+
+func (s TransitionStyle) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *TransitionStyle) UnmarshalText(text []byte) error {
+	style, err := ParseTransitionStyle(string(text))
+	if err != nil {
+		return err
+	}
+	*s = style
+	return nil
+}
+
+const transitionFrameInterval = time.Second / 30
+
+// Transitions is a Matrix that blends the last shown frame into the frames
+// of the next renderer. The transition starts with the first frame rendered
+// after Begin and keeps animating when the renderer draws only once, like
+// static images do.
+type Transitions struct {
+	matrix Matrix
+	width  int
+	height int
+
+	mu     sync.Mutex
+	pixels []color.RGBA
+	// shown is the last frame passed to the wrapped matrix.
+	shown []color.RGBA
+	from  []color.RGBA
+	to    []color.RGBA
+	// frame is handed to the wrapped matrix.
+	frame *image.RGBA
+
+	active   bool
+	style    TransitionStyle
+	duration time.Duration
+	started  time.Time
+	// generation stops the animation of a transition that was replaced.
+	generation int
+	now        func() time.Time
+}
+
+func NewTransitions(matrix Matrix) *Transitions {
+	width, height := matrix.Geometry()
+	return &Transitions{
+		matrix: matrix,
+		width:  width,
+		height: height,
+		pixels: make([]color.RGBA, width*height),
+		shown:  make([]color.RGBA, width*height),
+		from:   make([]color.RGBA, width*height),
+		to:     make([]color.RGBA, width*height),
+		frame:  image.NewRGBA(image.Rect(0, 0, width, height)),
+		now:    time.Now,
+	}
+}
+
+// Begin captures the frame that is shown and blends it into the following
+// frames for the given duration. TransitionNone, TransitionDefault or a
+// non-positive duration switch immediately.
+func (t *Transitions) Begin(style TransitionStyle, duration time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.generation++
+	t.active = style != TransitionNone && style != TransitionDefault && duration > 0
+	if !t.active {
+		return
+	}
+	copy(t.from, t.shown)
+	t.style = style
+	t.duration = duration
+	t.started = time.Time{}
+}
+
+func (t *Transitions) Geometry() (width, height int) {
+	return t.width, t.height
+}
+
+func (t *Transitions) At(position int) color.Color {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.pixels[position]
+}
+
+func (t *Transitions) Set(position int, c color.Color) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pixels[position] = rgba(c)
+}
+
+func (t *Transitions) Apply(pixels []color.Color) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for position := range t.pixels {
+		pixel := color.RGBA{}
+		if position < len(pixels) {
+			pixel = rgba(pixels[position])
+		}
+		t.pixels[position] = pixel
+	}
+	return t.renderLocked()
+}
+
+func (t *Transitions) Render() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.renderLocked()
+}
+
+// Present passes img on to the wrapped matrix, or blends it in while a
+// transition is running.
+func (t *Transitions) Present(img *image.RGBA) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.active {
+		readFrame(t.pixels, t.width, t.height, img)
+		return t.renderLocked()
+	}
+	clear(t.pixels)
+	if err := t.matrix.Present(img); err != nil {
+		return err
+	}
+	readFrame(t.shown, t.width, t.height, img)
+	return nil
+}
+
+func (t *Transitions) renderLocked() error {
+	defer clear(t.pixels)
+	if !t.active {
+		return t.writeLocked(t.pixels)
+	}
+
+	copy(t.to, t.pixels)
+	if t.started.IsZero() {
+		t.started = t.now()
+		go t.animate(t.generation)
+	}
+	return t.blendLocked()
+}
+
+// animate renders the transition until it finished, so that it completes
+// even if the renderer does not draw again.
+func (t *Transitions) animate(generation int) {
+	ticker := time.NewTicker(transitionFrameInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		t.mu.Lock()
+		if generation != t.generation || !t.active {
+			t.mu.Unlock()
+			return
+		}
+		_ = t.blendLocked()
+		done := !t.active
+		t.mu.Unlock()
+		if done {
+			return
+		}
+	}
+}
+
+// blendLocked writes the transition frame for the current time and ends the
+// transition once its duration elapsed.
+func (t *Transitions) blendLocked() error {
+	progress := float64(t.now().Sub(t.started)) / float64(t.duration)
+	if progress >= 1 {
+		t.active = false
+		return t.writeLocked(t.to)
+	}
+	return t.writeLocked(blend(t.style, t.from, t.to, t.width, max(progress, 0)))
+}
+
+func (t *Transitions) writeLocked(frame []color.RGBA) error {
+	writeFrame(t.frame, frame)
+	if err := t.matrix.Present(t.frame); err != nil {
+		return err
+	}
+	copy(t.shown, frame)
+	return nil
+}
+
+func (t *Transitions) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.generation++
+	t.active = false
+	return t.matrix.Close()
+}
+
+// blend returns the frame at progress (0 to 1) of a transition from one
+// frame to another.
+func blend(style TransitionStyle, from, to []color.RGBA, width int, progress float64) []color.RGBA {
+	frame := make([]color.RGBA, len(to))
+	switch style {
+	case TransitionWipe:
+		edge := int(progress * float64(width))
+		for position := range frame {
+			if position%width < edge {
+				frame[position] = to[position]
+			} else {
+				frame[position] = from[position]
+			}
+		}
+	case TransitionSlide:
+		// The next frame pushes the shown one out to the left.
+		offset := int(progress * float64(width))
+		for position := range frame {
+			x, row := position%width, position-position%width
+			if x < width-offset {
+				frame[position] = from[row+x+offset]
+			} else {
+				frame[position] = to[row+x-(width-offset)]
+			}
+		}
+	case TransitionDissolve:
+		for position := range frame {
+			if dissolveThreshold(position) < progress {
+				frame[position] = to[position]
+			} else {
+				frame[position] = from[position]
+			}
+		}
+	default:
+		for position := range frame {
+			frame[position] = mix(from[position], to[position], progress)
+		}
+	}
+	return frame
+}
+
+// dissolveThreshold returns a stable pseudo-random value between 0 and 1 for
+// a pixel, so that pixels switch in a scattered but fixed order.
+func dissolveThreshold(position int) float64 {
+	hash := uint32(position)*2654435761 ^ 0x9e3779b9
+	hash ^= hash >> 16
+	hash *= 0x85ebca6b
+	hash ^= hash >> 13
+	return float64(hash) / float64(^uint32(0))
+}
+
+func mix(from, to color.RGBA, progress float64) color.RGBA {
+	channel := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*progress + 0.5)
+	}
+	return color.RGBA{
+		R: channel(from.R, to.R),
+		G: channel(from.G, to.G),
+		B: channel(from.B, to.B),
+		A: channel(from.A, to.A),
+	}
+}
@@ -0,0 +1,129 @@
+package rgbmatrix
+
+import (
+	"image/color"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	transitionRed  = color.RGBA{R: 255, A: 255}
+	transitionBlue = color.RGBA{B: 255, A: 255}
+)
+
+func TestTransitionsCrossfadeAndFinishWithoutNewFrames(t *testing.T) {
+	matrix := &observableMatrix{width: 1, height: 1, pixels: make([]color.Color, 1)}
+	transitions := NewTransitions(matrix)
+	var clock sync.Mutex
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	transitions.now = func() time.Time {
+		clock.Lock()
+		defer clock.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		clock.Lock()
+		defer clock.Unlock()
+		now = now.Add(d)
+	}
+	shown := func() color.Color {
+		transitions.mu.Lock()
+		defer transitions.mu.Unlock()
+		return matrix.pixels[0]
+	}
+
+	transitions.Set(0, transitionRed)
+	if err := transitions.Render(); err != nil {
+		t.Fatal(err)
+	}
+
+	transitions.Begin(TransitionCrossfade, time.Second)
+	transitions.Set(0, transitionBlue)
+	if err := transitions.Render(); err != nil {
+		t.Fatal(err)
+	}
+	if got := shown(); got != transitionRed {
+		t.Fatalf("transition did not start from the shown frame: %v", got)
+	}
+
+	advance(500 * time.Millisecond)
+	transitions.Set(0, transitionBlue)
+	if err := transitions.Render(); err != nil {
+		t.Fatal(err)
+	}
+	if got := shown(); got != (color.RGBA{R: 128, B: 128, A: 255}) {
+		t.Fatalf("unexpected crossfade frame: %v", got)
+	}
+
+	advance(time.Second)
+	deadline := time.Now().Add(time.Second)
+	for shown() != transitionBlue {
+		if time.Now().After(deadline) {
+			t.Fatalf("transition did not finish: %v", shown())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTransitionsWithoutStylePassThrough(t *testing.T) {
+	matrix := &observableMatrix{width: 1, height: 1, pixels: make([]color.Color, 1)}
+	transitions := NewTransitions(matrix)
+	transitions.Begin(TransitionDefault, time.Second)
+	transitions.Set(0, transitionBlue)
+	if err := transitions.Render(); err != nil {
+		t.Fatal(err)
+	}
+	if matrix.pixels[0] != transitionBlue {
+		t.Fatalf("frame was not passed through: %v", matrix.pixels[0])
+	}
+	if transitions.At(0) != (color.RGBA{}) {
+		t.Fatal("drawing buffer was not cleared after rendering")
+	}
+}
+
+func TestBlendStyles(t *testing.T) {
+	from := []color.RGBA{transitionRed, transitionRed, transitionRed, transitionRed}
+	to := []color.RGBA{transitionBlue, transitionBlue, transitionBlue, {G: 255, A: 255}}
+	tests := []struct {
+		style TransitionStyle
+		want  []color.RGBA
+	}{
+		{style: TransitionWipe, want: []color.RGBA{transitionBlue, transitionBlue, transitionRed, transitionRed}},
+		{style: TransitionSlide, want: []color.RGBA{transitionRed, transitionRed, transitionBlue, transitionBlue}},
+	}
+	for _, test := range tests {
+		got := blend(test.style, from, to, 4, 0.5)
+		for position := range got {
+			if got[position] != test.want[position] {
+				t.Errorf("%s: unexpected frame: got %v, want %v", test.style, got, test.want)
+				break
+			}
+		}
+	}
+
+	switched := 0
+	for position := range 1000 {
+		if dissolveThreshold(position) < 0.5 {
+			switched++
+		}
+	}
+	if switched < 400 || switched > 600 {
+		t.Fatalf("dissolve is not evenly distributed: %d of 1000 pixels switched halfway", switched)
+	}
+}
+
+func TestParseTransitionStyle(t *testing.T) {
+	for _, name := range []string{"none", "crossfade", "wipe", "slide", "dissolve"} {
+		style, err := ParseTransitionStyle(name)
+		if err != nil || style.String() != name {
+			t.Errorf("%s: unexpected style %v: %v", name, style, err)
+		}
+	}
+	if style, err := ParseTransitionStyle(""); err != nil || style != TransitionDefault {
+		t.Fatalf("empty name: unexpected style %v: %v", style, err)
+	}
+	if _, err := ParseTransitionStyle("spin"); err == nil {
+		t.Fatal("unknown style was accepted")
+	}
+}
@@ -4,7 +4,12 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/api"
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/display"
@@ -14,26 +19,135 @@ import (
 )
 
 func main() {
-	// Main context
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// Main context, cancelled by SIGINT or SIGTERM to shut down
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Update loop context, cancelled once the API drained, so that the
+	// requests in flight are still answered during the shutdown
+	loop, stopLoop := context.WithCancel(context.Background())
+	defer stopLoop()
 
 	// Communication channel
 	commands := make(chan renderers.Command)
 	frames := display.NewHub()
 
 	// Config
-	config := rgbmatrix.LoadConfig()
-	width := config.Options.Cols * config.Options.ChainLength
-	height := config.Options.Rows * config.Options.Parallel
-	log.Printf("server starting: matrix=%dx%d output=raspberry-pi", width, height)
+	configPath := flag.String("config", rgbmatrix.DefaultConfigPath, "path of the config file")
+	flag.Parse()
+	config, err := rgbmatrix.LoadConfigFile(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Keycloak
 	keycloak.Init(config.Auth.ClientID, config.Auth.ClientSecret)
 
-	// Start REST API and connect
-	go api.ListenAndServe(commands, frames)
+	// Matrix
+	matrix, err := rgbmatrix.NewRGBLedMatrix(&config.Options, &config.RuntimeOptions)
+	if err != nil {
+		log.Fatalf("create matrix: %v", err)
+	}
+	matrix, err = rgbmatrix.NewMapper(matrix, config.Mapping, config.Options)
+	if err != nil {
+		log.Fatalf("map matrix: %v", err)
+	}
+	width, height := matrix.Geometry()
+	log.Printf("server starting: matrix=%dx%d output=raspberry-pi", width, height)
+	observable := rgbmatrix.NewObservable(matrix, frames.Publish)
+	corrector, err := rgbmatrix.NewCorrector(observable, config.Color)
+	if err != nil {
+		log.Fatalf("correct colors: %v", err)
+	}
+
+	// Overlays drawn on top of the renderers
+	layers := rgbmatrix.NewLayers(corrector)
+	overlays := renderers.NewOverlays(layers, renderers.ImagesDir, renderers.FontsDir)
+	go overlays.Run(loop)
+
+	// Playlists
+	playlists := renderers.NewPlaylists(loop, commands)
+
+	// Sequences
+	sequences := renderers.NewSequences(loop, commands)
+
+	// Schedules of the config, run from now on
+	schedules := renderers.NewSchedules(commands, playlists)
+	if err := schedules.Load(config); err != nil {
+		log.Fatalf("schedules: %v", err)
+	}
+	if err := api.ValidateSchedules(schedules.List(), config.Layouts); err != nil {
+		log.Fatalf("schedules: %v", err)
+	}
+	go schedules.Run(loop)
+
+	// Display state saved across restarts
+	stateFile, err := renderers.LoadStateFile(config.Boot.StateFile)
+	if err != nil {
+		log.Printf("saved display state is ignored: %v", err)
+	}
+	if err := stateFile.Writable(); err != nil {
+		log.Print(err)
+	}
+	boot, err := renderers.BootCommand(config)
+	if err != nil {
+		log.Fatalf("boot content: %v", err)
+	}
+	playlists.SaveTo(stateFile)
+	brightness := stateFile.Dimmable(observable)
+
+	// Display state, owned by the update loop
+	state := renderers.NewState()
+
+	// Config reloads
+	reloader := renderers.NewReloader(*configPath, config, brightness, corrector)
+
+	// Start REST API and connect. A failing API shuts the server down.
+	served := make(chan error, 1)
+	services := api.Services{
+		Commands:   commands,
+		Frames:     frames,
+		Playlists:  playlists,
+		Brightness: brightness,
+		Color:      corrector,
+		State:      state,
+		Layouts:    config.Layouts,
+		Overlays:   overlays,
+		Reloader:   reloader,
+		Sequences:  sequences,
+		Schedules:  schedules,
+	}
+	go func() {
+		err := api.ListenAndServe(ctx, services, time.Duration(config.Shutdown.Timeout))
+		stop()
+		stopLoop()
+		served <- err
+	}()
+
+	// Restore the saved display state once the update loop runs. The API
+	// serves requests meanwhile, so that a restore that hangs does not keep
+	// it down.
+	go stateFile.Restore(loop, commands, playlists, observable, boot)
 
 	// Run the update loop
-	renderers.UpdateLoop(ctx, commands, config, frames)
+	renderers.UpdateLoopWithMatrix(loop, commands, layers, renderers.LoopOptions{
+		State:       state,
+		Config:      config,
+		Reloads:     reloader.Reloads(),
+		Withdrawals: sequences.Withdrawals(),
+		StateFile:   stateFile,
+		Overlays:    overlays,
+	})
+
+	// The update loop returned after the API drained, with the renderer
+	// stopped, the overlays removed and the panels blank. No request uses
+	// the matrix any more once it is closed.
+	err = <-served
+	if closeErr := layers.Close(); closeErr != nil {
+		log.Printf("close matrix: %v", closeErr)
+	}
+	if err != nil {
+		log.Fatalf("HTTP API: %v", err)
+	}
+	log.Printf("server stopped")
 }
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/api"
 	"github.com/tnolle/go-rpi-rgb-led-matrix/internal/display"
@@ -17,14 +18,20 @@ import (
 
 func main() {
 	showDisplay := flag.Bool("display", false, "render the LED matrix in this terminal")
+	configPath := flag.String("config", rgbmatrix.DefaultConfigPath, "path of the config file")
 	flag.Parse()
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	loop, stopLoop := context.WithCancel(context.Background())
+	defer stopLoop()
 
 	commands := make(chan renderers.Command)
 	frames := display.NewHub()
-	config := rgbmatrix.LoadConfig()
+	config, err := rgbmatrix.LoadConfigFile(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
 	keycloak.Init(config.Auth.ClientID, config.Auth.ClientSecret)
 	width := config.Options.Cols * config.Options.ChainLength
 	height := config.Options.Rows * config.Options.Parallel
@@ -37,6 +44,78 @@ func main() {
 		log.Printf("server starting: matrix=%dx%d output=headless", width, height)
 	}
 
-	go api.ListenAndServe(commands, frames)
-	renderers.UpdateLoopWithMatrix(ctx, commands, matrix, frames)
+	matrix, err = rgbmatrix.NewMapper(matrix, config.Mapping, config.Options)
+	if err != nil {
+		log.Fatalf("map matrix: %v", err)
+	}
+	observable := rgbmatrix.NewObservable(matrix, frames.Publish)
+	corrector, err := rgbmatrix.NewCorrector(observable, config.Color)
+	if err != nil {
+		log.Fatalf("correct colors: %v", err)
+	}
+	layers := rgbmatrix.NewLayers(corrector)
+	overlays := renderers.NewOverlays(layers, renderers.ImagesDir, renderers.FontsDir)
+	go overlays.Run(loop)
+	playlists := renderers.NewPlaylists(loop, commands)
+	sequences := renderers.NewSequences(loop, commands)
+	schedules := renderers.NewSchedules(commands, playlists)
+	if err := schedules.Load(config); err != nil {
+		log.Fatalf("schedules: %v", err)
+	}
+	if err := api.ValidateSchedules(schedules.List(), config.Layouts); err != nil {
+		log.Fatalf("schedules: %v", err)
+	}
+	go schedules.Run(loop)
+	stateFile, err := renderers.LoadStateFile(config.Boot.StateFile)
+	if err != nil {
+		log.Printf("saved display state is ignored: %v", err)
+	}
+	if err := stateFile.Writable(); err != nil {
+		log.Print(err)
+	}
+	boot, err := renderers.BootCommand(config)
+	if err != nil {
+		log.Fatalf("boot content: %v", err)
+	}
+	playlists.SaveTo(stateFile)
+	brightness := stateFile.Dimmable(observable)
+	state := renderers.NewState()
+	reloader := renderers.NewReloader(*configPath, config, brightness, corrector)
+	served := make(chan error, 1)
+	services := api.Services{
+		Commands:   commands,
+		Frames:     frames,
+		Playlists:  playlists,
+		Brightness: brightness,
+		Color:      corrector,
+		State:      state,
+		Layouts:    config.Layouts,
+		Overlays:   overlays,
+		Reloader:   reloader,
+		Sequences:  sequences,
+		Schedules:  schedules,
+	}
+	go func() {
+		err := api.ListenAndServe(ctx, services, time.Duration(config.Shutdown.Timeout))
+		stop()
+		stopLoop()
+		served <- err
+	}()
+	go stateFile.Restore(loop, commands, playlists, observable, boot)
+	renderers.UpdateLoopWithMatrix(loop, commands, layers, renderers.LoopOptions{
+		State:       state,
+		Config:      config,
+		Reloads:     reloader.Reloads(),
+		Withdrawals: sequences.Withdrawals(),
+		StateFile:   stateFile,
+		Overlays:    overlays,
+	})
+	err = <-served
+	if closeErr := layers.Close(); closeErr != nil {
+		log.Printf("close matrix: %v", closeErr)
+	}
+	if err != nil {
+		log.Fatalf("HTTP API: %v", err)
+	}
+	log.Printf("server stopped")
 }